@@ -1,10 +1,13 @@
 package api
 
 import (
+	"encoding/json"
+	"fmt"
 	"net/http"
 
 	"github.com/containous/mux"
 	assetfs "github.com/elazarl/go-bindata-assetfs"
+	"github.com/pteich/traefik/healthcheck"
 	"github.com/pteich/traefik/log"
 	"github.com/pteich/traefik/middlewares"
 	"github.com/pteich/traefik/safe"
@@ -24,6 +27,7 @@ type Handler struct {
 	Stats                 *thoas_stats.Stats         `json:"-" hash:"-"`
 	StatsRecorder         *middlewares.StatsRecorder `json:"-" hash:"-"`
 	DashboardAssets       *assetfs.AssetFS           `json:"-" hash:"-"`
+	HealthCheck           *healthcheck.HealthCheck   `json:"-" hash:"-"`
 }
 
 var (
@@ -52,6 +56,8 @@ func (p Handler) AddRoutes(router *mux.Router) {
 
 	// health route
 	router.Methods(http.MethodGet).Path("/health").HandlerFunc(p.getHealthHandler)
+	router.Methods(http.MethodGet).Path("/api/health/backends").HandlerFunc(p.getHealthBackendsHandler)
+	router.Methods(http.MethodGet).Path("/api/health/events").HandlerFunc(p.getHealthEventsHandler)
 
 	version.Handler{}.AddRoutes(router)
 
@@ -71,7 +77,7 @@ func getProviderIDFromVars(vars map[string]string) string {
 
 func (p Handler) getConfigHandler(response http.ResponseWriter, request *http.Request) {
 	currentConfigurations := p.CurrentConfigurations.Get().(types.Configurations)
-	err := templatesRenderer.JSON(response, http.StatusOK, currentConfigurations)
+	err := templatesRenderer.JSON(response, http.StatusOK, redactConfigurations(currentConfigurations))
 	if err != nil {
 		log.Error(err)
 	}
@@ -82,7 +88,7 @@ func (p Handler) getProviderHandler(response http.ResponseWriter, request *http.
 
 	currentConfigurations := p.CurrentConfigurations.Get().(types.Configurations)
 	if provider, ok := currentConfigurations[providerID]; ok {
-		err := templatesRenderer.JSON(response, http.StatusOK, provider)
+		err := templatesRenderer.JSON(response, http.StatusOK, redactConfiguration(provider))
 		if err != nil {
 			log.Error(err)
 		}
@@ -96,7 +102,7 @@ func (p Handler) getBackendsHandler(response http.ResponseWriter, request *http.
 
 	currentConfigurations := p.CurrentConfigurations.Get().(types.Configurations)
 	if provider, ok := currentConfigurations[providerID]; ok {
-		err := templatesRenderer.JSON(response, http.StatusOK, provider.Backends)
+		err := templatesRenderer.JSON(response, http.StatusOK, redactConfiguration(provider).Backends)
 		if err != nil {
 			log.Error(err)
 		}
@@ -113,7 +119,7 @@ func (p Handler) getBackendHandler(response http.ResponseWriter, request *http.R
 	currentConfigurations := p.CurrentConfigurations.Get().(types.Configurations)
 	if provider, ok := currentConfigurations[providerID]; ok {
 		if backend, ok := provider.Backends[backendID]; ok {
-			err := templatesRenderer.JSON(response, http.StatusOK, backend)
+			err := templatesRenderer.JSON(response, http.StatusOK, redactBackend(backend))
 			if err != nil {
 				log.Error(err)
 			}
@@ -250,3 +256,88 @@ func (p *Handler) getHealthHandler(response http.ResponseWriter, request *http.R
 		log.Error(err)
 	}
 }
+
+func (p Handler) getHealthBackendsHandler(response http.ResponseWriter, request *http.Request) {
+	statuses := make(map[string][]healthcheck.ServerStatus)
+	if p.HealthCheck != nil {
+		statuses = p.HealthCheck.BackendStatuses()
+	}
+
+	err := templatesRenderer.JSON(response, http.StatusOK, statuses)
+	if err != nil {
+		log.Error(err)
+	}
+}
+
+// getHealthEventsHandler streams the transitions of the servers as server-sent events, until the client disconnects.
+func (p Handler) getHealthEventsHandler(response http.ResponseWriter, request *http.Request) {
+	flusher, ok := response.(http.Flusher)
+	if !ok || p.HealthCheck == nil {
+		http.Error(response, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	events := p.HealthCheck.Subscribe()
+	defer p.HealthCheck.Unsubscribe(events)
+
+	response.Header().Set("Content-Type", "text/event-stream")
+	response.Header().Set("Cache-Control", "no-cache")
+	response.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-request.Context().Done():
+			return
+		case event := <-events:
+			data, err := json.Marshal(event)
+			if err != nil {
+				log.Error(err)
+				continue
+			}
+
+			if _, err := fmt.Fprintf(response, "event: %s\ndata: %s\n\n", event.State, data); err != nil {
+				log.Debugf("Unable to send health event: %v", err)
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// redactConfigurations returns a copy of the configurations without their secrets.
+func redactConfigurations(configurations types.Configurations) types.Configurations {
+	redacted := make(types.Configurations, len(configurations))
+	for providerID, configuration := range configurations {
+		redacted[providerID] = redactConfiguration(configuration)
+	}
+	return redacted
+}
+
+// redactConfiguration returns a copy of the configuration without the client keys of the backends.
+func redactConfiguration(configuration *types.Configuration) *types.Configuration {
+	if configuration == nil {
+		return nil
+	}
+
+	redacted := *configuration
+	redacted.Backends = make(map[string]*types.Backend, len(configuration.Backends))
+	for backendName, backend := range configuration.Backends {
+		redacted.Backends[backendName] = redactBackend(backend)
+	}
+	return &redacted
+}
+
+// redactBackend returns a copy of the backend without the client key of its transport.
+func redactBackend(backend *types.Backend) *types.Backend {
+	if backend == nil || backend.Transport == nil || len(backend.Transport.ClientKey) == 0 {
+		return backend
+	}
+
+	transport := *backend.Transport
+	transport.ClientKey = ""
+
+	redacted := *backend
+	redacted.Transport = &transport
+	return &redacted
+}
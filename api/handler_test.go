@@ -0,0 +1,131 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/containous/mux"
+	"github.com/pteich/traefik/healthcheck"
+	"github.com/pteich/traefik/metrics"
+	"github.com/pteich/traefik/safe"
+	"github.com/pteich/traefik/testhelpers"
+	"github.com/pteich/traefik/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+func TestHealthBackendsAndEvents(t *testing.T) {
+	backendServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer backendServer.Close()
+
+	hc := healthcheck.GetHealthCheck(metrics.NewVoidRegistry())
+
+	router := mux.NewRouter()
+	Handler{HealthCheck: hc}.AddRoutes(router)
+
+	apiServer := httptest.NewServer(router)
+	defer apiServer.Close()
+
+	resp, err := http.Get(apiServer.URL + "/api/health/events")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	lb, err := roundrobin.New(http.NotFoundHandler())
+	require.NoError(t, err)
+	err = lb.UpsertServer(testhelpers.MustParseURL(backendServer.URL))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	hc.SetBackendsConfiguration(ctx, map[string]*healthcheck.BackendConfig{
+		"backend": healthcheck.NewBackendConfig(healthcheck.Options{Path: "/health", Interval: time.Hour, LB: lb}, "backend"),
+	})
+
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+
+	var eventLines []string
+	for len(eventLines) < 2 {
+		select {
+		case line := <-lines:
+			eventLines = append(eventLines, line)
+		case <-time.After(5 * time.Second):
+			t.Fatal("no health event received")
+		}
+	}
+
+	assert.Equal(t, "event: down", eventLines[0])
+	require.True(t, strings.HasPrefix(eventLines[1], "data: "))
+
+	var event healthcheck.Event
+	err = json.Unmarshal([]byte(strings.TrimPrefix(eventLines[1], "data: ")), &event)
+	require.NoError(t, err)
+	assert.Equal(t, "backend", event.Backend)
+	assert.Equal(t, backendServer.URL, event.URL)
+	assert.Equal(t, healthcheck.StateDown, event.State)
+	assert.Contains(t, event.Error, "503")
+
+	resp, err = http.Get(apiServer.URL + "/api/health/backends")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var statuses map[string][]healthcheck.ServerStatus
+	err = json.NewDecoder(resp.Body).Decode(&statuses)
+	require.NoError(t, err)
+
+	require.Len(t, statuses["backend"], 1)
+	assert.Equal(t, backendServer.URL, statuses["backend"][0].URL)
+	assert.Equal(t, healthcheck.StateDown, statuses["backend"][0].State)
+	assert.Contains(t, statuses["backend"][0].LastError, "503")
+}
+
+func TestBackendClientKeyRedacted(t *testing.T) {
+	configurations := types.Configurations{
+		"file": &types.Configuration{
+			Backends: map[string]*types.Backend{
+				"backend": {
+					Transport: &types.Transport{
+						ClientCert: "client.crt",
+						ClientKey:  "secret-client-key",
+					},
+				},
+			},
+		},
+	}
+
+	router := mux.NewRouter()
+	Handler{CurrentConfigurations: safe.New(configurations)}.AddRoutes(router)
+
+	for _, path := range []string{"/api", "/api/providers/file", "/api/providers/file/backends", "/api/providers/file/backends/backend"} {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+
+		assert.Equal(t, http.StatusOK, recorder.Code, path)
+		assert.Contains(t, recorder.Body.String(), "client.crt", path)
+		assert.NotContains(t, recorder.Body.String(), "secret-client-key", path)
+	}
+
+	// The current configuration is not altered.
+	assert.Equal(t, "secret-client-key", string(configurations["file"].Backends["backend"].Transport.ClientKey))
+}
@@ -156,6 +156,8 @@ var _templatesConsul_catalogTmpl = []byte(`[backends]
   [backends."backend-{{ $backendName }}".loadBalancer]
     method = "{{ $loadBalancer.Method }}"
     sticky = {{ $loadBalancer.Sticky }}
+    hashKey = "{{ $loadBalancer.HashKey }}"
+    slowStart = "{{ $loadBalancer.SlowStart }}"
     {{if $loadBalancer.Stickiness }}
     [backends."backend-{{ $backendName }}".loadBalancer.stickiness]
       cookieName = "{{ $loadBalancer.Stickiness.CookieName }}"
@@ -170,16 +172,31 @@ var _templatesConsul_catalogTmpl = []byte(`[backends]
   [backends."backend-{{ $backendName }}".maxConn]
     extractorFunc = "{{ $maxConn.ExtractorFunc }}"
     amount = {{ $maxConn.Amount }}
+    queueLength = {{ $maxConn.QueueLength }}
+    queueTimeout = "{{ $maxConn.QueueTimeout }}"
+    priorityHeader = "{{ $maxConn.PriorityHeader }}"
   {{end}}
 
   {{ $healthCheck := getHealthCheck $service.TraefikLabels }}
   {{if $healthCheck }}
   [backends."backend-{{ $backendName }}".healthCheck]
+    type = "{{ $healthCheck.Type }}"
+    service = "{{ $healthCheck.Service }}"
     scheme = "{{ $healthCheck.Scheme }}"
     path = "{{ $healthCheck.Path }}"
     port = {{ $healthCheck.Port }}
     interval = "{{ $healthCheck.Interval }}"
     hostname = "{{ $healthCheck.Hostname }}"
+    method = "{{ $healthCheck.Method }}"
+    {{if $healthCheck.StatusCodes }}
+    statusCodes = [{{range $healthCheck.StatusCodes }}
+      "{{.}}",
+      {{end}}]
+    {{end}}
+    bodyRegex = "{{ $healthCheck.BodyRegex }}"
+    timeout = "{{ $healthCheck.Timeout }}"
+    rise = {{ $healthCheck.Rise }}
+    fall = {{ $healthCheck.Fall }}
     {{if $healthCheck.Headers }}
     [backends."backend-{{ $backendName }}".healthCheck.headers]
       {{range $k, $v := $healthCheck.Headers }}
@@ -198,12 +215,82 @@ var _templatesConsul_catalogTmpl = []byte(`[backends]
     retryExpression = "{{ $buffering.RetryExpression }}"
   {{end}}
 
+  {{ $passiveHealthCheck := getPassiveHealthCheck $service.TraefikLabels }}
+  {{if $passiveHealthCheck }}
+  [backends."backend-{{ $backendName }}".passiveHealthCheck]
+    consecutiveErrors = {{ $passiveHealthCheck.ConsecutiveErrors }}
+    ejectionTime = "{{ $passiveHealthCheck.EjectionTime }}"
+    maxEjectionPercent = {{ $passiveHealthCheck.MaxEjectionPercent }}
+  {{end}}
+
+  {{ $transport := getTransport $service.TraefikLabels }}
+  {{if $transport }}
+  [backends."backend-{{ $backendName }}".transport]
+    dialTimeout = "{{ $transport.DialTimeout }}"
+    responseHeaderTimeout = "{{ $transport.ResponseHeaderTimeout }}"
+    idleConnTimeout = "{{ $transport.IdleConnTimeout }}"
+    maxIdleConnsPerHost = {{ $transport.MaxIdleConnsPerHost }}
+    serverName = "{{ $transport.ServerName }}"
+    rootCAs = [{{range $transport.RootCAs }}
+      "{{.}}",
+      {{end}}]
+    clientCert = """{{ $transport.ClientCert }}"""
+    clientKey = """{{ $transport.ClientKey }}"""
+    {{if $transport.InsecureSkipVerify }}
+    insecureSkipVerify = {{ $transport.InsecureSkipVerify }}
+    {{end}}
+    disableHTTP2 = {{ $transport.DisableHTTP2 }}
+  {{end}}
+
+  {{ $retry := getRetry $service.TraefikLabels }}
+  {{if $retry }}
+  [backends."backend-{{ $backendName }}".retry]
+    attempts = {{ $retry.Attempts }}
+    statusCodes = [{{range $retry.StatusCodes }}
+      "{{.}}",
+      {{end}}]
+    methods = [{{range $retry.Methods }}
+      "{{.}}",
+      {{end}}]
+    initialInterval = "{{ $retry.InitialInterval }}"
+    maxInterval = "{{ $retry.MaxInterval }}"
+    perTryTimeout = "{{ $retry.PerTryTimeout }}"
+    budgetPercent = {{ $retry.BudgetPercent }}
+  {{end}}
+
+  {{ $hedging := getHedging $service.TraefikLabels }}
+  {{if $hedging }}
+  [backends."backend-{{ $backendName }}".hedging]
+    delay = "{{ $hedging.Delay }}"
+    percentile = {{ $hedging.Percentile }}
+  {{end}}
+
+  {{ $adaptiveConcurrency := getAdaptiveConcurrency $service.TraefikLabels }}
+  {{if $adaptiveConcurrency }}
+  [backends."backend-{{ $backendName }}".adaptiveConcurrency]
+    algorithm = "{{ $adaptiveConcurrency.Algorithm }}"
+    initialLimit = {{ $adaptiveConcurrency.InitialLimit }}
+    minLimit = {{ $adaptiveConcurrency.MinLimit }}
+    maxLimit = {{ $adaptiveConcurrency.MaxLimit }}
+    latencyThreshold = "{{ $adaptiveConcurrency.LatencyThreshold }}"
+  {{end}}
+
+  {{ $dnsDiscovery := getDNSDiscovery $service.TraefikLabels }}
+  {{if $dnsDiscovery }}
+  [backends."backend-{{ $backendName }}".dnsDiscovery]
+    name = "{{ $dnsDiscovery.Name }}"
+    port = {{ $dnsDiscovery.Port }}
+    scheme = "{{ $dnsDiscovery.Scheme }}"
+    interval = "{{ $dnsDiscovery.Interval }}"
+  {{end}}
+
 {{end}}
 {{range $index, $node := .Nodes}}
   {{ $server := getServer $node }}
   [backends."backend-{{ getNodeBackendName $node }}".servers."{{ getServerName $node $index }}"]
     url = "{{ $server.URL }}"
     weight = {{ $server.Weight }}
+    priority = {{ $server.Priority }}
 
 {{end}}
 
@@ -334,6 +421,30 @@ var _templatesConsul_catalogTmpl = []byte(`[backends]
       {{end}}
     {{end}}
 
+    {{ $weightedBackends := getWeightedBackends $service.TraefikLabels }}
+    {{range $weightedBackends }}
+    [[frontends."frontend-{{ $service.ServiceName }}".backends]]
+      name = "backend-{{ .Name }}"
+      weight = {{ .Weight }}
+    {{end}}
+
+    {{ $backendsStickiness := getBackendsStickiness $service.TraefikLabels }}
+    {{if $backendsStickiness }}
+    [frontends."frontend-{{ $service.ServiceName }}".backendsStickiness]
+      cookieName = "{{ $backendsStickiness.CookieName }}"
+      secure = {{ $backendsStickiness.Secure }}
+      httpOnly = {{ $backendsStickiness.HTTPOnly }}
+      sameSite = "{{ $backendsStickiness.SameSite }}"
+    {{end}}
+
+    {{ $mirror := getMirror $service.TraefikLabels }}
+    {{if $mirror }}
+    [frontends."frontend-{{ $service.ServiceName }}".mirror]
+      backend = "backend-{{ $mirror.Backend }}"
+      percent = {{ $mirror.Percent }}
+      maxBodySize = {{ $mirror.MaxBodySize }}
+    {{end}}
+
     {{ $rateLimit := getRateLimit $service.TraefikLabels }}
     {{if $rateLimit }}
     [frontends."frontend-{{ $service.ServiceName }}".rateLimit]
@@ -654,6 +765,8 @@ var _templatesDockerTmpl = []byte(`{{$backendServers := .Servers}}
     [backends."backend-{{ $backendName }}".loadBalancer]
       method = "{{ $loadBalancer.Method }}"
       sticky = {{ $loadBalancer.Sticky }}
+      hashKey = "{{ $loadBalancer.HashKey }}"
+      slowStart = "{{ $loadBalancer.SlowStart }}"
       {{if $loadBalancer.Stickiness }}
       [backends."backend-{{ $backendName }}".loadBalancer.stickiness]
         cookieName = "{{ $loadBalancer.Stickiness.CookieName }}"
@@ -668,16 +781,31 @@ var _templatesDockerTmpl = []byte(`{{$backendServers := .Servers}}
   [backends."backend-{{ $backendName }}".maxConn]
     extractorFunc = "{{ $maxConn.ExtractorFunc }}"
     amount = {{ $maxConn.Amount }}
+    queueLength = {{ $maxConn.QueueLength }}
+    queueTimeout = "{{ $maxConn.QueueTimeout }}"
+    priorityHeader = "{{ $maxConn.PriorityHeader }}"
   {{end}}
 
   {{ $healthCheck := getHealthCheck $backend.SegmentLabels }}
   {{if $healthCheck }}
   [backends."backend-{{ $backendName }}".healthCheck]
+    type = "{{ $healthCheck.Type }}"
+    service = "{{ $healthCheck.Service }}"
     scheme = "{{ $healthCheck.Scheme }}"
     path = "{{ $healthCheck.Path }}"
     port = {{ $healthCheck.Port }}
     interval = "{{ $healthCheck.Interval }}"
     hostname = "{{ $healthCheck.Hostname }}"
+    method = "{{ $healthCheck.Method }}"
+    {{if $healthCheck.StatusCodes }}
+    statusCodes = [{{range $healthCheck.StatusCodes }}
+      "{{.}}",
+      {{end}}]
+    {{end}}
+    bodyRegex = "{{ $healthCheck.BodyRegex }}"
+    timeout = "{{ $healthCheck.Timeout }}"
+    rise = {{ $healthCheck.Rise }}
+    fall = {{ $healthCheck.Fall }}
     {{if $healthCheck.Headers }}
     [backends."backend-{{ $backendName }}".healthCheck.headers]
       {{range $k, $v := $healthCheck.Headers }}
@@ -696,10 +824,80 @@ var _templatesDockerTmpl = []byte(`{{$backendServers := .Servers}}
     retryExpression = "{{ $buffering.RetryExpression }}"
   {{end}}
 
+  {{ $passiveHealthCheck := getPassiveHealthCheck $backend.SegmentLabels }}
+  {{if $passiveHealthCheck }}
+  [backends."backend-{{ $backendName }}".passiveHealthCheck]
+    consecutiveErrors = {{ $passiveHealthCheck.ConsecutiveErrors }}
+    ejectionTime = "{{ $passiveHealthCheck.EjectionTime }}"
+    maxEjectionPercent = {{ $passiveHealthCheck.MaxEjectionPercent }}
+  {{end}}
+
+  {{ $transport := getTransport $backend.SegmentLabels }}
+  {{if $transport }}
+  [backends."backend-{{ $backendName }}".transport]
+    dialTimeout = "{{ $transport.DialTimeout }}"
+    responseHeaderTimeout = "{{ $transport.ResponseHeaderTimeout }}"
+    idleConnTimeout = "{{ $transport.IdleConnTimeout }}"
+    maxIdleConnsPerHost = {{ $transport.MaxIdleConnsPerHost }}
+    serverName = "{{ $transport.ServerName }}"
+    rootCAs = [{{range $transport.RootCAs }}
+      "{{.}}",
+      {{end}}]
+    clientCert = """{{ $transport.ClientCert }}"""
+    clientKey = """{{ $transport.ClientKey }}"""
+    {{if $transport.InsecureSkipVerify }}
+    insecureSkipVerify = {{ $transport.InsecureSkipVerify }}
+    {{end}}
+    disableHTTP2 = {{ $transport.DisableHTTP2 }}
+  {{end}}
+
+  {{ $retry := getRetry $backend.SegmentLabels }}
+  {{if $retry }}
+  [backends."backend-{{ $backendName }}".retry]
+    attempts = {{ $retry.Attempts }}
+    statusCodes = [{{range $retry.StatusCodes }}
+      "{{.}}",
+      {{end}}]
+    methods = [{{range $retry.Methods }}
+      "{{.}}",
+      {{end}}]
+    initialInterval = "{{ $retry.InitialInterval }}"
+    maxInterval = "{{ $retry.MaxInterval }}"
+    perTryTimeout = "{{ $retry.PerTryTimeout }}"
+    budgetPercent = {{ $retry.BudgetPercent }}
+  {{end}}
+
+  {{ $hedging := getHedging $backend.SegmentLabels }}
+  {{if $hedging }}
+  [backends."backend-{{ $backendName }}".hedging]
+    delay = "{{ $hedging.Delay }}"
+    percentile = {{ $hedging.Percentile }}
+  {{end}}
+
+  {{ $adaptiveConcurrency := getAdaptiveConcurrency $backend.SegmentLabels }}
+  {{if $adaptiveConcurrency }}
+  [backends."backend-{{ $backendName }}".adaptiveConcurrency]
+    algorithm = "{{ $adaptiveConcurrency.Algorithm }}"
+    initialLimit = {{ $adaptiveConcurrency.InitialLimit }}
+    minLimit = {{ $adaptiveConcurrency.MinLimit }}
+    maxLimit = {{ $adaptiveConcurrency.MaxLimit }}
+    latencyThreshold = "{{ $adaptiveConcurrency.LatencyThreshold }}"
+  {{end}}
+
+  {{ $dnsDiscovery := getDNSDiscovery $backend.SegmentLabels }}
+  {{if $dnsDiscovery }}
+  [backends."backend-{{ $backendName }}".dnsDiscovery]
+    name = "{{ $dnsDiscovery.Name }}"
+    port = {{ $dnsDiscovery.Port }}
+    scheme = "{{ $dnsDiscovery.Scheme }}"
+    interval = "{{ $dnsDiscovery.Interval }}"
+  {{end}}
+
   {{range $serverName, $server := getServers $servers }}
   [backends."backend-{{ $backendName }}".servers."{{ $serverName }}"]
     url = "{{ $server.URL }}"
     weight = {{ $server.Weight }}
+    priority = {{ $server.Priority }}
   {{end}}
 
 {{end}}
@@ -832,6 +1030,30 @@ var _templatesDockerTmpl = []byte(`{{$backendServers := .Servers}}
       {{end}}
     {{end}}
 
+    {{ $weightedBackends := getWeightedBackends $container.SegmentLabels }}
+    {{range $weightedBackends }}
+    [[frontends."frontend-{{ $frontendName }}".backends]]
+      name = "backend-{{ .Name }}"
+      weight = {{ .Weight }}
+    {{end}}
+
+    {{ $backendsStickiness := getBackendsStickiness $container.SegmentLabels }}
+    {{if $backendsStickiness }}
+    [frontends."frontend-{{ $frontendName }}".backendsStickiness]
+      cookieName = "{{ $backendsStickiness.CookieName }}"
+      secure = {{ $backendsStickiness.Secure }}
+      httpOnly = {{ $backendsStickiness.HTTPOnly }}
+      sameSite = "{{ $backendsStickiness.SameSite }}"
+    {{end}}
+
+    {{ $mirror := getMirror $container.SegmentLabels }}
+    {{if $mirror }}
+    [frontends."frontend-{{ $frontendName }}".mirror]
+      backend = "backend-{{ $mirror.Backend }}"
+      percent = {{ $mirror.Percent }}
+      maxBodySize = {{ $mirror.MaxBodySize }}
+    {{end}}
+
     {{ $rateLimit := getRateLimit $container.SegmentLabels }}
     {{if $rateLimit }}
     [frontends."frontend-{{ $frontendName }}".rateLimit]
@@ -1003,6 +1225,8 @@ var _templatesEcsTmpl = []byte(`[backends]
   [backends."backend-{{ $serviceName }}".loadBalancer]
     method = "{{ $loadBalancer.Method }}"
     sticky = {{ $loadBalancer.Sticky }}
+    hashKey = "{{ $loadBalancer.HashKey }}"
+    slowStart = "{{ $loadBalancer.SlowStart }}"
     {{if $loadBalancer.Stickiness }}
     [backends."backend-{{ $serviceName }}".loadBalancer.stickiness]
       cookieName = "{{ $loadBalancer.Stickiness.CookieName }}"
@@ -1017,16 +1241,31 @@ var _templatesEcsTmpl = []byte(`[backends]
   [backends."backend-{{ $serviceName }}".maxConn]
     extractorFunc = "{{ $maxConn.ExtractorFunc }}"
     amount = {{ $maxConn.Amount }}
+    queueLength = {{ $maxConn.QueueLength }}
+    queueTimeout = "{{ $maxConn.QueueTimeout }}"
+    priorityHeader = "{{ $maxConn.PriorityHeader }}"
   {{end}}
 
   {{ $healthCheck := getHealthCheck $firstInstance.SegmentLabels }}
   {{if $healthCheck }}
   [backends."backend-{{ $serviceName }}".healthCheck]
+    type = "{{ $healthCheck.Type }}"
+    service = "{{ $healthCheck.Service }}"
     scheme = "{{ $healthCheck.Scheme }}"
     path = "{{ $healthCheck.Path }}"
     port = {{ $healthCheck.Port }}
     interval = "{{ $healthCheck.Interval }}"
     hostname = "{{ $healthCheck.Hostname }}"
+    method = "{{ $healthCheck.Method }}"
+    {{if $healthCheck.StatusCodes }}
+    statusCodes = [{{range $healthCheck.StatusCodes }}
+      "{{.}}",
+      {{end}}]
+    {{end}}
+    bodyRegex = "{{ $healthCheck.BodyRegex }}"
+    timeout = "{{ $healthCheck.Timeout }}"
+    rise = {{ $healthCheck.Rise }}
+    fall = {{ $healthCheck.Fall }}
     {{if $healthCheck.Headers }}
     [backends."backend-{{ $serviceName }}".healthCheck.headers]
       {{range $k, $v := $healthCheck.Headers }}
@@ -1045,10 +1284,80 @@ var _templatesEcsTmpl = []byte(`[backends]
     retryExpression = "{{ $buffering.RetryExpression }}"
   {{end}}
 
+  {{ $passiveHealthCheck := getPassiveHealthCheck $firstInstance.SegmentLabels }}
+  {{if $passiveHealthCheck }}
+  [backends."backend-{{ $serviceName }}".passiveHealthCheck]
+    consecutiveErrors = {{ $passiveHealthCheck.ConsecutiveErrors }}
+    ejectionTime = "{{ $passiveHealthCheck.EjectionTime }}"
+    maxEjectionPercent = {{ $passiveHealthCheck.MaxEjectionPercent }}
+  {{end}}
+
+  {{ $transport := getTransport $firstInstance.SegmentLabels }}
+  {{if $transport }}
+  [backends."backend-{{ $serviceName }}".transport]
+    dialTimeout = "{{ $transport.DialTimeout }}"
+    responseHeaderTimeout = "{{ $transport.ResponseHeaderTimeout }}"
+    idleConnTimeout = "{{ $transport.IdleConnTimeout }}"
+    maxIdleConnsPerHost = {{ $transport.MaxIdleConnsPerHost }}
+    serverName = "{{ $transport.ServerName }}"
+    rootCAs = [{{range $transport.RootCAs }}
+      "{{.}}",
+      {{end}}]
+    clientCert = """{{ $transport.ClientCert }}"""
+    clientKey = """{{ $transport.ClientKey }}"""
+    {{if $transport.InsecureSkipVerify }}
+    insecureSkipVerify = {{ $transport.InsecureSkipVerify }}
+    {{end}}
+    disableHTTP2 = {{ $transport.DisableHTTP2 }}
+  {{end}}
+
+  {{ $retry := getRetry $firstInstance.SegmentLabels }}
+  {{if $retry }}
+  [backends."backend-{{ $serviceName }}".retry]
+    attempts = {{ $retry.Attempts }}
+    statusCodes = [{{range $retry.StatusCodes }}
+      "{{.}}",
+      {{end}}]
+    methods = [{{range $retry.Methods }}
+      "{{.}}",
+      {{end}}]
+    initialInterval = "{{ $retry.InitialInterval }}"
+    maxInterval = "{{ $retry.MaxInterval }}"
+    perTryTimeout = "{{ $retry.PerTryTimeout }}"
+    budgetPercent = {{ $retry.BudgetPercent }}
+  {{end}}
+
+  {{ $hedging := getHedging $firstInstance.SegmentLabels }}
+  {{if $hedging }}
+  [backends."backend-{{ $serviceName }}".hedging]
+    delay = "{{ $hedging.Delay }}"
+    percentile = {{ $hedging.Percentile }}
+  {{end}}
+
+  {{ $adaptiveConcurrency := getAdaptiveConcurrency $firstInstance.SegmentLabels }}
+  {{if $adaptiveConcurrency }}
+  [backends."backend-{{ $serviceName }}".adaptiveConcurrency]
+    algorithm = "{{ $adaptiveConcurrency.Algorithm }}"
+    initialLimit = {{ $adaptiveConcurrency.InitialLimit }}
+    minLimit = {{ $adaptiveConcurrency.MinLimit }}
+    maxLimit = {{ $adaptiveConcurrency.MaxLimit }}
+    latencyThreshold = "{{ $adaptiveConcurrency.LatencyThreshold }}"
+  {{end}}
+
+  {{ $dnsDiscovery := getDNSDiscovery $firstInstance.SegmentLabels }}
+  {{if $dnsDiscovery }}
+  [backends."backend-{{ $serviceName }}".dnsDiscovery]
+    name = "{{ $dnsDiscovery.Name }}"
+    port = {{ $dnsDiscovery.Port }}
+    scheme = "{{ $dnsDiscovery.Scheme }}"
+    interval = "{{ $dnsDiscovery.Interval }}"
+  {{end}}
+
   {{range $serverName, $server := getServers $instances }}
   [backends."backend-{{ $serviceName }}".servers."{{ $serverName }}"]
     url = "{{ $server.URL }}"
     weight = {{ $server.Weight }}
+    priority = {{ $server.Priority }}
   {{end}}
 
 {{end}}
@@ -1183,6 +1492,30 @@ var _templatesEcsTmpl = []byte(`[backends]
       {{end}}
     {{end}}
 
+    {{ $weightedBackends := getWeightedBackends $instance.SegmentLabels }}
+    {{range $weightedBackends }}
+    [[frontends."frontend-{{ $frontendName }}".backends]]
+      name = "backend-{{ .Name }}"
+      weight = {{ .Weight }}
+    {{end}}
+
+    {{ $backendsStickiness := getBackendsStickiness $instance.SegmentLabels }}
+    {{if $backendsStickiness }}
+    [frontends."frontend-{{ $frontendName }}".backendsStickiness]
+      cookieName = "{{ $backendsStickiness.CookieName }}"
+      secure = {{ $backendsStickiness.Secure }}
+      httpOnly = {{ $backendsStickiness.HTTPOnly }}
+      sameSite = "{{ $backendsStickiness.SameSite }}"
+    {{end}}
+
+    {{ $mirror := getMirror $instance.SegmentLabels }}
+    {{if $mirror }}
+    [frontends."frontend-{{ $frontendName }}".mirror]
+      backend = "backend-{{ $mirror.Backend }}"
+      percent = {{ $mirror.Percent }}
+      maxBodySize = {{ $mirror.MaxBodySize }}
+    {{end}}
+
     {{ $rateLimit := getRateLimit $instance.SegmentLabels }}
     {{if $rateLimit }}
     [frontends."frontend-{{ $frontendName }}".rateLimit]
@@ -1591,6 +1924,8 @@ var _templatesKvTmpl = []byte(`[backends]
     [backends."{{ $backendName }}".loadBalancer]
       method = "{{ $loadBalancer.Method }}"
       sticky = {{ $loadBalancer.Sticky }}
+      hashKey = "{{ $loadBalancer.HashKey }}"
+      slowStart = "{{ $loadBalancer.SlowStart }}"
       {{if $loadBalancer.Stickiness }}
       [backends."{{ $backendName }}".loadBalancer.stickiness]
         cookieName = "{{ $loadBalancer.Stickiness.CookieName }}"
@@ -1605,16 +1940,31 @@ var _templatesKvTmpl = []byte(`[backends]
   [backends."{{ $backendName }}".maxConn]
     extractorFunc = "{{ $maxConn.ExtractorFunc }}"
     amount = {{ $maxConn.Amount }}
+    queueLength = {{ $maxConn.QueueLength }}
+    queueTimeout = "{{ $maxConn.QueueTimeout }}"
+    priorityHeader = "{{ $maxConn.PriorityHeader }}"
   {{end}}
 
   {{ $healthCheck := getHealthCheck $backend }}
   {{if $healthCheck }}
   [backends."{{ $backendName }}".healthCheck]
+    type = "{{ $healthCheck.Type }}"
+    service = "{{ $healthCheck.Service }}"
     scheme = "{{ $healthCheck.Scheme }}"
     path = "{{ $healthCheck.Path }}"
     port = {{ $healthCheck.Port }}
     interval = "{{ $healthCheck.Interval }}"
     hostname = "{{ $healthCheck.Hostname }}"
+    method = "{{ $healthCheck.Method }}"
+    {{if $healthCheck.StatusCodes }}
+    statusCodes = [{{range $healthCheck.StatusCodes }}
+      "{{.}}",
+      {{end}}]
+    {{end}}
+    bodyRegex = "{{ $healthCheck.BodyRegex }}"
+    timeout = "{{ $healthCheck.Timeout }}"
+    rise = {{ $healthCheck.Rise }}
+    fall = {{ $healthCheck.Fall }}
     {{if $healthCheck.Headers }}
     [backends."{{ $backendName }}".healthCheck.headers]
       {{range $k, $v := $healthCheck.Headers }}
@@ -1633,10 +1983,80 @@ var _templatesKvTmpl = []byte(`[backends]
     retryExpression = "{{ $buffering.RetryExpression }}"
   {{end}}
 
+  {{ $passiveHealthCheck := getPassiveHealthCheck $backend }}
+  {{if $passiveHealthCheck }}
+  [backends."{{ $backendName }}".passiveHealthCheck]
+    consecutiveErrors = {{ $passiveHealthCheck.ConsecutiveErrors }}
+    ejectionTime = "{{ $passiveHealthCheck.EjectionTime }}"
+    maxEjectionPercent = {{ $passiveHealthCheck.MaxEjectionPercent }}
+  {{end}}
+
+  {{ $transport := getTransport $backend }}
+  {{if $transport }}
+  [backends."{{ $backendName }}".transport]
+    dialTimeout = "{{ $transport.DialTimeout }}"
+    responseHeaderTimeout = "{{ $transport.ResponseHeaderTimeout }}"
+    idleConnTimeout = "{{ $transport.IdleConnTimeout }}"
+    maxIdleConnsPerHost = {{ $transport.MaxIdleConnsPerHost }}
+    serverName = "{{ $transport.ServerName }}"
+    rootCAs = [{{range $transport.RootCAs }}
+      "{{.}}",
+      {{end}}]
+    clientCert = """{{ $transport.ClientCert }}"""
+    clientKey = """{{ $transport.ClientKey }}"""
+    {{if $transport.InsecureSkipVerify }}
+    insecureSkipVerify = {{ $transport.InsecureSkipVerify }}
+    {{end}}
+    disableHTTP2 = {{ $transport.DisableHTTP2 }}
+  {{end}}
+
+  {{ $retry := getRetry $backend }}
+  {{if $retry }}
+  [backends."{{ $backendName }}".retry]
+    attempts = {{ $retry.Attempts }}
+    statusCodes = [{{range $retry.StatusCodes }}
+      "{{.}}",
+      {{end}}]
+    methods = [{{range $retry.Methods }}
+      "{{.}}",
+      {{end}}]
+    initialInterval = "{{ $retry.InitialInterval }}"
+    maxInterval = "{{ $retry.MaxInterval }}"
+    perTryTimeout = "{{ $retry.PerTryTimeout }}"
+    budgetPercent = {{ $retry.BudgetPercent }}
+  {{end}}
+
+  {{ $hedging := getHedging $backend }}
+  {{if $hedging }}
+  [backends."{{ $backendName }}".hedging]
+    delay = "{{ $hedging.Delay }}"
+    percentile = {{ $hedging.Percentile }}
+  {{end}}
+
+  {{ $adaptiveConcurrency := getAdaptiveConcurrency $backend }}
+  {{if $adaptiveConcurrency }}
+  [backends."{{ $backendName }}".adaptiveConcurrency]
+    algorithm = "{{ $adaptiveConcurrency.Algorithm }}"
+    initialLimit = {{ $adaptiveConcurrency.InitialLimit }}
+    minLimit = {{ $adaptiveConcurrency.MinLimit }}
+    maxLimit = {{ $adaptiveConcurrency.MaxLimit }}
+    latencyThreshold = "{{ $adaptiveConcurrency.LatencyThreshold }}"
+  {{end}}
+
+  {{ $dnsDiscovery := getDNSDiscovery $backend }}
+  {{if $dnsDiscovery }}
+  [backends."{{ $backendName }}".dnsDiscovery]
+    name = "{{ $dnsDiscovery.Name }}"
+    port = {{ $dnsDiscovery.Port }}
+    scheme = "{{ $dnsDiscovery.Scheme }}"
+    interval = "{{ $dnsDiscovery.Interval }}"
+  {{end}}
+
   {{range $serverName, $server := getServers $backend}}
   [backends."{{ $backendName }}".servers."{{ $serverName }}"]
     url = "{{ $server.URL }}"
     weight = {{ $server.Weight }}
+    priority = {{ $server.Priority }}
   {{end}}
 
 {{end}}
@@ -1769,6 +2189,30 @@ var _templatesKvTmpl = []byte(`[backends]
       {{end}}
     {{end}}
 
+    {{ $weightedBackends := getWeightedBackends $frontend }}
+    {{range $weightedBackends }}
+    [[frontends."{{ $frontendName }}".backends]]
+      name = "{{ .Name }}"
+      weight = {{ .Weight }}
+    {{end}}
+
+    {{ $backendsStickiness := getBackendsStickiness $frontend }}
+    {{if $backendsStickiness }}
+    [frontends."{{ $frontendName }}".backendsStickiness]
+      cookieName = "{{ $backendsStickiness.CookieName }}"
+      secure = {{ $backendsStickiness.Secure }}
+      httpOnly = {{ $backendsStickiness.HTTPOnly }}
+      sameSite = "{{ $backendsStickiness.SameSite }}"
+    {{end}}
+
+    {{ $mirror := getMirror $frontend }}
+    {{if $mirror }}
+    [frontends."{{ $frontendName }}".mirror]
+      backend = "{{ $mirror.Backend }}"
+      percent = {{ $mirror.Percent }}
+      maxBodySize = {{ $mirror.MaxBodySize }}
+    {{end}}
+
     {{ $rateLimit := getRateLimit $frontend }}
     {{if $rateLimit }}
     [frontends."{{ $frontendName }}".rateLimit]
@@ -1982,6 +2426,8 @@ var _templatesMarathonTmpl = []byte(`{{ $apps := .Applications }}
     [backends."{{ $backendName }}".loadBalancer]
       method = "{{ $loadBalancer.Method }}"
       sticky = {{ $loadBalancer.Sticky }}
+      hashKey = "{{ $loadBalancer.HashKey }}"
+      slowStart = "{{ $loadBalancer.SlowStart }}"
       {{if $loadBalancer.Stickiness }}
       [backends."{{ $backendName }}".loadBalancer.stickiness]
         cookieName = "{{ $loadBalancer.Stickiness.CookieName }}"
@@ -1996,16 +2442,31 @@ var _templatesMarathonTmpl = []byte(`{{ $apps := .Applications }}
     [backends."{{ $backendName }}".maxConn]
       extractorFunc = "{{ $maxConn.ExtractorFunc }}"
       amount = {{ $maxConn.Amount }}
+      queueLength = {{ $maxConn.QueueLength }}
+      queueTimeout = "{{ $maxConn.QueueTimeout }}"
+      priorityHeader = "{{ $maxConn.PriorityHeader }}"
     {{end}}
 
     {{ $healthCheck := getHealthCheck $app.SegmentLabels }}
     {{if $healthCheck }}
     [backends."{{ $backendName }}".healthCheck]
+      type = "{{ $healthCheck.Type }}"
+      service = "{{ $healthCheck.Service }}"
       scheme = "{{ $healthCheck.Scheme }}"
       path = "{{ $healthCheck.Path }}"
       port = {{ $healthCheck.Port }}
       interval = "{{ $healthCheck.Interval }}"
       hostname = "{{ $healthCheck.Hostname }}"
+      method = "{{ $healthCheck.Method }}"
+      {{if $healthCheck.StatusCodes }}
+      statusCodes = [{{range $healthCheck.StatusCodes }}
+        "{{.}}",
+        {{end}}]
+      {{end}}
+      bodyRegex = "{{ $healthCheck.BodyRegex }}"
+      timeout = "{{ $healthCheck.Timeout }}"
+      rise = {{ $healthCheck.Rise }}
+      fall = {{ $healthCheck.Fall }}
       {{if $healthCheck.Headers }}
       [backends.{{ $backendName }}.healthCheck.headers]
         {{range $k, $v := $healthCheck.Headers }}
@@ -2024,10 +2485,80 @@ var _templatesMarathonTmpl = []byte(`{{ $apps := .Applications }}
       retryExpression = "{{ $buffering.RetryExpression }}"
     {{end}}
 
+    {{ $passiveHealthCheck := getPassiveHealthCheck $app.SegmentLabels }}
+    {{if $passiveHealthCheck }}
+    [backends."{{ $backendName }}".passiveHealthCheck]
+      consecutiveErrors = {{ $passiveHealthCheck.ConsecutiveErrors }}
+      ejectionTime = "{{ $passiveHealthCheck.EjectionTime }}"
+      maxEjectionPercent = {{ $passiveHealthCheck.MaxEjectionPercent }}
+    {{end}}
+
+    {{ $transport := getTransport $app.SegmentLabels }}
+    {{if $transport }}
+    [backends."{{ $backendName }}".transport]
+      dialTimeout = "{{ $transport.DialTimeout }}"
+      responseHeaderTimeout = "{{ $transport.ResponseHeaderTimeout }}"
+      idleConnTimeout = "{{ $transport.IdleConnTimeout }}"
+      maxIdleConnsPerHost = {{ $transport.MaxIdleConnsPerHost }}
+      serverName = "{{ $transport.ServerName }}"
+      rootCAs = [{{range $transport.RootCAs }}
+        "{{.}}",
+        {{end}}]
+      clientCert = """{{ $transport.ClientCert }}"""
+      clientKey = """{{ $transport.ClientKey }}"""
+      {{if $transport.InsecureSkipVerify }}
+      insecureSkipVerify = {{ $transport.InsecureSkipVerify }}
+      {{end}}
+      disableHTTP2 = {{ $transport.DisableHTTP2 }}
+    {{end}}
+
+    {{ $retry := getRetry $app.SegmentLabels }}
+    {{if $retry }}
+    [backends."{{ $backendName }}".retry]
+      attempts = {{ $retry.Attempts }}
+      statusCodes = [{{range $retry.StatusCodes }}
+        "{{.}}",
+        {{end}}]
+      methods = [{{range $retry.Methods }}
+        "{{.}}",
+        {{end}}]
+      initialInterval = "{{ $retry.InitialInterval }}"
+      maxInterval = "{{ $retry.MaxInterval }}"
+      perTryTimeout = "{{ $retry.PerTryTimeout }}"
+      budgetPercent = {{ $retry.BudgetPercent }}
+    {{end}}
+
+    {{ $hedging := getHedging $app.SegmentLabels }}
+    {{if $hedging }}
+    [backends."{{ $backendName }}".hedging]
+      delay = "{{ $hedging.Delay }}"
+      percentile = {{ $hedging.Percentile }}
+    {{end}}
+
+    {{ $adaptiveConcurrency := getAdaptiveConcurrency $app.SegmentLabels }}
+    {{if $adaptiveConcurrency }}
+    [backends."{{ $backendName }}".adaptiveConcurrency]
+      algorithm = "{{ $adaptiveConcurrency.Algorithm }}"
+      initialLimit = {{ $adaptiveConcurrency.InitialLimit }}
+      minLimit = {{ $adaptiveConcurrency.MinLimit }}
+      maxLimit = {{ $adaptiveConcurrency.MaxLimit }}
+      latencyThreshold = "{{ $adaptiveConcurrency.LatencyThreshold }}"
+    {{end}}
+
+    {{ $dnsDiscovery := getDNSDiscovery $app.SegmentLabels }}
+    {{if $dnsDiscovery }}
+    [backends."{{ $backendName }}".dnsDiscovery]
+      name = "{{ $dnsDiscovery.Name }}"
+      port = {{ $dnsDiscovery.Port }}
+      scheme = "{{ $dnsDiscovery.Scheme }}"
+      interval = "{{ $dnsDiscovery.Interval }}"
+    {{end}}
+
     {{range $serverName, $server := getServers $app }}
     [backends."{{ $backendName }}".servers."{{ $serverName }}"]
       url = "{{ $server.URL }}"
       weight = {{ $server.Weight }}
+      priority = {{ $server.Priority }}
     {{end}}
 
 {{end}}
@@ -2160,6 +2691,30 @@ var _templatesMarathonTmpl = []byte(`{{ $apps := .Applications }}
       {{end}}
     {{end}}
 
+    {{ $weightedBackends := getWeightedBackends $app.SegmentLabels }}
+    {{range $weightedBackends }}
+    [[frontends."{{ $frontendName }}".backends]]
+      name = "backend{{ .Name }}"
+      weight = {{ .Weight }}
+    {{end}}
+
+    {{ $backendsStickiness := getBackendsStickiness $app.SegmentLabels }}
+    {{if $backendsStickiness }}
+    [frontends."{{ $frontendName }}".backendsStickiness]
+      cookieName = "{{ $backendsStickiness.CookieName }}"
+      secure = {{ $backendsStickiness.Secure }}
+      httpOnly = {{ $backendsStickiness.HTTPOnly }}
+      sameSite = "{{ $backendsStickiness.SameSite }}"
+    {{end}}
+
+    {{ $mirror := getMirror $app.SegmentLabels }}
+    {{if $mirror }}
+    [frontends."{{ $frontendName }}".mirror]
+      backend = "backend{{ $mirror.Backend }}"
+      percent = {{ $mirror.Percent }}
+      maxBodySize = {{ $mirror.MaxBodySize }}
+    {{end}}
+
     {{ $rateLimit := getRateLimit $app.SegmentLabels }}
     {{if $rateLimit }}
     [frontends."{{ $frontendName }}".rateLimit]
@@ -2317,6 +2872,8 @@ var _templatesMesosTmpl = []byte(`[backends]
     [backends."backend-{{ $backendName }}".loadBalancer]
       method = "{{ $loadBalancer.Method }}"
       sticky = {{ $loadBalancer.Sticky }}
+      hashKey = "{{ $loadBalancer.HashKey }}"
+      slowStart = "{{ $loadBalancer.SlowStart }}"
       {{if $loadBalancer.Stickiness }}
       [backends."backend-{{ $backendName }}".loadBalancer.stickiness]
         cookieName = "{{ $loadBalancer.Stickiness.CookieName }}"
@@ -2331,16 +2888,31 @@ var _templatesMesosTmpl = []byte(`[backends]
   [backends."backend-{{ $backendName }}".maxConn]
     extractorFunc = "{{ $maxConn.ExtractorFunc }}"
     amount = {{ $maxConn.Amount }}
+    queueLength = {{ $maxConn.QueueLength }}
+    queueTimeout = "{{ $maxConn.QueueTimeout }}"
+    priorityHeader = "{{ $maxConn.PriorityHeader }}"
   {{end}}
 
   {{ $healthCheck := getHealthCheck $app.TraefikLabels }}
   {{if $healthCheck }}
   [backends."backend-{{ $backendName }}".healthCheck]
+    type = "{{ $healthCheck.Type }}"
+    service = "{{ $healthCheck.Service }}"
     scheme = "{{ $healthCheck.Scheme }}"
     path = "{{ $healthCheck.Path }}"
     port = {{ $healthCheck.Port }}
     interval = "{{ $healthCheck.Interval }}"
     hostname = "{{ $healthCheck.Hostname }}"
+    method = "{{ $healthCheck.Method }}"
+    {{if $healthCheck.StatusCodes }}
+    statusCodes = [{{range $healthCheck.StatusCodes }}
+      "{{.}}",
+      {{end}}]
+    {{end}}
+    bodyRegex = "{{ $healthCheck.BodyRegex }}"
+    timeout = "{{ $healthCheck.Timeout }}"
+    rise = {{ $healthCheck.Rise }}
+    fall = {{ $healthCheck.Fall }}
     {{if $healthCheck.Headers }}
     [backends."backend-{{ $backendName }}".healthCheck.headers]
       {{range $k, $v := $healthCheck.Headers }}
@@ -2359,10 +2931,80 @@ var _templatesMesosTmpl = []byte(`[backends]
     retryExpression = "{{ $buffering.RetryExpression }}"
   {{end}}
 
+  {{ $passiveHealthCheck := getPassiveHealthCheck $app.TraefikLabels }}
+  {{if $passiveHealthCheck }}
+  [backends."backend-{{ $backendName }}".passiveHealthCheck]
+    consecutiveErrors = {{ $passiveHealthCheck.ConsecutiveErrors }}
+    ejectionTime = "{{ $passiveHealthCheck.EjectionTime }}"
+    maxEjectionPercent = {{ $passiveHealthCheck.MaxEjectionPercent }}
+  {{end}}
+
+  {{ $transport := getTransport $app.TraefikLabels }}
+  {{if $transport }}
+  [backends."backend-{{ $backendName }}".transport]
+    dialTimeout = "{{ $transport.DialTimeout }}"
+    responseHeaderTimeout = "{{ $transport.ResponseHeaderTimeout }}"
+    idleConnTimeout = "{{ $transport.IdleConnTimeout }}"
+    maxIdleConnsPerHost = {{ $transport.MaxIdleConnsPerHost }}
+    serverName = "{{ $transport.ServerName }}"
+    rootCAs = [{{range $transport.RootCAs }}
+      "{{.}}",
+      {{end}}]
+    clientCert = """{{ $transport.ClientCert }}"""
+    clientKey = """{{ $transport.ClientKey }}"""
+    {{if $transport.InsecureSkipVerify }}
+    insecureSkipVerify = {{ $transport.InsecureSkipVerify }}
+    {{end}}
+    disableHTTP2 = {{ $transport.DisableHTTP2 }}
+  {{end}}
+
+  {{ $retry := getRetry $app.TraefikLabels }}
+  {{if $retry }}
+  [backends."backend-{{ $backendName }}".retry]
+    attempts = {{ $retry.Attempts }}
+    statusCodes = [{{range $retry.StatusCodes }}
+      "{{.}}",
+      {{end}}]
+    methods = [{{range $retry.Methods }}
+      "{{.}}",
+      {{end}}]
+    initialInterval = "{{ $retry.InitialInterval }}"
+    maxInterval = "{{ $retry.MaxInterval }}"
+    perTryTimeout = "{{ $retry.PerTryTimeout }}"
+    budgetPercent = {{ $retry.BudgetPercent }}
+  {{end}}
+
+  {{ $hedging := getHedging $app.TraefikLabels }}
+  {{if $hedging }}
+  [backends."backend-{{ $backendName }}".hedging]
+    delay = "{{ $hedging.Delay }}"
+    percentile = {{ $hedging.Percentile }}
+  {{end}}
+
+  {{ $adaptiveConcurrency := getAdaptiveConcurrency $app.TraefikLabels }}
+  {{if $adaptiveConcurrency }}
+  [backends."backend-{{ $backendName }}".adaptiveConcurrency]
+    algorithm = "{{ $adaptiveConcurrency.Algorithm }}"
+    initialLimit = {{ $adaptiveConcurrency.InitialLimit }}
+    minLimit = {{ $adaptiveConcurrency.MinLimit }}
+    maxLimit = {{ $adaptiveConcurrency.MaxLimit }}
+    latencyThreshold = "{{ $adaptiveConcurrency.LatencyThreshold }}"
+  {{end}}
+
+  {{ $dnsDiscovery := getDNSDiscovery $app.TraefikLabels }}
+  {{if $dnsDiscovery }}
+  [backends."backend-{{ $backendName }}".dnsDiscovery]
+    name = "{{ $dnsDiscovery.Name }}"
+    port = {{ $dnsDiscovery.Port }}
+    scheme = "{{ $dnsDiscovery.Scheme }}"
+    interval = "{{ $dnsDiscovery.Interval }}"
+  {{end}}
+
   {{range $serverName, $server := getServers $tasks }}
   [backends."backend-{{ $backendName }}".servers."{{ $serverName }}"]
     url = "{{ $server.URL }}"
     weight = {{ $server.Weight }}
+    priority = {{ $server.Priority }}
   {{end}}
 {{end}}
 
@@ -2495,6 +3137,30 @@ var _templatesMesosTmpl = []byte(`[backends]
       {{end}}
     {{end}}
 
+    {{ $weightedBackends := getWeightedBackends $app.TraefikLabels }}
+    {{range $weightedBackends }}
+    [[frontends."frontend-{{ $frontendName }}".backends]]
+      name = "backend-{{ .Name }}"
+      weight = {{ .Weight }}
+    {{end}}
+
+    {{ $backendsStickiness := getBackendsStickiness $app.TraefikLabels }}
+    {{if $backendsStickiness }}
+    [frontends."frontend-{{ $frontendName }}".backendsStickiness]
+      cookieName = "{{ $backendsStickiness.CookieName }}"
+      secure = {{ $backendsStickiness.Secure }}
+      httpOnly = {{ $backendsStickiness.HTTPOnly }}
+      sameSite = "{{ $backendsStickiness.SameSite }}"
+    {{end}}
+
+    {{ $mirror := getMirror $app.TraefikLabels }}
+    {{if $mirror }}
+    [frontends."frontend-{{ $frontendName }}".mirror]
+      backend = "backend-{{ $mirror.Backend }}"
+      percent = {{ $mirror.Percent }}
+      maxBodySize = {{ $mirror.MaxBodySize }}
+    {{end}}
+
     {{ $rateLimit := getRateLimit $app.TraefikLabels }}
     {{if $rateLimit }}
     [frontends."frontend-{{ $frontendName }}".rateLimit]
@@ -2706,6 +3372,8 @@ var _templatesRancherTmpl = []byte(`{{ $backendServers := .Backends }}
     [backends."backend-{{ $backendName }}".loadBalancer]
       method = "{{ $loadBalancer.Method }}"
       sticky = {{ $loadBalancer.Sticky }}
+      hashKey = "{{ $loadBalancer.HashKey }}"
+      slowStart = "{{ $loadBalancer.SlowStart }}"
       {{if $loadBalancer.Stickiness }}
       [backends."backend-{{ $backendName }}".loadBalancer.stickiness]
         cookieName = "{{ $loadBalancer.Stickiness.CookieName }}"
@@ -2720,16 +3388,31 @@ var _templatesRancherTmpl = []byte(`{{ $backendServers := .Backends }}
   [backends."backend-{{ $backendName }}".maxConn]
     extractorFunc = "{{ $maxConn.ExtractorFunc }}"
     amount = {{ $maxConn.Amount }}
+    queueLength = {{ $maxConn.QueueLength }}
+    queueTimeout = "{{ $maxConn.QueueTimeout }}"
+    priorityHeader = "{{ $maxConn.PriorityHeader }}"
   {{end}}
 
   {{ $healthCheck := getHealthCheck $backend.SegmentLabels }}
   {{if $healthCheck }}
   [backends."backend-{{ $backendName }}".healthCheck]
+    type = "{{ $healthCheck.Type }}"
+    service = "{{ $healthCheck.Service }}"
     scheme = "{{ $healthCheck.Scheme }}"
     path = "{{ $healthCheck.Path }}"
     port = {{ $healthCheck.Port }}
     interval = "{{ $healthCheck.Interval }}"
     hostname = "{{ $healthCheck.Hostname }}"
+    method = "{{ $healthCheck.Method }}"
+    {{if $healthCheck.StatusCodes }}
+    statusCodes = [{{range $healthCheck.StatusCodes }}
+      "{{.}}",
+      {{end}}]
+    {{end}}
+    bodyRegex = "{{ $healthCheck.BodyRegex }}"
+    timeout = "{{ $healthCheck.Timeout }}"
+    rise = {{ $healthCheck.Rise }}
+    fall = {{ $healthCheck.Fall }}
     {{if $healthCheck.Headers }}
     [backends."backend-{{ $backendName }}".healthCheck.headers]
       {{range $k, $v := $healthCheck.Headers }}
@@ -2748,10 +3431,80 @@ var _templatesRancherTmpl = []byte(`{{ $backendServers := .Backends }}
     retryExpression = "{{ $buffering.RetryExpression }}"
   {{end}}
 
+  {{ $passiveHealthCheck := getPassiveHealthCheck $backend.SegmentLabels }}
+  {{if $passiveHealthCheck }}
+  [backends."backend-{{ $backendName }}".passiveHealthCheck]
+    consecutiveErrors = {{ $passiveHealthCheck.ConsecutiveErrors }}
+    ejectionTime = "{{ $passiveHealthCheck.EjectionTime }}"
+    maxEjectionPercent = {{ $passiveHealthCheck.MaxEjectionPercent }}
+  {{end}}
+
+  {{ $transport := getTransport $backend.SegmentLabels }}
+  {{if $transport }}
+  [backends."backend-{{ $backendName }}".transport]
+    dialTimeout = "{{ $transport.DialTimeout }}"
+    responseHeaderTimeout = "{{ $transport.ResponseHeaderTimeout }}"
+    idleConnTimeout = "{{ $transport.IdleConnTimeout }}"
+    maxIdleConnsPerHost = {{ $transport.MaxIdleConnsPerHost }}
+    serverName = "{{ $transport.ServerName }}"
+    rootCAs = [{{range $transport.RootCAs }}
+      "{{.}}",
+      {{end}}]
+    clientCert = """{{ $transport.ClientCert }}"""
+    clientKey = """{{ $transport.ClientKey }}"""
+    {{if $transport.InsecureSkipVerify }}
+    insecureSkipVerify = {{ $transport.InsecureSkipVerify }}
+    {{end}}
+    disableHTTP2 = {{ $transport.DisableHTTP2 }}
+  {{end}}
+
+  {{ $retry := getRetry $backend.SegmentLabels }}
+  {{if $retry }}
+  [backends."backend-{{ $backendName }}".retry]
+    attempts = {{ $retry.Attempts }}
+    statusCodes = [{{range $retry.StatusCodes }}
+      "{{.}}",
+      {{end}}]
+    methods = [{{range $retry.Methods }}
+      "{{.}}",
+      {{end}}]
+    initialInterval = "{{ $retry.InitialInterval }}"
+    maxInterval = "{{ $retry.MaxInterval }}"
+    perTryTimeout = "{{ $retry.PerTryTimeout }}"
+    budgetPercent = {{ $retry.BudgetPercent }}
+  {{end}}
+
+  {{ $hedging := getHedging $backend.SegmentLabels }}
+  {{if $hedging }}
+  [backends."backend-{{ $backendName }}".hedging]
+    delay = "{{ $hedging.Delay }}"
+    percentile = {{ $hedging.Percentile }}
+  {{end}}
+
+  {{ $adaptiveConcurrency := getAdaptiveConcurrency $backend.SegmentLabels }}
+  {{if $adaptiveConcurrency }}
+  [backends."backend-{{ $backendName }}".adaptiveConcurrency]
+    algorithm = "{{ $adaptiveConcurrency.Algorithm }}"
+    initialLimit = {{ $adaptiveConcurrency.InitialLimit }}
+    minLimit = {{ $adaptiveConcurrency.MinLimit }}
+    maxLimit = {{ $adaptiveConcurrency.MaxLimit }}
+    latencyThreshold = "{{ $adaptiveConcurrency.LatencyThreshold }}"
+  {{end}}
+
+  {{ $dnsDiscovery := getDNSDiscovery $backend.SegmentLabels }}
+  {{if $dnsDiscovery }}
+  [backends."backend-{{ $backendName }}".dnsDiscovery]
+    name = "{{ $dnsDiscovery.Name }}"
+    port = {{ $dnsDiscovery.Port }}
+    scheme = "{{ $dnsDiscovery.Scheme }}"
+    interval = "{{ $dnsDiscovery.Interval }}"
+  {{end}}
+
   {{range $serverName, $server := getServers $backend}}
   [backends."backend-{{ $backendName }}".servers."{{ $serverName }}"]
     url = "{{ $server.URL }}"
     weight = {{ $server.Weight }}
+    priority = {{ $server.Priority }}
   {{end}}
 
 {{end}}
@@ -2883,6 +3636,30 @@ var _templatesRancherTmpl = []byte(`{{ $backendServers := .Backends }}
       {{end}}
     {{end}}
 
+    {{ $weightedBackends := getWeightedBackends $service.SegmentLabels }}
+    {{range $weightedBackends }}
+    [[frontends."frontend-{{ $frontendName }}".backends]]
+      name = "backend-{{ .Name }}"
+      weight = {{ .Weight }}
+    {{end}}
+
+    {{ $backendsStickiness := getBackendsStickiness $service.SegmentLabels }}
+    {{if $backendsStickiness }}
+    [frontends."frontend-{{ $frontendName }}".backendsStickiness]
+      cookieName = "{{ $backendsStickiness.CookieName }}"
+      secure = {{ $backendsStickiness.Secure }}
+      httpOnly = {{ $backendsStickiness.HTTPOnly }}
+      sameSite = "{{ $backendsStickiness.SameSite }}"
+    {{end}}
+
+    {{ $mirror := getMirror $service.SegmentLabels }}
+    {{if $mirror }}
+    [frontends."frontend-{{ $frontendName }}".mirror]
+      backend = "backend-{{ $mirror.Backend }}"
+      percent = {{ $mirror.Percent }}
+      maxBodySize = {{ $mirror.MaxBodySize }}
+    {{end}}
+
     {{ $rateLimit := getRateLimit $service.SegmentLabels }}
     {{if $rateLimit }}
     [frontends."frontend-{{ $frontendName }}".rateLimit]
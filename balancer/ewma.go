@@ -0,0 +1,259 @@
+package balancer
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/pteich/traefik/log"
+	"github.com/vulcand/oxy/roundrobin"
+	"github.com/vulcand/oxy/utils"
+)
+
+const (
+	// ewmaDecayTime is the time for an observed latency to lose most of its weight in the average.
+	ewmaDecayTime = 10 * time.Second
+	// ewmaPenalty is the latency of a server without any observation while it has in-flight requests,
+	// so that a new server does not get all the requests before its first response.
+	ewmaPenalty = float64(time.Second)
+)
+
+// PeakEWMA forwards the requests to the server with the lowest latency, using the power of two choices:
+// two servers are randomly picked, and the one with the lowest cost gets the request.
+// The cost of a server is the exponentially weighted moving average of its latency (which immediately rises to the observed peaks),
+// multiplied by its number of in-flight requests, relatively to its weight.
+type PeakEWMA struct {
+	next          http.Handler
+	errHandler    utils.ErrorHandler
+	stickySession *roundrobin.StickySession
+	now           func() time.Time
+	randIntn      func(n int) int
+
+	mutex   sync.Mutex
+	servers []*ewmaServer
+}
+
+type ewmaServer struct {
+	url      *url.URL
+	weight   int
+	inFlight int
+	// latency average in nanoseconds
+	ewma       float64
+	lastUpdate time.Time
+}
+
+// NewPeakEWMA creates a new PeakEWMA, the stickySession is optional.
+func NewPeakEWMA(next http.Handler, stickySession *roundrobin.StickySession) *PeakEWMA {
+	return &PeakEWMA{
+		next:          next,
+		errHandler:    utils.DefaultHandler,
+		stickySession: stickySession,
+		now:           time.Now,
+		randIntn:      rand.Intn,
+	}
+}
+
+func (b *PeakEWMA) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	// make shallow copy of request before changing anything to avoid side effects
+	newReq := *req
+	excluded := excludedServers(req)
+
+	var srv *ewmaServer
+	if b.stickySession != nil {
+		cookieURL, present, err := b.stickySession.GetBackend(&newReq, b.Servers())
+		if err != nil {
+			log.Warnf("Error using server from cookie: %v", err)
+		}
+
+		if present && !excluded(cookieURL) {
+			srv = b.acquireServer(cookieURL)
+		}
+	}
+
+	if srv == nil {
+		srv = b.acquireNextServer(excluded)
+		if srv == nil {
+			b.errHandler.ServeHTTP(w, req, fmt.Errorf("no servers in the pool"))
+			return
+		}
+
+		if b.stickySession != nil {
+			b.stickySession.StickBackend(srv.url, &w)
+		}
+	}
+
+	start := b.now()
+	defer func() {
+		b.release(srv, b.now().Sub(start))
+	}()
+
+	newReq.URL = utils.CopyURL(srv.url)
+	b.next.ServeHTTP(w, &newReq)
+}
+
+// ExcludesServers returns true, the servers excluded by the request context are skipped.
+func (b *PeakEWMA) ExcludesServers() bool {
+	return true
+}
+
+// Servers gets the servers URL.
+func (b *PeakEWMA) Servers() []*url.URL {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	out := make([]*url.URL, len(b.servers))
+	for i, srv := range b.servers {
+		out[i] = srv.url
+	}
+	return out
+}
+
+// ServerWeight gets the server weight.
+func (b *PeakEWMA) ServerWeight(u *url.URL) (int, bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if srv, _ := b.findServer(u); srv != nil {
+		return srv.weight, true
+	}
+	return -1, false
+}
+
+// RemoveServer removes a server, its in-flight requests are not interrupted.
+func (b *PeakEWMA) RemoveServer(u *url.URL) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	_, index := b.findServer(u)
+	if index < 0 {
+		return fmt.Errorf("server not found")
+	}
+
+	b.servers = append(b.servers[:index], b.servers[index+1:]...)
+	return nil
+}
+
+// UpsertServer adds a server, or updates the weight of an existing one.
+func (b *PeakEWMA) UpsertServer(u *url.URL, options ...roundrobin.ServerOption) error {
+	if u == nil {
+		return fmt.Errorf("server URL can't be nil")
+	}
+
+	weight, err := serverWeight(u, options...)
+	if err != nil {
+		return err
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if srv, _ := b.findServer(u); srv != nil {
+		srv.weight = weight
+		return nil
+	}
+
+	b.servers = append(b.servers, &ewmaServer{url: utils.CopyURL(u), weight: weight})
+	return nil
+}
+
+// acquireNextServer selects the server with the lowest cost of two, skipping the excluded ones unless they are the only ones left.
+func (b *PeakEWMA) acquireNextServer(excluded func(u *url.URL) bool) *ewmaServer {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	candidates := b.candidates(excluded)
+	if len(candidates) == 0 {
+		candidates = b.candidates(nil)
+	}
+
+	var selected *ewmaServer
+	switch len(candidates) {
+	case 0:
+		return nil
+	case 1:
+		selected = candidates[0]
+	default:
+		// power of two choices: two distinct servers are picked
+		i := b.randIntn(len(candidates))
+		j := b.randIntn(len(candidates) - 1)
+		if j >= i {
+			j++
+		}
+
+		selected = candidates[i]
+		if candidates[j].cost() < selected.cost() {
+			selected = candidates[j]
+		}
+	}
+
+	selected.inFlight++
+	return selected
+}
+
+func (b *PeakEWMA) candidates(excluded func(u *url.URL) bool) []*ewmaServer {
+	// the servers with a zero weight (oxy's default weight can be set to 0) don't get any request
+	candidates := make([]*ewmaServer, 0, len(b.servers))
+	for _, srv := range b.servers {
+		if srv.weight > 0 && (excluded == nil || !excluded(srv.url)) {
+			candidates = append(candidates, srv)
+		}
+	}
+	return candidates
+}
+
+func (b *PeakEWMA) acquireServer(u *url.URL) *ewmaServer {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	srv, _ := b.findServer(u)
+	if srv != nil {
+		srv.inFlight++
+	}
+	return srv
+}
+
+func (b *PeakEWMA) release(srv *ewmaServer, latency time.Duration) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	srv.inFlight--
+	srv.observe(float64(latency), b.now())
+}
+
+func (b *PeakEWMA) findServer(u *url.URL) (*ewmaServer, int) {
+	for i, srv := range b.servers {
+		if sameURL(srv.url, u) {
+			return srv, i
+		}
+	}
+	return nil, -1
+}
+
+// observe updates the latency average, the peaks are immediately taken into account.
+func (s *ewmaServer) observe(latency float64, now time.Time) {
+	if s.lastUpdate.IsZero() || latency > s.ewma {
+		s.ewma = latency
+	} else {
+		elapsed := now.Sub(s.lastUpdate)
+		w := math.Exp(-float64(elapsed) / float64(ewmaDecayTime))
+		s.ewma = s.ewma*w + latency*(1-w)
+	}
+	s.lastUpdate = now
+}
+
+func (s *ewmaServer) cost() float64 {
+	if s.weight <= 0 {
+		return math.Inf(1)
+	}
+
+	latency := s.ewma
+	if s.lastUpdate.IsZero() && s.inFlight > 0 {
+		latency = ewmaPenalty
+	}
+
+	return latency * float64(s.inFlight+1) / float64(s.weight)
+}
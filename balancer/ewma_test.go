@@ -0,0 +1,123 @@
+package balancer
+
+import (
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+func TestPeakEWMA(t *testing.T) {
+	now := time.Now()
+	latencies := map[string]time.Duration{
+		"fast": 10 * time.Millisecond,
+		"slow": 100 * time.Millisecond,
+	}
+
+	served := map[string]int{}
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		served[req.URL.Host]++
+		now = now.Add(latencies[req.URL.Host])
+	})
+
+	lb := NewPeakEWMA(next, nil)
+	lb.now = func() time.Time { return now }
+
+	var picks int
+	lb.randIntn = func(n int) int {
+		picks++
+		return picks % n
+	}
+
+	require.NoError(t, lb.UpsertServer(mustParseURL(t, "http://slow")))
+	require.NoError(t, lb.UpsertServer(mustParseURL(t, "http://fast")))
+
+	for i := 0; i < 20; i++ {
+		lb.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+	}
+
+	// Both servers are tried while they have no latency, then the fast one gets the requests.
+	assert.Equal(t, 1, served["slow"])
+	assert.Equal(t, 19, served["fast"])
+}
+
+func TestPeakEWMAInFlight(t *testing.T) {
+	lb := NewPeakEWMA(http.NotFoundHandler(), nil)
+	lb.randIntn = func(n int) int { return 0 }
+
+	require.NoError(t, lb.UpsertServer(mustParseURL(t, "http://a")))
+	require.NoError(t, lb.UpsertServer(mustParseURL(t, "http://b"), roundrobin.Weight(2)))
+
+	// The servers without latency are penalized by their in-flight requests.
+	assert.Equal(t, "a", lb.acquireNextServer(nil).url.Host)
+	assert.Equal(t, "b", lb.acquireNextServer(nil).url.Host)
+	assert.Equal(t, "b", lb.acquireNextServer(nil).url.Host)
+	assert.Equal(t, "b", lb.acquireNextServer(nil).url.Host)
+	assert.Equal(t, "a", lb.acquireNextServer(nil).url.Host)
+}
+
+func TestPeakEWMAZeroWeight(t *testing.T) {
+	lb := NewPeakEWMA(http.NotFoundHandler(), nil)
+	lb.randIntn = func(n int) int { return 0 }
+
+	// oxy turns a zero weight into its default weight, which can be set to 0.
+	lb.servers = []*ewmaServer{{url: mustParseURL(t, "http://a"), weight: 0}}
+
+	// A server with a zero weight doesn't get any request.
+	assert.Nil(t, lb.acquireNextServer(nil))
+	assert.True(t, math.IsInf(lb.servers[0].cost(), 1))
+
+	lb.servers = append(lb.servers,
+		&ewmaServer{url: mustParseURL(t, "http://b"), weight: 1},
+		&ewmaServer{url: mustParseURL(t, "http://c"), weight: 1},
+	)
+
+	for i := 0; i < 10; i++ {
+		srv := lb.acquireNextServer(nil)
+		require.NotNil(t, srv)
+		assert.NotEqual(t, "a", srv.url.Host)
+		assert.False(t, math.IsNaN(srv.cost()))
+	}
+}
+
+func TestEWMAServerObserve(t *testing.T) {
+	now := time.Now()
+	srv := &ewmaServer{url: &url.URL{Host: "a"}, weight: 1}
+
+	srv.observe(float64(100*time.Millisecond), now)
+	assert.Equal(t, float64(100*time.Millisecond), srv.ewma)
+
+	// A lower latency is averaged, according to the elapsed time.
+	srv.observe(float64(10*time.Millisecond), now.Add(ewmaDecayTime))
+	assert.InDelta(t, float64(43*time.Millisecond), srv.ewma, float64(time.Millisecond))
+
+	// A peak is immediately taken into account.
+	srv.observe(float64(200*time.Millisecond), now.Add(ewmaDecayTime+time.Millisecond))
+	assert.Equal(t, float64(200*time.Millisecond), srv.ewma)
+}
+
+func TestPeakEWMAServers(t *testing.T) {
+	lb := NewPeakEWMA(http.NotFoundHandler(), nil)
+
+	a := mustParseURL(t, "http://a")
+	require.NoError(t, lb.UpsertServer(a, roundrobin.Weight(3)))
+	assert.Equal(t, []*url.URL{a}, lb.Servers())
+
+	weight, ok := lb.ServerWeight(a)
+	assert.True(t, ok)
+	assert.Equal(t, 3, weight)
+
+	require.NoError(t, lb.RemoveServer(a))
+	assert.Empty(t, lb.Servers())
+	assert.Error(t, lb.RemoveServer(a))
+
+	recorder := httptest.NewRecorder()
+	lb.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+}
@@ -0,0 +1,29 @@
+package balancer
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// excludedServersKey is the key within the request context of the servers the load balancers should skip.
+type excludedServersKey struct{}
+
+// ExcludingBalancer is a load balancer able to skip the servers excluded by WithExcludedServers.
+type ExcludingBalancer interface {
+	ExcludesServers() bool
+}
+
+// WithExcludedServers returns a copy of ctx making the load balancers of this package skip the servers
+// for which excluded returns true (e.g. the servers already tried by the retries), when other servers are available.
+func WithExcludedServers(ctx context.Context, excluded func(u *url.URL) bool) context.Context {
+	return context.WithValue(ctx, excludedServersKey{}, excluded)
+}
+
+// excludedServers returns the servers to skip for the request, none when the context doesn't exclude any.
+func excludedServers(req *http.Request) func(u *url.URL) bool {
+	if excluded, ok := req.Context().Value(excludedServersKey{}).(func(u *url.URL) bool); ok && excluded != nil {
+		return excluded
+	}
+	return func(*url.URL) bool { return false }
+}
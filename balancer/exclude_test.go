@@ -0,0 +1,93 @@
+package balancer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExcludedServers(t *testing.T) {
+	newHash := func(next http.Handler) Balancer {
+		lb, err := NewHash(next, HashKeyPath)
+		require.NoError(t, err)
+		return lb
+	}
+
+	testCases := []struct {
+		desc        string
+		newBalancer func(next http.Handler) Balancer
+	}{
+		{
+			desc: "least connection",
+			newBalancer: func(next http.Handler) Balancer {
+				return NewLeastConn(next, nil)
+			},
+		},
+		{
+			desc: "peak EWMA",
+			newBalancer: func(next http.Handler) Balancer {
+				return NewPeakEWMA(next, nil)
+			},
+		},
+		{
+			desc:        "hash",
+			newBalancer: newHash,
+		},
+		{
+			desc: "priority",
+			newBalancer: func(next http.Handler) Balancer {
+				return NewPriority(func() (Balancer, error) {
+					return newHash(next), nil
+				})
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var hosts []string
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				hosts = append(hosts, req.URL.Host)
+			})
+
+			lb := test.newBalancer(next)
+			require.NoError(t, lb.UpsertServer(mustParseURL(t, "http://a")))
+			require.NoError(t, lb.UpsertServer(mustParseURL(t, "http://b")))
+			require.NoError(t, lb.UpsertServer(mustParseURL(t, "http://c")))
+
+			excluding, ok := lb.(ExcludingBalancer)
+			require.True(t, ok)
+			assert.True(t, excluding.ExcludesServers())
+
+			serve := func(excluded ...string) {
+				req := httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil)
+				req = req.WithContext(WithExcludedServers(req.Context(), func(u *url.URL) bool {
+					for _, host := range excluded {
+						if u.Host == host {
+							return true
+						}
+					}
+					return false
+				}))
+				lb.ServeHTTP(httptest.NewRecorder(), req)
+			}
+
+			for i := 0; i < 5; i++ {
+				serve("a", "b")
+			}
+			assert.Equal(t, []string{"c", "c", "c", "c", "c"}, hosts)
+
+			// The excluded servers are used when no other server is available.
+			hosts = nil
+			serve("a", "b", "c")
+			assert.Len(t, hosts, 1)
+		})
+	}
+}
@@ -0,0 +1,256 @@
+package balancer
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/vulcand/oxy/roundrobin"
+	"github.com/vulcand/oxy/utils"
+)
+
+// Hash key sources
+const (
+	HashKeyClientIP = "clientip"
+	HashKeyPath     = "path"
+	HashKeyHeader   = "header"
+	HashKeyCookie   = "cookie"
+)
+
+// pointsPerWeight is the number of points of a server on the ring, for each unit of its weight.
+const pointsPerWeight = 100
+
+// Hash forwards the requests to the servers according to a consistent hash of a request key (ring hash).
+// Adding or removing a server only remaps the keys of about 1/N of the ring.
+type Hash struct {
+	next       http.Handler
+	errHandler utils.ErrorHandler
+	key        func(req *http.Request) string
+
+	mutex   sync.RWMutex
+	servers []*hashServer
+	ring    []ringPoint
+}
+
+type hashServer struct {
+	url    *url.URL
+	weight int
+}
+
+type ringPoint struct {
+	hash   uint64
+	server *hashServer
+}
+
+// NewHash creates a new Hash balancer.
+// The hashKey is the source of the request key:
+// "clientip" (default), "path", "header:<name>" or "cookie:<name>".
+// The requests without the header or the cookie are hashed on their client IP.
+func NewHash(next http.Handler, hashKey string) (*Hash, error) {
+	key, err := newHashKeyFunc(hashKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Hash{
+		next:       next,
+		errHandler: utils.DefaultHandler,
+		key:        key,
+	}, nil
+}
+
+func (b *Hash) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	srv := b.nextServer(b.key(req), excludedServers(req))
+	if srv == nil {
+		b.errHandler.ServeHTTP(w, req, fmt.Errorf("no servers in the pool"))
+		return
+	}
+
+	// make shallow copy of request before changing anything to avoid side effects
+	newReq := *req
+	newReq.URL = utils.CopyURL(srv.url)
+	b.next.ServeHTTP(w, &newReq)
+}
+
+// ExcludesServers returns true, the servers excluded by the request context are skipped.
+func (b *Hash) ExcludesServers() bool {
+	return true
+}
+
+// Servers gets the servers URL.
+func (b *Hash) Servers() []*url.URL {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	out := make([]*url.URL, len(b.servers))
+	for i, srv := range b.servers {
+		out[i] = srv.url
+	}
+	return out
+}
+
+// ServerWeight gets the server weight.
+func (b *Hash) ServerWeight(u *url.URL) (int, bool) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	if srv, _ := b.findServer(u); srv != nil {
+		return srv.weight, true
+	}
+	return -1, false
+}
+
+// RemoveServer removes a server.
+func (b *Hash) RemoveServer(u *url.URL) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	_, index := b.findServer(u)
+	if index < 0 {
+		return fmt.Errorf("server not found")
+	}
+
+	b.servers = append(b.servers[:index], b.servers[index+1:]...)
+	b.buildRing()
+	return nil
+}
+
+// UpsertServer adds a server, or updates the weight of an existing one.
+func (b *Hash) UpsertServer(u *url.URL, options ...roundrobin.ServerOption) error {
+	if u == nil {
+		return fmt.Errorf("server URL can't be nil")
+	}
+
+	weight, err := serverWeight(u, options...)
+	if err != nil {
+		return err
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if srv, _ := b.findServer(u); srv != nil {
+		srv.weight = weight
+	} else {
+		b.servers = append(b.servers, &hashServer{url: utils.CopyURL(u), weight: weight})
+	}
+
+	b.buildRing()
+	return nil
+}
+
+// nextServer returns the server of the first point of the ring clockwise from the key,
+// skipping the excluded servers unless they are the only ones left.
+func (b *Hash) nextServer(key string, excluded func(u *url.URL) bool) *hashServer {
+	hash := hashString(key)
+
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	if len(b.ring) == 0 {
+		return nil
+	}
+
+	// first point of the ring clockwise
+	index := sort.Search(len(b.ring), func(i int) bool { return b.ring[i].hash >= hash })
+	if index == len(b.ring) {
+		index = 0
+	}
+
+	for i := range b.ring {
+		srv := b.ring[(index+i)%len(b.ring)].server
+		if !excluded(srv.url) {
+			return srv
+		}
+	}
+	return b.ring[index].server
+}
+
+// buildRing places the points of the servers on the ring, according to their weight.
+// The points only depend on the server URL, so the ring is the same whatever the order of the servers.
+func (b *Hash) buildRing() {
+	var ring []ringPoint
+	for _, srv := range b.servers {
+		for i := 0; i < srv.weight*pointsPerWeight; i++ {
+			ring = append(ring, ringPoint{hash: hashString(srv.url.String() + "-" + strconv.Itoa(i)), server: srv})
+		}
+	}
+
+	sort.Slice(ring, func(i, j int) bool { return ring[i].hash < ring[j].hash })
+	b.ring = ring
+}
+
+func (b *Hash) findServer(u *url.URL) (*hashServer, int) {
+	for i, srv := range b.servers {
+		if sameURL(srv.url, u) {
+			return srv, i
+		}
+	}
+	return nil, -1
+}
+
+func newHashKeyFunc(hashKey string) (func(req *http.Request) string, error) {
+	source, name := hashKey, ""
+	if i := strings.Index(hashKey, ":"); i >= 0 {
+		source, name = hashKey[:i], strings.TrimSpace(hashKey[i+1:])
+	}
+
+	switch strings.ToLower(strings.TrimSpace(source)) {
+	case "", HashKeyClientIP:
+		return clientIP, nil
+	case HashKeyPath:
+		return func(req *http.Request) string {
+			return req.URL.Path
+		}, nil
+	case HashKeyHeader:
+		if len(name) == 0 {
+			return nil, fmt.Errorf("missing header name in hash key %q", hashKey)
+		}
+		return func(req *http.Request) string {
+			if value := req.Header.Get(name); len(value) > 0 {
+				return value
+			}
+			return clientIP(req)
+		}, nil
+	case HashKeyCookie:
+		if len(name) == 0 {
+			return nil, fmt.Errorf("missing cookie name in hash key %q", hashKey)
+		}
+		return func(req *http.Request) string {
+			if cookie, err := req.Cookie(name); err == nil && len(cookie.Value) > 0 {
+				return cookie.Value
+			}
+			return clientIP(req)
+		}, nil
+	default:
+		return nil, fmt.Errorf("invalid hash key %q", hashKey)
+	}
+}
+
+func clientIP(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
+
+func hashString(value string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(value))
+
+	// FNV poorly spreads the values differing by their last bytes, so the hash goes through the finalizer of splitmix64.
+	x := h.Sum64()
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
@@ -0,0 +1,154 @@
+package balancer
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+func TestHashKey(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		hashKey  string
+		request  func(req *http.Request)
+		expected string
+	}{
+		{
+			desc:     "default to client IP",
+			hashKey:  "",
+			expected: "10.0.0.1",
+		},
+		{
+			desc:     "client IP",
+			hashKey:  "ClientIP",
+			expected: "10.0.0.1",
+		},
+		{
+			desc:     "path",
+			hashKey:  "path",
+			expected: "/foo",
+		},
+		{
+			desc:    "header",
+			hashKey: "header:X-User",
+			request: func(req *http.Request) {
+				req.Header.Set("X-User", "bob")
+			},
+			expected: "bob",
+		},
+		{
+			desc:     "missing header",
+			hashKey:  "header:X-User",
+			expected: "10.0.0.1",
+		},
+		{
+			desc:    "cookie",
+			hashKey: "cookie:session",
+			request: func(req *http.Request) {
+				req.AddCookie(&http.Cookie{Name: "session", Value: "abc"})
+			},
+			expected: "abc",
+		},
+		{
+			desc:     "missing cookie",
+			hashKey:  "cookie:session",
+			expected: "10.0.0.1",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			key, err := newHashKeyFunc(test.hashKey)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil)
+			req.RemoteAddr = "10.0.0.1:1234"
+			if test.request != nil {
+				test.request(req)
+			}
+
+			assert.Equal(t, test.expected, key(req))
+		})
+	}
+}
+
+func TestHashKeyInvalid(t *testing.T) {
+	for _, hashKey := range []string{"header", "cookie:", "foo"} {
+		_, err := NewHash(http.NotFoundHandler(), hashKey)
+		assert.Error(t, err, hashKey)
+	}
+}
+
+func TestHash(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("X-Server", req.URL.Host)
+	})
+
+	lb, err := NewHash(next, "header:X-User")
+	require.NoError(t, err)
+
+	for i := 0; i < 4; i++ {
+		require.NoError(t, lb.UpsertServer(mustParseURL(t, fmt.Sprintf("http://server%d", i)), roundrobin.Weight(1)))
+	}
+
+	const keys = 10000
+
+	assignments := func() map[string]string {
+		result := make(map[string]string, keys)
+		for i := 0; i < keys; i++ {
+			req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+			req.Header.Set("X-User", fmt.Sprintf("user%d", i))
+
+			recorder := httptest.NewRecorder()
+			lb.ServeHTTP(recorder, req)
+			result[req.Header.Get("X-User")] = recorder.Header().Get("X-Server")
+		}
+		return result
+	}
+
+	before := assignments()
+
+	// The keys are spread between the servers.
+	counts := map[string]int{}
+	for _, server := range before {
+		counts[server]++
+	}
+	require.Len(t, counts, 4)
+	for server, count := range counts {
+		assert.InDelta(t, keys/4, count, keys/10, server)
+	}
+
+	// The same keys are forwarded to the same servers.
+	assert.Equal(t, before, assignments())
+
+	// Only the keys of the removed server are remapped.
+	require.NoError(t, lb.RemoveServer(mustParseURL(t, "http://server3")))
+	afterRemove := assignments()
+	for key, server := range before {
+		if server != "server3" {
+			assert.Equal(t, server, afterRemove[key])
+		}
+	}
+
+	// Adding the server back restores the initial assignments.
+	require.NoError(t, lb.UpsertServer(mustParseURL(t, "http://server3")))
+	assert.Equal(t, before, assignments())
+}
+
+func TestHashNoServer(t *testing.T) {
+	lb, err := NewHash(http.NotFoundHandler(), "")
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	lb.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+}
@@ -0,0 +1,204 @@
+// Package balancer provides the load balancers complementing the round robin ones of oxy.
+package balancer
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/pteich/traefik/log"
+	"github.com/vulcand/oxy/roundrobin"
+	"github.com/vulcand/oxy/utils"
+)
+
+// LeastConn forwards the requests to the server with the fewest in-flight requests, relatively to its weight.
+type LeastConn struct {
+	next          http.Handler
+	errHandler    utils.ErrorHandler
+	stickySession *roundrobin.StickySession
+
+	mutex   sync.Mutex
+	servers []*leastConnServer
+	// index of the last selected server, used to spread the requests between the servers having the same load.
+	index int
+}
+
+type leastConnServer struct {
+	url      *url.URL
+	weight   int
+	inFlight int
+}
+
+// NewLeastConn creates a new LeastConn, the stickySession is optional.
+func NewLeastConn(next http.Handler, stickySession *roundrobin.StickySession) *LeastConn {
+	return &LeastConn{
+		next:          next,
+		errHandler:    utils.DefaultHandler,
+		stickySession: stickySession,
+		index:         -1,
+	}
+}
+
+func (b *LeastConn) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	// make shallow copy of request before changing anything to avoid side effects
+	newReq := *req
+	excluded := excludedServers(req)
+
+	var srv *leastConnServer
+	if b.stickySession != nil {
+		cookieURL, present, err := b.stickySession.GetBackend(&newReq, b.Servers())
+		if err != nil {
+			log.Warnf("Error using server from cookie: %v", err)
+		}
+
+		if present && !excluded(cookieURL) {
+			srv = b.acquireServer(cookieURL)
+		}
+	}
+
+	if srv == nil {
+		srv = b.acquireNextServer(excluded)
+		if srv == nil {
+			b.errHandler.ServeHTTP(w, req, fmt.Errorf("no servers in the pool"))
+			return
+		}
+
+		if b.stickySession != nil {
+			b.stickySession.StickBackend(srv.url, &w)
+		}
+	}
+	defer b.release(srv)
+
+	newReq.URL = utils.CopyURL(srv.url)
+	b.next.ServeHTTP(w, &newReq)
+}
+
+// ExcludesServers returns true, the servers excluded by the request context are skipped.
+func (b *LeastConn) ExcludesServers() bool {
+	return true
+}
+
+// Servers gets the servers URL.
+func (b *LeastConn) Servers() []*url.URL {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	out := make([]*url.URL, len(b.servers))
+	for i, srv := range b.servers {
+		out[i] = srv.url
+	}
+	return out
+}
+
+// ServerWeight gets the server weight.
+func (b *LeastConn) ServerWeight(u *url.URL) (int, bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if srv, _ := b.findServer(u); srv != nil {
+		return srv.weight, true
+	}
+	return -1, false
+}
+
+// RemoveServer removes a server, its in-flight requests are not interrupted.
+func (b *LeastConn) RemoveServer(u *url.URL) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	_, index := b.findServer(u)
+	if index < 0 {
+		return fmt.Errorf("server not found")
+	}
+
+	b.servers = append(b.servers[:index], b.servers[index+1:]...)
+	b.index = -1
+	return nil
+}
+
+// UpsertServer adds a server, or updates the weight of an existing one.
+func (b *LeastConn) UpsertServer(u *url.URL, options ...roundrobin.ServerOption) error {
+	if u == nil {
+		return fmt.Errorf("server URL can't be nil")
+	}
+
+	weight, err := serverWeight(u, options...)
+	if err != nil {
+		return err
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if srv, _ := b.findServer(u); srv != nil {
+		srv.weight = weight
+		return nil
+	}
+
+	b.servers = append(b.servers, &leastConnServer{url: utils.CopyURL(u), weight: weight})
+	return nil
+}
+
+// acquireNextServer selects the least loaded server, skipping the excluded ones unless they are the only ones left.
+func (b *LeastConn) acquireNextServer(excluded func(u *url.URL) bool) *leastConnServer {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	selected, selectedIndex := b.leastLoaded(excluded)
+	if selected == nil {
+		selected, selectedIndex = b.leastLoaded(nil)
+	}
+
+	if selected != nil {
+		b.index = selectedIndex
+		selected.inFlight++
+	}
+	return selected
+}
+
+func (b *LeastConn) leastLoaded(excluded func(u *url.URL) bool) (*leastConnServer, int) {
+	var selected *leastConnServer
+	selectedIndex := -1
+	for i := range b.servers {
+		index := (b.index + 1 + i) % len(b.servers)
+		srv := b.servers[index]
+		if srv.weight <= 0 || excluded != nil && excluded(srv.url) {
+			continue
+		}
+
+		// inFlight/weight < selected.inFlight/selected.weight, without the divisions
+		if selected == nil || srv.inFlight*selected.weight < selected.inFlight*srv.weight {
+			selected = srv
+			selectedIndex = index
+		}
+	}
+	return selected, selectedIndex
+}
+
+func (b *LeastConn) acquireServer(u *url.URL) *leastConnServer {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	srv, _ := b.findServer(u)
+	if srv != nil {
+		srv.inFlight++
+	}
+	return srv
+}
+
+func (b *LeastConn) release(srv *leastConnServer) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	srv.inFlight--
+}
+
+func (b *LeastConn) findServer(u *url.URL) (*leastConnServer, int) {
+	for i, srv := range b.servers {
+		if sameURL(srv.url, u) {
+			return srv, i
+		}
+	}
+	return nil, -1
+}
@@ -0,0 +1,156 @@
+package balancer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+func TestLeastConn(t *testing.T) {
+	release := make(chan struct{})
+	var started sync.WaitGroup
+
+	var mu sync.Mutex
+	served := map[string]int{}
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		served[req.URL.Host]++
+		mu.Unlock()
+
+		started.Done()
+		if req.Header.Get("X-Wait") != "" {
+			<-release
+		}
+		rw.WriteHeader(http.StatusOK)
+	})
+
+	lb := NewLeastConn(next, nil)
+	require.NoError(t, lb.UpsertServer(mustParseURL(t, "http://a"), roundrobin.Weight(1)))
+	require.NoError(t, lb.UpsertServer(mustParseURL(t, "http://b"), roundrobin.Weight(2)))
+
+	// 3 long-polling requests: 2 for b (weight 2), 1 for a.
+	var done sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		started.Add(1)
+		done.Add(1)
+		go func() {
+			defer done.Done()
+			req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+			req.Header.Set("X-Wait", "true")
+			lb.ServeHTTP(httptest.NewRecorder(), req)
+		}()
+		started.Wait()
+	}
+
+	assert.Equal(t, map[string]int{"a": 1, "b": 2}, served)
+
+	// The servers have the same relative load, the next requests are spread between them.
+	for i := 0; i < 2; i++ {
+		started.Add(1)
+		lb.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+	}
+
+	close(release)
+	done.Wait()
+
+	assert.Equal(t, map[string]int{"a": 2, "b": 3}, served)
+}
+
+func TestLeastConnServers(t *testing.T) {
+	lb := NewLeastConn(http.NotFoundHandler(), nil)
+
+	a := mustParseURL(t, "http://a")
+	b := mustParseURL(t, "http://b")
+
+	require.NoError(t, lb.UpsertServer(a))
+	require.NoError(t, lb.UpsertServer(b, roundrobin.Weight(3)))
+	assert.Equal(t, []*url.URL{a, b}, lb.Servers())
+
+	weight, ok := lb.ServerWeight(a)
+	assert.True(t, ok)
+	assert.Equal(t, 1, weight)
+
+	require.NoError(t, lb.UpsertServer(b, roundrobin.Weight(5)))
+	weight, ok = lb.ServerWeight(b)
+	assert.True(t, ok)
+	assert.Equal(t, 5, weight)
+
+	require.NoError(t, lb.RemoveServer(a))
+	assert.Equal(t, []*url.URL{b}, lb.Servers())
+	assert.Error(t, lb.RemoveServer(a))
+
+	_, ok = lb.ServerWeight(a)
+	assert.False(t, ok)
+}
+
+func TestLeastConnZeroWeight(t *testing.T) {
+	lb := NewLeastConn(http.NotFoundHandler(), nil)
+
+	// oxy turns a zero weight into its default weight, which can be set to 0.
+	lb.servers = []*leastConnServer{{url: mustParseURL(t, "http://a"), weight: 0}}
+
+	// A server with a zero weight doesn't get any request.
+	assert.Nil(t, lb.acquireNextServer(nil))
+
+	lb.servers = append(lb.servers,
+		&leastConnServer{url: mustParseURL(t, "http://b"), weight: 1},
+		&leastConnServer{url: mustParseURL(t, "http://c"), weight: 1},
+	)
+
+	for i := 0; i < 10; i++ {
+		srv := lb.acquireNextServer(nil)
+		require.NotNil(t, srv)
+		assert.NotEqual(t, "a", srv.url.Host)
+	}
+}
+
+func TestLeastConnNoServer(t *testing.T) {
+	lb := NewLeastConn(http.NotFoundHandler(), nil)
+
+	recorder := httptest.NewRecorder()
+	lb.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+}
+
+func TestLeastConnStickySession(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("X-Server", req.URL.Host)
+	})
+
+	lb := NewLeastConn(next, roundrobin.NewStickySession("test"))
+	require.NoError(t, lb.UpsertServer(mustParseURL(t, "http://a")))
+	require.NoError(t, lb.UpsertServer(mustParseURL(t, "http://b")))
+
+	recorder := httptest.NewRecorder()
+	lb.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+
+	server := recorder.Header().Get("X-Server")
+	cookies := recorder.Result().Cookies()
+	require.Len(t, cookies, 1)
+
+	for i := 0; i < 3; i++ {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+		req.AddCookie(cookies[0])
+
+		recorder = httptest.NewRecorder()
+		lb.ServeHTTP(recorder, req)
+
+		assert.Equal(t, server, recorder.Header().Get("X-Server"))
+	}
+}
+
+func mustParseURL(t *testing.T, raw string) *url.URL {
+	t.Helper()
+
+	u, err := url.Parse(raw)
+	require.NoError(t, err)
+	return u
+}
@@ -0,0 +1,200 @@
+package balancer
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+
+	"github.com/vulcand/oxy/roundrobin"
+	"github.com/vulcand/oxy/utils"
+)
+
+// Balancer is a load balancer whose servers can be managed (e.g. by the health checks).
+type Balancer interface {
+	http.Handler
+	Servers() []*url.URL
+	RemoveServer(u *url.URL) error
+	UpsertServer(u *url.URL, options ...roundrobin.ServerOption) error
+}
+
+// Priority dispatches the servers in groups, one per priority, each group being balanced by its own load balancer.
+// The requests are forwarded to the group with the lowest priority value still having servers,
+// so the backup servers (higher values) only get requests when all the servers of the previous groups are down.
+type Priority struct {
+	newBalancer func() (Balancer, error)
+	errHandler  utils.ErrorHandler
+
+	mutex sync.RWMutex
+	// priorities of the servers, kept when a server is removed so it gets back in its group when it is upserted again.
+	priorities map[string]int
+	// groups sorted by priority
+	groups []*priorityGroup
+}
+
+type priorityGroup struct {
+	priority int
+	balancer Balancer
+}
+
+// NewPriority creates a new Priority, newBalancer creates the load balancer of each group.
+func NewPriority(newBalancer func() (Balancer, error)) *Priority {
+	return &Priority{
+		newBalancer: newBalancer,
+		errHandler:  utils.DefaultHandler,
+		priorities:  make(map[string]int),
+	}
+}
+
+// SetServerPriority sets the priority of a server, it is taken into account when the server is upserted.
+// The servers without priority belong to the group 0.
+func (b *Priority) SetServerPriority(u *url.URL, priority int) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.priorities[serverKey(u)] = priority
+}
+
+func (b *Priority) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	balancer := b.activeBalancer()
+	if balancer == nil {
+		b.errHandler.ServeHTTP(w, req, fmt.Errorf("no servers in the pool"))
+		return
+	}
+
+	balancer.ServeHTTP(w, req)
+}
+
+// ExcludesServers returns true when the load balancer of the group getting the requests skips
+// the servers excluded by the request context.
+func (b *Priority) ExcludesServers() bool {
+	excluding, ok := b.activeBalancer().(ExcludingBalancer)
+	return ok && excluding.ExcludesServers()
+}
+
+// Servers gets the servers URL of all the groups.
+func (b *Priority) Servers() []*url.URL {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	var out []*url.URL
+	for _, group := range b.groups {
+		out = append(out, group.balancer.Servers()...)
+	}
+	return out
+}
+
+// ActiveServers gets the servers URL of the group getting the requests.
+func (b *Priority) ActiveServers() []*url.URL {
+	balancer := b.activeBalancer()
+	if balancer == nil {
+		return nil
+	}
+	return balancer.Servers()
+}
+
+// ServerWeight gets the server weight, if the load balancer of its group exposes it.
+func (b *Priority) ServerWeight(u *url.URL) (int, bool) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	group := b.findGroup(u)
+	if group == nil {
+		return -1, false
+	}
+
+	if weighted, ok := group.balancer.(interface {
+		ServerWeight(u *url.URL) (int, bool)
+	}); ok {
+		return weighted.ServerWeight(u)
+	}
+	return -1, false
+}
+
+// RemoveServer removes a server from its group.
+func (b *Priority) RemoveServer(u *url.URL) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	group := b.findGroup(u)
+	if group == nil {
+		return fmt.Errorf("server not found")
+	}
+
+	return group.balancer.RemoveServer(u)
+}
+
+// UpsertServer adds a server to the group of its priority, or updates it.
+func (b *Priority) UpsertServer(u *url.URL, options ...roundrobin.ServerOption) error {
+	if u == nil {
+		return fmt.Errorf("server URL can't be nil")
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	priority := b.priorities[serverKey(u)]
+
+	// the priority of the server may have changed
+	if group := b.findGroup(u); group != nil && group.priority != priority {
+		if err := group.balancer.RemoveServer(u); err != nil {
+			return err
+		}
+	}
+
+	group, err := b.getGroup(priority)
+	if err != nil {
+		return err
+	}
+
+	return group.balancer.UpsertServer(u, options...)
+}
+
+// activeBalancer returns the load balancer of the first group having servers.
+func (b *Priority) activeBalancer() Balancer {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	for _, group := range b.groups {
+		if len(group.balancer.Servers()) > 0 {
+			return group.balancer
+		}
+	}
+	return nil
+}
+
+func (b *Priority) getGroup(priority int) (*priorityGroup, error) {
+	index := sort.Search(len(b.groups), func(i int) bool { return b.groups[i].priority >= priority })
+	if index < len(b.groups) && b.groups[index].priority == priority {
+		return b.groups[index], nil
+	}
+
+	balancer, err := b.newBalancer()
+	if err != nil {
+		return nil, err
+	}
+
+	group := &priorityGroup{priority: priority, balancer: balancer}
+
+	b.groups = append(b.groups, nil)
+	copy(b.groups[index+1:], b.groups[index:])
+	b.groups[index] = group
+
+	return group, nil
+}
+
+func (b *Priority) findGroup(u *url.URL) *priorityGroup {
+	for _, group := range b.groups {
+		for _, srv := range group.balancer.Servers() {
+			if sameURL(srv, u) {
+				return group
+			}
+		}
+	}
+	return nil
+}
+
+func serverKey(u *url.URL) string {
+	return u.Scheme + "://" + u.Host + u.Path
+}
@@ -0,0 +1,96 @@
+package balancer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+func TestPriorityFailover(t *testing.T) {
+	lb := newTestPriority(t)
+
+	a := mustParseURL(t, "http://a")
+	b := mustParseURL(t, "http://b")
+	backup := mustParseURL(t, "http://backup")
+
+	lb.SetServerPriority(backup, 1)
+	require.NoError(t, lb.UpsertServer(backup))
+	require.NoError(t, lb.UpsertServer(a))
+	require.NoError(t, lb.UpsertServer(b))
+
+	assert.Equal(t, []string{"a", "b", "a", "b"}, serveHosts(lb, 4))
+
+	// The backup only gets the requests when all the primary servers are down.
+	require.NoError(t, lb.RemoveServer(a))
+	assert.Equal(t, []string{"b", "b"}, serveHosts(lb, 2))
+
+	require.NoError(t, lb.RemoveServer(b))
+	assert.Equal(t, []string{"backup", "backup"}, serveHosts(lb, 2))
+
+	// The server gets back in its group.
+	require.NoError(t, lb.UpsertServer(a))
+	assert.Equal(t, []string{"a", "a"}, serveHosts(lb, 2))
+
+	require.NoError(t, lb.RemoveServer(a))
+	require.NoError(t, lb.RemoveServer(backup))
+	recorder := httptest.NewRecorder()
+	lb.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+}
+
+func TestPriorityServers(t *testing.T) {
+	lb := newTestPriority(t)
+
+	a := mustParseURL(t, "http://a")
+	backup := mustParseURL(t, "http://backup")
+
+	lb.SetServerPriority(backup, 10)
+	require.NoError(t, lb.UpsertServer(backup, roundrobin.Weight(3)))
+	require.NoError(t, lb.UpsertServer(a))
+	assert.Equal(t, []*url.URL{a, backup}, lb.Servers())
+	assert.Equal(t, []*url.URL{a}, lb.ActiveServers())
+
+	weight, ok := lb.ServerWeight(backup)
+	assert.True(t, ok)
+	assert.Equal(t, 3, weight)
+
+	// A priority change moves the server to its new group.
+	lb.SetServerPriority(backup, 0)
+	require.NoError(t, lb.UpsertServer(backup))
+	assert.Equal(t, []*url.URL{a, backup}, lb.Servers())
+	assert.Equal(t, []string{"a", "backup"}, serveHosts(lb, 2))
+
+	require.NoError(t, lb.RemoveServer(a))
+	assert.Equal(t, []*url.URL{backup}, lb.Servers())
+	assert.Error(t, lb.RemoveServer(a))
+
+	_, ok = lb.ServerWeight(a)
+	assert.False(t, ok)
+}
+
+func newTestPriority(t *testing.T) *Priority {
+	t.Helper()
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("X-Server", req.URL.Host)
+	})
+
+	return NewPriority(func() (Balancer, error) {
+		return roundrobin.New(next)
+	})
+}
+
+func serveHosts(lb http.Handler, count int) []string {
+	var hosts []string
+	for i := 0; i < count; i++ {
+		recorder := httptest.NewRecorder()
+		lb.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+		hosts = append(hosts, recorder.Header().Get("X-Server"))
+	}
+	return hosts
+}
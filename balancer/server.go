@@ -0,0 +1,27 @@
+package balancer
+
+import (
+	"net/url"
+
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+// serverWeight returns the weight set by the server options.
+// The options of oxy only apply to its own servers, so they are applied to a server of a transient round robin.
+func serverWeight(u *url.URL, options ...roundrobin.ServerOption) (int, error) {
+	rr, err := roundrobin.New(nil)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := rr.UpsertServer(u, options...); err != nil {
+		return 0, err
+	}
+
+	weight, _ := rr.ServerWeight(u)
+	return weight, nil
+}
+
+func sameURL(a, b *url.URL) bool {
+	return a.Path == b.Path && a.Host == b.Host && a.Scheme == b.Scheme
+}
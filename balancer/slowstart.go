@@ -0,0 +1,222 @@
+package balancer
+
+import (
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/pteich/traefik/log"
+	"github.com/pteich/traefik/safe"
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+const (
+	// slowStartScale multiplies the weights of the servers, so the servers of weight 1 can be ramped up.
+	slowStartScale = 10
+	// slowStartSteps is the number of weight updates during the slow start window.
+	slowStartSteps = 10
+)
+
+// ServerStarts records when the servers of a backend have been added.
+// It is shared by the load balancers of the backend, so the slow start of a server goes on across the configuration reloads,
+// and the servers already running are not ramped up again by the new load balancers.
+type ServerStarts struct {
+	mutex  sync.Mutex
+	starts map[string]time.Time
+}
+
+// NewServerStarts creates a new ServerStarts.
+func NewServerStarts() *ServerStarts {
+	return &ServerStarts{starts: make(map[string]time.Time)}
+}
+
+// Retain forgets the servers which are not in the given URLs, so they are ramped up again if they come back.
+func (s *ServerStarts) Retain(urls []*url.URL) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	retained := make(map[string]time.Time)
+	for _, u := range urls {
+		key := serverKey(u)
+		if start, ok := s.starts[key]; ok {
+			retained[key] = start
+		}
+	}
+	s.starts = retained
+}
+
+// start returns when the server has been added, now for a new one.
+func (s *ServerStarts) start(u *url.URL, now time.Time) time.Time {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	key := serverKey(u)
+	if start, ok := s.starts[key]; ok {
+		return start
+	}
+
+	s.starts[key] = now
+	return now
+}
+
+func (s *ServerStarts) remove(u *url.URL) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.starts, serverKey(u))
+}
+
+// SlowStart is a round robin ramping up the weight of the servers added to it (e.g. by a provider or by the health check),
+// from a fraction of their weight to their full weight over a window, so the servers just started are not flooded with requests.
+// It can be wrapped by the rebalancer of oxy.
+type SlowStart struct {
+	*roundrobin.RoundRobin
+	window time.Duration
+	starts *ServerStarts
+	now    func() time.Time
+
+	mutex sync.Mutex
+	// servers being ramped up
+	ramps   map[string]*slowStartRamp
+	running bool
+}
+
+type slowStartRamp struct {
+	url    *url.URL
+	weight int
+	start  time.Time
+}
+
+// NewSlowStart creates a new SlowStart, the starts are shared by the load balancers of the backend.
+func NewSlowStart(rr *roundrobin.RoundRobin, window time.Duration, starts *ServerStarts) *SlowStart {
+	return &SlowStart{
+		RoundRobin: rr,
+		window:     window,
+		starts:     starts,
+		now:        time.Now,
+		ramps:      make(map[string]*slowStartRamp),
+	}
+}
+
+// ServerWeight gets the server weight, the full one for a server being ramped up.
+func (b *SlowStart) ServerWeight(u *url.URL) (int, bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if ramp, ok := b.ramps[serverKey(u)]; ok {
+		return ramp.weight, true
+	}
+
+	weight, ok := b.RoundRobin.ServerWeight(u)
+	if !ok {
+		return -1, false
+	}
+	return weight / slowStartScale, true
+}
+
+// RemoveServer removes a server, it will be ramped up again when it is added back.
+func (b *SlowStart) RemoveServer(u *url.URL) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if err := b.RoundRobin.RemoveServer(u); err != nil {
+		return err
+	}
+
+	delete(b.ramps, serverKey(u))
+	b.starts.remove(u)
+	return nil
+}
+
+// UpsertServer adds a server, ramping it up, or updates it.
+func (b *SlowStart) UpsertServer(u *url.URL, options ...roundrobin.ServerOption) error {
+	weight, err := serverWeight(u, options...)
+	if err != nil {
+		return err
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	key := serverKey(u)
+	now := b.now()
+
+	ramp, ok := b.ramps[key]
+	if !ok {
+		if _, exists := b.RoundRobin.ServerWeight(u); exists {
+			return b.RoundRobin.UpsertServer(u, roundrobin.Weight(weight*slowStartScale))
+		}
+
+		ramp = &slowStartRamp{url: u, start: b.starts.start(u, now)}
+	}
+	ramp.weight = weight
+
+	rampWeight, done := b.rampWeight(ramp, now)
+	if err := b.RoundRobin.UpsertServer(u, roundrobin.Weight(rampWeight)); err != nil {
+		return err
+	}
+
+	if done {
+		delete(b.ramps, key)
+		return nil
+	}
+
+	if !ok {
+		log.Debugf("Starting slow start of server %s", u)
+		b.ramps[key] = ramp
+	}
+
+	if !b.running {
+		b.running = true
+		safe.Go(b.run)
+	}
+	return nil
+}
+
+// run updates the weights of the servers being ramped up, until there is none.
+func (b *SlowStart) run() {
+	ticker := time.NewTicker(b.window / slowStartSteps)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if !b.updateWeights() {
+			return
+		}
+	}
+}
+
+// updateWeights updates the weights of the servers being ramped up, and returns whether some are still ramping.
+func (b *SlowStart) updateWeights() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	now := b.now()
+	for key, ramp := range b.ramps {
+		weight, done := b.rampWeight(ramp, now)
+		if err := b.RoundRobin.UpsertServer(ramp.url, roundrobin.Weight(weight)); err != nil {
+			log.Errorf("Error updating the slow start weight of server %s: %v", ramp.url, err)
+		}
+
+		if done {
+			log.Debugf("Slow start of server %s completed", ramp.url)
+			delete(b.ramps, key)
+		}
+	}
+
+	b.running = len(b.ramps) > 0
+	return b.running
+}
+
+// rampWeight returns the scaled weight of the server at the given time, and whether its ramp is done.
+func (b *SlowStart) rampWeight(ramp *slowStartRamp, now time.Time) (int, bool) {
+	elapsed := now.Sub(ramp.start)
+	if elapsed >= b.window {
+		return ramp.weight * slowStartScale, true
+	}
+
+	weight := int(int64(ramp.weight*slowStartScale) * int64(elapsed) / int64(b.window))
+	if weight < 1 {
+		weight = 1
+	}
+	return weight, false
+}
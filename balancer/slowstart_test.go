@@ -0,0 +1,141 @@
+package balancer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+func TestSlowStartRamp(t *testing.T) {
+	now := time.Now()
+	lb := newTestSlowStart(t, NewServerStarts(), func() time.Time { return now })
+
+	a := mustParseURL(t, "http://a")
+	b := mustParseURL(t, "http://b")
+
+	require.NoError(t, lb.UpsertServer(a, roundrobin.Weight(2)))
+	assertRRWeight(t, lb, a, 1)
+
+	// The full weight is reported, e.g. for the health check to add the server back.
+	weight, ok := lb.ServerWeight(a)
+	assert.True(t, ok)
+	assert.Equal(t, 2, weight)
+
+	now = now.Add(30 * time.Second)
+	require.NoError(t, lb.UpsertServer(b))
+	assert.True(t, lb.updateWeights())
+	assertRRWeight(t, lb, a, 10)
+	assertRRWeight(t, lb, b, 1)
+
+	// A weight update of a server being ramped up changes its target.
+	require.NoError(t, lb.UpsertServer(a, roundrobin.Weight(4)))
+	assertRRWeight(t, lb, a, 20)
+
+	now = now.Add(30 * time.Second)
+	assert.True(t, lb.updateWeights())
+	assertRRWeight(t, lb, a, 40)
+	assertRRWeight(t, lb, b, 5)
+
+	now = now.Add(time.Minute)
+	assert.False(t, lb.updateWeights())
+	assertRRWeight(t, lb, b, 10)
+
+	weight, ok = lb.ServerWeight(b)
+	assert.True(t, ok)
+	assert.Equal(t, 1, weight)
+
+	// A server already ramped up keeps its full weight.
+	require.NoError(t, lb.UpsertServer(b, roundrobin.Weight(3)))
+	assertRRWeight(t, lb, b, 30)
+
+	// A server added back is ramped up again.
+	require.NoError(t, lb.RemoveServer(a))
+	require.NoError(t, lb.UpsertServer(a, roundrobin.Weight(4)))
+	assertRRWeight(t, lb, a, 1)
+}
+
+func TestSlowStartSharedStarts(t *testing.T) {
+	now := time.Now()
+	starts := NewServerStarts()
+
+	a := mustParseURL(t, "http://a")
+	b := mustParseURL(t, "http://b")
+
+	previous := newTestSlowStart(t, starts, func() time.Time { return now })
+	require.NoError(t, previous.UpsertServer(a))
+	require.NoError(t, previous.UpsertServer(b))
+
+	// The load balancer of a reloaded configuration goes on with the slow start of the servers.
+	now = now.Add(30 * time.Second)
+	lb := newTestSlowStart(t, starts, func() time.Time { return now })
+	require.NoError(t, lb.UpsertServer(a))
+	assertRRWeight(t, lb, a, 5)
+
+	now = now.Add(time.Minute)
+	lb = newTestSlowStart(t, starts, func() time.Time { return now })
+	require.NoError(t, lb.UpsertServer(a))
+	assertRRWeight(t, lb, a, 10)
+
+	// The servers no longer in the configuration are ramped up again.
+	starts.Retain([]*url.URL{a})
+	require.NoError(t, lb.UpsertServer(b))
+	assertRRWeight(t, lb, b, 1)
+}
+
+func TestSlowStartRebalancer(t *testing.T) {
+	now := time.Now()
+	slowStart := newTestSlowStart(t, NewServerStarts(), func() time.Time { return now })
+
+	lb, err := roundrobin.NewRebalancer(slowStart)
+	require.NoError(t, err)
+
+	a := mustParseURL(t, "http://a")
+	b := mustParseURL(t, "http://b")
+
+	now = now.Add(-time.Hour)
+	require.NoError(t, lb.UpsertServer(a))
+	now = now.Add(time.Hour)
+	require.NoError(t, lb.UpsertServer(b))
+
+	// The rebalancer resets the weights through the slow start.
+	assertRRWeight(t, slowStart, a, 10)
+	assertRRWeight(t, slowStart, b, 1)
+
+	served := map[string]int{}
+	for i := 0; i < 11; i++ {
+		recorder := httptest.NewRecorder()
+		lb.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+		served[recorder.Header().Get("X-Server")]++
+	}
+
+	assert.Equal(t, map[string]int{"a": 10, "b": 1}, served)
+}
+
+func newTestSlowStart(t *testing.T, starts *ServerStarts, now func() time.Time) *SlowStart {
+	t.Helper()
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("X-Server", req.URL.Host)
+	})
+
+	rr, err := roundrobin.New(next)
+	require.NoError(t, err)
+
+	lb := NewSlowStart(rr, time.Minute, starts)
+	lb.now = now
+	return lb
+}
+
+func assertRRWeight(t *testing.T, lb *SlowStart, u *url.URL, expected int) {
+	t.Helper()
+
+	weight, ok := lb.RoundRobin.ServerWeight(u)
+	require.True(t, ok)
+	assert.Equal(t, expected, weight)
+}
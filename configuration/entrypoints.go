@@ -4,14 +4,23 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/containous/flaeg/parse"
 	"github.com/pteich/traefik/log"
 	"github.com/pteich/traefik/tls"
 	"github.com/pteich/traefik/types"
 )
 
+// Entry point protocols
+const (
+	EntryPointProtocolHTTP = "http"
+	EntryPointProtocolTCP  = "tcp"
+	EntryPointProtocolUDP  = "udp"
+)
+
 // EntryPoint holds an entry point configuration of the reverse proxy (ip, port, TLS...)
 type EntryPoint struct {
 	Address              string
+	Protocol             string            `export:"true"`
 	TLS                  *tls.TLS          `export:"true"`
 	Redirect             *types.Redirect   `export:"true"`
 	Auth                 *types.Auth       `export:"true"`
@@ -20,6 +29,22 @@ type EntryPoint struct {
 	Compress             bool              `export:"true"`
 	ProxyProtocol        *ProxyProtocol    `export:"true"`
 	ForwardedHeaders     *ForwardedHeaders `export:"true"`
+	UDP                  *UDP              `export:"true"`
+}
+
+// IsTCP returns true if the entry point handles raw TCP connections instead of HTTP requests.
+func (ep *EntryPoint) IsTCP() bool {
+	return ep != nil && strings.EqualFold(ep.Protocol, EntryPointProtocolTCP)
+}
+
+// IsUDP returns true if the entry point forwards UDP datagrams instead of HTTP requests.
+func (ep *EntryPoint) IsUDP() bool {
+	return ep != nil && strings.EqualFold(ep.Protocol, EntryPointProtocolUDP)
+}
+
+// UDP contains the UDP entry point configuration
+type UDP struct {
+	SessionTimeout parse.Duration `export:"true"`
 }
 
 // ProxyProtocol contains Proxy-Protocol configuration
@@ -76,8 +101,14 @@ func (ep *EntryPoints) Set(value string) error {
 		return err
 	}
 
+	configUDP, err := makeEntryPointUDP(result)
+	if err != nil {
+		return err
+	}
+
 	(*ep)[result["name"]] = &EntryPoint{
 		Address:              result["address"],
+		Protocol:             result["protocol"],
 		TLS:                  configTLS,
 		Auth:                 makeEntryPointAuth(result),
 		Redirect:             makeEntryPointRedirect(result),
@@ -86,6 +117,7 @@ func (ep *EntryPoints) Set(value string) error {
 		WhiteList:            makeWhiteList(result),
 		ProxyProtocol:        makeEntryPointProxyProtocol(result),
 		ForwardedHeaders:     makeEntryPointForwardedHeaders(result),
+		UDP:                  configUDP,
 	}
 
 	return nil
@@ -163,6 +195,20 @@ func makeEntryPointAuth(result map[string]string) *types.Auth {
 	return auth
 }
 
+func makeEntryPointUDP(result map[string]string) (*UDP, error) {
+	rawTimeout, ok := result["udp_sessiontimeout"]
+	if !ok {
+		return nil, nil
+	}
+
+	configUDP := &UDP{}
+	if err := configUDP.SessionTimeout.Set(rawTimeout); err != nil {
+		return nil, fmt.Errorf("invalid UDP session timeout %q: %v", rawTimeout, err)
+	}
+
+	return configUDP, nil
+}
+
 func makeEntryPointProxyProtocol(result map[string]string) *ProxyProtocol {
 	var proxyProtocol *ProxyProtocol
 
@@ -2,7 +2,9 @@ package configuration
 
 import (
 	"testing"
+	"time"
 
+	"github.com/containous/flaeg/parse"
 	"github.com/pteich/traefik/tls"
 	"github.com/pteich/traefik/types"
 	"github.com/stretchr/testify/assert"
@@ -475,6 +477,27 @@ func TestEntryPoints_Set(t *testing.T) {
 				ForwardedHeaders: &ForwardedHeaders{Insecure: true},
 			},
 		},
+		{
+			name:                   "tcp protocol",
+			expression:             "Name:foo Address::5432 Protocol:tcp",
+			expectedEntryPointName: "foo",
+			expectedEntryPoint: &EntryPoint{
+				Address:          ":5432",
+				Protocol:         "tcp",
+				ForwardedHeaders: &ForwardedHeaders{Insecure: true},
+			},
+		},
+		{
+			name:                   "udp protocol",
+			expression:             "Name:foo Address::53 Protocol:udp UDP.SessionTimeout:10s",
+			expectedEntryPointName: "foo",
+			expectedEntryPoint: &EntryPoint{
+				Address:          ":53",
+				Protocol:         "udp",
+				ForwardedHeaders: &ForwardedHeaders{Insecure: true},
+				UDP:              &UDP{SessionTimeout: parse.Duration(10 * time.Second)},
+			},
+		},
 	}
 
 	for _, test := range testCases {
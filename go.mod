@@ -23,6 +23,7 @@ require (
 	github.com/gambol99/go-marathon v0.7.2-0.20180614232016-99a156b96fb2
 	github.com/go-acme/lego/v4 v4.5.3
 	github.com/go-kit/kit v0.9.0
+	github.com/golang/protobuf v1.5.3
 	github.com/google/go-github v9.0.0+incompatible
 	github.com/google/uuid v1.5.0
 	github.com/gorilla/websocket v1.5.1
@@ -67,6 +68,7 @@ require (
 	github.com/urfave/negroni v0.2.1-0.20170426175938-490e6a555d47
 	github.com/vulcand/oxy v1.2.0
 	golang.org/x/net v0.19.0
+	google.golang.org/grpc v1.60.1
 	gopkg.in/DataDog/dd-trace-go.v1 v1.13.0
 	gopkg.in/fsnotify.v1 v1.4.7
 	gopkg.in/yaml.v2 v2.4.0
@@ -134,7 +136,6 @@ require (
 	github.com/gogo/protobuf v1.3.2 // indirect
 	github.com/golang/glog v1.1.2 // indirect
 	github.com/golang/groupcache v0.0.0-20210331224755-41bb18bfe9da // indirect
-	github.com/golang/protobuf v1.5.3 // indirect
 	github.com/google/gnostic v0.5.7-v3refs // indirect
 	github.com/google/go-cmp v0.5.9 // indirect
 	github.com/google/go-querystring v1.1.0 // indirect
@@ -243,7 +244,6 @@ require (
 	google.golang.org/genproto v0.0.0-20231002182017-d307bd883b97 // indirect
 	google.golang.org/genproto/googleapis/api v0.0.0-20231002182017-d307bd883b97 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20231002182017-d307bd883b97 // indirect
-	google.golang.org/grpc v1.60.1 // indirect
 	google.golang.org/protobuf v1.32.0 // indirect
 	gopkg.in/inf.v0 v0.9.1 // indirect
 	gopkg.in/ini.v1 v1.62.0 // indirect
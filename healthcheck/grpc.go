@@ -0,0 +1,134 @@
+package healthcheck
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/golang/protobuf/proto"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// grpcHealthCheckPath is the path of the Check method of the gRPC health checking protocol.
+const grpcHealthCheckPath = "/grpc.health.v1.Health/Check"
+
+// grpcFrameHeaderLength is the length of the header of the gRPC messages: a compression flag and the message length.
+const grpcFrameHeaderLength = 5
+
+// checkGRPCHealth returns a nil error if the server answers SERVING to the gRPC health checking protocol.
+func checkGRPCHealth(serverURL *url.URL, backend *BackendConfig) error {
+	req, err := backend.newGRPCRequest(serverURL)
+	if err != nil {
+		return fmt.Errorf("failed to create gRPC request: %s", err)
+	}
+
+	req = backend.addHeadersAndHost(req)
+
+	client := http.Client{
+		Timeout:   backend.requestTimeout,
+		Transport: backend.Options.Transport,
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("gRPC request failed: %s", err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("received error status code: %v", resp.StatusCode)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read gRPC response: %s", err)
+	}
+
+	// The status is in the trailers, or in the headers of a response without message.
+	grpcStatus := resp.Trailer.Get("Grpc-Status")
+	grpcMessage := resp.Trailer.Get("Grpc-Message")
+	if grpcStatus == "" {
+		grpcStatus = resp.Header.Get("Grpc-Status")
+		grpcMessage = resp.Header.Get("Grpc-Message")
+	}
+	if grpcStatus != "0" {
+		return fmt.Errorf("received gRPC status %q: %s", grpcStatus, grpcMessage)
+	}
+
+	status, err := parseGRPCHealthResponse(body)
+	if err != nil {
+		return fmt.Errorf("invalid gRPC response: %s", err)
+	}
+
+	if status != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("received gRPC health status: %s", status)
+	}
+
+	return nil
+}
+
+// newGRPCRequest creates the request to the Check method, for the service of the health check.
+// The servers without TLS are requested with the h2c scheme, as gRPC requires HTTP/2.
+func (b *BackendConfig) newGRPCRequest(serverURL *url.URL) (*http.Request, error) {
+	scheme := serverURL.Scheme
+	if len(b.Scheme) > 0 {
+		scheme = b.Scheme
+	}
+	if scheme == "http" {
+		scheme = "h2c"
+	}
+
+	u := &url.URL{Scheme: scheme, Host: serverURL.Host, Path: grpcHealthCheckPath}
+	if b.Port != 0 {
+		u.Host = net.JoinHostPort(serverURL.Hostname(), strconv.Itoa(b.Port))
+	}
+
+	message, err := proto.Marshal(&healthpb.HealthCheckRequest{Service: b.Service})
+	if err != nil {
+		return nil, err
+	}
+
+	body := make([]byte, grpcFrameHeaderLength+len(message))
+	binary.BigEndian.PutUint32(body[1:grpcFrameHeaderLength], uint32(len(message)))
+	copy(body[grpcFrameHeaderLength:], message)
+
+	req, err := http.NewRequest(http.MethodPost, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/grpc")
+	req.Header.Set("Te", "trailers")
+
+	return req, nil
+}
+
+// parseGRPCHealthResponse returns the serving status of the response to the Check method.
+func parseGRPCHealthResponse(body []byte) (healthpb.HealthCheckResponse_ServingStatus, error) {
+	if len(body) < grpcFrameHeaderLength {
+		return healthpb.HealthCheckResponse_UNKNOWN, errors.New("missing message")
+	}
+
+	if body[0] != 0 {
+		return healthpb.HealthCheckResponse_UNKNOWN, errors.New("compressed message")
+	}
+
+	length := binary.BigEndian.Uint32(body[1:grpcFrameHeaderLength])
+	if uint32(len(body)-grpcFrameHeaderLength) != length {
+		return healthpb.HealthCheckResponse_UNKNOWN, fmt.Errorf("message length %d, expected %d", len(body)-grpcFrameHeaderLength, length)
+	}
+
+	response := &healthpb.HealthCheckResponse{}
+	if err := proto.Unmarshal(body[grpcFrameHeaderLength:], response); err != nil {
+		return healthpb.HealthCheckResponse_UNKNOWN, err
+	}
+
+	return response.Status, nil
+}
@@ -0,0 +1,151 @@
+package healthcheck
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/pteich/traefik/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/http2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestCheckGRPCHealth(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("serving", healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus("not-serving", healthpb.HealthCheckResponse_NOT_SERVING)
+
+	grpcServer := grpc.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+	go grpcServer.Serve(listener)
+	defer grpcServer.Stop()
+
+	testCases := []struct {
+		desc        string
+		serverURL   string
+		service     string
+		expectedErr bool
+	}{
+		{
+			desc:      "whole server serving",
+			serverURL: "h2c://" + listener.Addr().String(),
+		},
+		{
+			desc:      "service serving, http scheme",
+			serverURL: "http://" + listener.Addr().String(),
+			service:   "serving",
+		},
+		{
+			desc:        "service not serving",
+			serverURL:   "h2c://" + listener.Addr().String(),
+			service:     "not-serving",
+			expectedErr: true,
+		},
+		{
+			desc:        "unknown service",
+			serverURL:   "h2c://" + listener.Addr().String(),
+			service:     "unknown",
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			backend := NewBackendConfig(Options{
+				Type:      TypeGRPC,
+				Service:   test.service,
+				Transport: newH2CTransport(),
+			}, "backendName")
+
+			err := checkHealth(testhelpers.MustParseURL(test.serverURL), backend)
+			if test.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestParseGRPCHealthResponse(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		body           []byte
+		expectedStatus healthpb.HealthCheckResponse_ServingStatus
+		expectedErr    bool
+	}{
+		{
+			desc:           "serving",
+			body:           []byte{0, 0, 0, 0, 2, 0x08, 0x01},
+			expectedStatus: healthpb.HealthCheckResponse_SERVING,
+		},
+		{
+			desc:           "not serving",
+			body:           []byte{0, 0, 0, 0, 2, 0x08, 0x02},
+			expectedStatus: healthpb.HealthCheckResponse_NOT_SERVING,
+		},
+		{
+			desc:        "missing message",
+			body:        []byte{0, 0},
+			expectedErr: true,
+		},
+		{
+			desc:        "compressed message",
+			body:        []byte{1, 0, 0, 0, 2, 0x08, 0x01},
+			expectedErr: true,
+		},
+		{
+			desc:        "truncated message",
+			body:        []byte{0, 0, 0, 0, 3, 0x08, 0x01},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			status, err := parseGRPCHealthResponse(test.body)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedStatus, status)
+		})
+	}
+}
+
+type h2cTransport struct {
+	*http2.Transport
+}
+
+func (t *h2cTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req.URL.Scheme = "http"
+	return t.Transport.RoundTrip(req)
+}
+
+// newH2CTransport creates a transport sending the h2c requests with HTTP/2 without TLS, like the forwarding transport.
+func newH2CTransport() http.RoundTripper {
+	transport := &http.Transport{}
+	transport.RegisterProtocol("h2c", &h2cTransport{
+		Transport: &http2.Transport{
+			DialTLS: func(netw, addr string, cfg *tls.Config) (net.Conn, error) {
+				return net.Dial(netw, addr)
+			},
+			AllowHTTP: true,
+		},
+	})
+	return transport
+}
@@ -2,10 +2,14 @@ package healthcheck
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net"
 	"net/http"
 	"net/url"
+	"regexp"
 	"strconv"
 	"sync"
 	"time"
@@ -13,9 +17,19 @@ import (
 	"github.com/go-kit/kit/metrics"
 	"github.com/pteich/traefik/log"
 	"github.com/pteich/traefik/safe"
+	"github.com/pteich/traefik/types"
 	"github.com/vulcand/oxy/roundrobin"
 )
 
+// unixScheme is the scheme of the servers listening on a unix domain socket.
+const unixScheme = "unix"
+
+// defaultRequestTimeout is the default timeout of each check.
+const defaultRequestTimeout = 5 * time.Second
+
+// maxBodySize is the maximum size of the body of the responses matched against the body regular expression.
+const maxBodySize = 1 << 20
+
 var singleton *HealthCheck
 var once sync.Once
 
@@ -27,26 +41,59 @@ type BalancerHandler interface {
 	UpsertServer(u *url.URL, options ...roundrobin.ServerOption) error
 }
 
+// weightedBalancer is a load balancer exposing the weight of its servers.
+type weightedBalancer interface {
+	ServerWeight(u *url.URL) (int, bool)
+}
+
 // metricsRegistry is a local interface in the health check package, exposing only the required metrics
 // necessary for the health check package. This makes it easier for the tests.
 type metricsRegistry interface {
 	BackendServerUpGauge() metrics.Gauge
 }
 
+// Types of health check.
+const (
+	// TypeHTTP checks the status code of a HTTP request to the server.
+	TypeHTTP = "http"
+	// TypeTCP only checks that a TCP connection, or a TLS handshake, to the server succeeds.
+	TypeTCP = "tcp"
+	// TypeGRPC checks the serving status of the server with the gRPC health checking protocol.
+	TypeGRPC = "grpc"
+)
+
 // Options are the public health check options.
 type Options struct {
+	Type string
+	// Service is the service name of the gRPC health checks, the whole server when empty.
+	Service   string
 	Headers   map[string]string
 	Hostname  string
 	Scheme    string
 	Path      string
 	Port      int
 	Transport http.RoundTripper
+	// TLSConfig is the TLS configuration of the TCP health checks of the servers using TLS.
+	TLSConfig *tls.Config
 	Interval  time.Duration
 	LB        BalancerHandler
+	// Method is the method of the HTTP health checks.
+	Method string
+	// StatusCodes are the status codes of the healthy responses to the HTTP health checks, 2xx and 3xx when empty.
+	StatusCodes types.HTTPCodeRanges
+	// BodyRegex is the regular expression the body of the healthy responses to the HTTP health checks must match.
+	BodyRegex *regexp.Regexp
+	// Timeout is the timeout of each check.
+	Timeout time.Duration
+	// Rise is the number of consecutive successful checks before a server is returned to the load balancer.
+	Rise int
+	// Fall is the number of consecutive failed checks before a server is removed from the load balancer.
+	Fall int
 }
 
 func (opt Options) String() string {
-	return fmt.Sprintf("[Hostname: %s Headers: %v Path: %s Port: %d Interval: %s]", opt.Hostname, opt.Headers, opt.Path, opt.Port, opt.Interval)
+	return fmt.Sprintf("[Type: %s Service: %s Hostname: %s Headers: %v Method: %s Path: %s Port: %d Interval: %s Timeout: %s Rise: %d Fall: %d]",
+		opt.Type, opt.Service, opt.Hostname, opt.Headers, opt.Method, opt.Path, opt.Port, opt.Interval, opt.Timeout, opt.Rise, opt.Fall)
 }
 
 type backendURL struct {
@@ -60,9 +107,25 @@ type BackendConfig struct {
 	name           string
 	disabledURLs   []backendURL
 	requestTimeout time.Duration
+	// consecutive counts, by server URL, the consecutive checks contradicting the current state of the server.
+	consecutive map[string]int
+
+	// checked is false for the backends without active health check, only registered for the status of their servers.
+	checked bool
+
+	lock     sync.RWMutex
+	statuses map[string]*ServerStatus
+	// ejected are the servers ejected by the passive health check, by URL.
+	ejected map[string]bool
+	// notify sends the transitions of the servers to the subscribers of the health check.
+	notify func(events []Event)
 }
 
 func (b *BackendConfig) newRequest(serverURL *url.URL) (*http.Request, error) {
+	if serverURL.Scheme == unixScheme {
+		return b.newUnixSocketRequest(serverURL)
+	}
+
 	u, err := serverURL.Parse(b.Path)
 	if err != nil {
 		return nil, err
@@ -76,7 +139,86 @@ func (b *BackendConfig) newRequest(serverURL *url.URL) (*http.Request, error) {
 		u.Host = net.JoinHostPort(u.Hostname(), strconv.Itoa(b.Port))
 	}
 
-	return http.NewRequest(http.MethodGet, u.String(), http.NoBody)
+	return http.NewRequest(b.method(), u.String(), http.NoBody)
+}
+
+// newUnixSocketRequest creates the request to a server listening on a unix domain socket, e.g. unix:///run/app.sock.
+// The socket path is the host of the request URL, as expected by the transport, the scheme and port overrides don't apply.
+func (b *BackendConfig) newUnixSocketRequest(serverURL *url.URL) (*http.Request, error) {
+	u, err := (&url.URL{Scheme: "http", Host: "localhost"}).Parse(b.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(b.method(), u.String(), http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	req.URL.Scheme = unixScheme
+	req.URL.Host = serverURL.Path
+	return req, nil
+}
+
+func (b *BackendConfig) method() string {
+	if len(b.Method) > 0 {
+		return b.Method
+	}
+	return http.MethodGet
+}
+
+// countResult counts a check contradicting the current state of the server,
+// and returns the number of consecutive such checks.
+func (b *BackendConfig) countResult(u *url.URL) int {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.consecutive[u.String()]++
+	return b.consecutive[u.String()]
+}
+
+// resetResults resets the count of the checks contradicting the current state of the server.
+func (b *BackendConfig) resetResults(u *url.URL) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	delete(b.consecutive, u.String())
+}
+
+// ForgetServer forgets a server removed from the backend (e.g. no longer discovered):
+// it is no longer checked, nor returned to the load balancer, and its status is removed.
+func (b *BackendConfig) ForgetServer(u *url.URL) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	var disabledURLs []backendURL
+	for _, disabled := range b.disabledURLs {
+		if disabled.url.String() != u.String() {
+			disabledURLs = append(disabledURLs, disabled)
+		}
+	}
+	b.disabledURLs = disabledURLs
+
+	delete(b.consecutive, u.String())
+	delete(b.statuses, u.String())
+	delete(b.ejected, u.String())
+}
+
+// isDisabled returns whether the server was removed from the load balancer by the health check.
+func (b *BackendConfig) isDisabled(u *url.URL) bool {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	return b.isDisabledLocked(u)
+}
+
+func (b *BackendConfig) isDisabledLocked(u *url.URL) bool {
+	for _, disabled := range b.disabledURLs {
+		if disabled.url.String() == u.String() {
+			return true
+		}
+	}
+	return false
 }
 
 // this function adds additional http headers and hostname to http.request
@@ -96,11 +238,23 @@ type HealthCheck struct {
 	Backends map[string]*BackendConfig
 	metrics  metricsRegistry
 	cancel   context.CancelFunc
+
+	lock        sync.RWMutex
+	subscribers map[chan Event]struct{}
 }
 
 // SetBackendsConfiguration set backends configuration
 func (hc *HealthCheck) SetBackendsConfiguration(parentCtx context.Context, backends map[string]*BackendConfig) {
+	hc.lock.Lock()
 	hc.Backends = backends
+	hc.lock.Unlock()
+
+	for _, backend := range backends {
+		backend.lock.Lock()
+		backend.notify = hc.notify
+		backend.lock.Unlock()
+	}
+
 	if hc.cancel != nil {
 		hc.cancel()
 	}
@@ -108,6 +262,10 @@ func (hc *HealthCheck) SetBackendsConfiguration(parentCtx context.Context, backe
 	hc.cancel = cancel
 
 	for _, backend := range backends {
+		if !backend.checked {
+			continue
+		}
+
 		currentBackend := backend
 		safe.Go(func() {
 			hc.execute(ctx, currentBackend)
@@ -134,27 +292,51 @@ func (hc *HealthCheck) execute(ctx context.Context, backend *BackendConfig) {
 
 func (hc *HealthCheck) checkBackend(backend *BackendConfig) {
 	enabledURLs := backend.LB.Servers()
+	states := make(map[string]string)
+	var events []Event
 	var newDisabledURLs []backendURL
-	for _, backendurl := range backend.disabledURLs {
+
+	backend.lock.RLock()
+	disabledURLs := backend.disabledURLs
+	backend.lock.RUnlock()
+
+	for _, backendurl := range disabledURLs {
 		serverUpMetricValue := float64(0)
-		if err := checkHealth(backendurl.url, backend); err == nil {
-			log.Warnf("Health check up: Returning to server list. Backend: %q URL: %q Weight: %d", backend.name, backendurl.url.String(), backendurl.weight)
-			backend.LB.UpsertServer(backendurl.url, roundrobin.Weight(backendurl.weight))
-			serverUpMetricValue = 1
+		if err := backend.check(backendurl.url); err == nil {
+			if count := backend.countResult(backendurl.url); count < backend.Rise {
+				log.Debugf("Health check up %d/%d times. Backend: %q URL: %q", count, backend.Rise, backend.name, backendurl.url.String())
+				newDisabledURLs = append(newDisabledURLs, backendurl)
+			} else {
+				log.Warnf("Health check up: Returning to server list. Backend: %q URL: %q Weight: %d", backend.name, backendurl.url.String(), backendurl.weight)
+				backend.resetResults(backendurl.url)
+				backend.LB.UpsertServer(backendurl.url, roundrobin.Weight(backendurl.weight))
+				events = append(events, newEvent(backend, backendurl.url, StateUp, nil))
+				serverUpMetricValue = 1
+			}
 		} else {
 			log.Warnf("Health check still failing. Backend: %q URL: %q Reason: %s", backend.name, backendurl.url.String(), err)
+			backend.resetResults(backendurl.url)
 			newDisabledURLs = append(newDisabledURLs, backendurl)
 		}
+		states[backendurl.url.String()] = stateFromMetricValue(serverUpMetricValue)
 		labelValues := []string{"backend", backend.name, "url", backendurl.url.String()}
 		hc.metrics.BackendServerUpGauge().With(labelValues...).Set(serverUpMetricValue)
 	}
-	backend.disabledURLs = newDisabledURLs
+	backend.lock.Lock()
+	backend.disabledURLs = retainDisabledURLs(newDisabledURLs, backend.disabledURLs)
+	backend.lock.Unlock()
 
 	for _, url := range enabledURLs {
 		serverUpMetricValue := float64(1)
-		if err := checkHealth(url, backend); err != nil {
+		if err := backend.check(url); err == nil {
+			backend.resetResults(url)
+		} else if count := backend.countResult(url); count < backend.Fall {
+			log.Warnf("Health check failed %d/%d times. Backend: %q URL: %q Reason: %s", count, backend.Fall, backend.name, url.String(), err)
+		} else {
+			backend.resetResults(url)
+
 			weight := 1
-			rr, ok := backend.LB.(*roundrobin.RoundRobin)
+			rr, ok := backend.LB.(weightedBalancer)
 			if ok {
 				var gotWeight bool
 				weight, gotWeight = rr.ServerWeight(url)
@@ -164,12 +346,42 @@ func (hc *HealthCheck) checkBackend(backend *BackendConfig) {
 			}
 			log.Warnf("Health check failed: Remove from server list. Backend: %q URL: %q Weight: %d Reason: %s", backend.name, url.String(), weight, err)
 			backend.LB.RemoveServer(url)
+			backend.lock.Lock()
 			backend.disabledURLs = append(backend.disabledURLs, backendURL{url, weight})
+			backend.lock.Unlock()
+			events = append(events, newEvent(backend, url, StateDown, err))
 			serverUpMetricValue = 0
 		}
+		states[url.String()] = stateFromMetricValue(serverUpMetricValue)
 		labelValues := []string{"backend", backend.name, "url", url.String()}
 		hc.metrics.BackendServerUpGauge().With(labelValues...).Set(serverUpMetricValue)
 	}
+
+	backend.setStates(states)
+	hc.notify(events)
+}
+
+// retainDisabledURLs returns the servers still disabled, without the servers forgotten during the check.
+func retainDisabledURLs(disabledURLs []backendURL, current []backendURL) []backendURL {
+	known := make(map[string]bool, len(current))
+	for _, disabled := range current {
+		known[disabled.url.String()] = true
+	}
+
+	var retained []backendURL
+	for _, disabled := range disabledURLs {
+		if known[disabled.url.String()] {
+			retained = append(retained, disabled)
+		}
+	}
+	return retained
+}
+
+func stateFromMetricValue(serverUpMetricValue float64) string {
+	if serverUpMetricValue == 1 {
+		return StateUp
+	}
+	return StateDown
 }
 
 // GetHealthCheck returns the health check which is guaranteed to be a singleton.
@@ -182,23 +394,59 @@ func GetHealthCheck(metrics metricsRegistry) *HealthCheck {
 
 func newHealthCheck(metrics metricsRegistry) *HealthCheck {
 	return &HealthCheck{
-		Backends: make(map[string]*BackendConfig),
-		metrics:  metrics,
+		Backends:    make(map[string]*BackendConfig),
+		metrics:     metrics,
+		subscribers: make(map[chan Event]struct{}),
 	}
 }
 
 // NewBackendConfig Instantiate a new BackendConfig
 func NewBackendConfig(options Options, backendName string) *BackendConfig {
+	if options.Rise <= 0 {
+		options.Rise = 1
+	}
+	if options.Fall <= 0 {
+		options.Fall = 1
+	}
+
+	requestTimeout := defaultRequestTimeout
+	if options.Timeout > 0 {
+		requestTimeout = options.Timeout
+	}
+
 	return &BackendConfig{
 		Options:        options,
 		name:           backendName,
-		requestTimeout: 5 * time.Second,
+		requestTimeout: requestTimeout,
+		consecutive:    make(map[string]int),
+		checked:        true,
+		statuses:       make(map[string]*ServerStatus),
+		ejected:        make(map[string]bool),
+	}
+}
+
+// NewUncheckedBackendConfig creates the BackendConfig of a backend without active health check,
+// registered for the status of its servers, e.g. the ejections of the passive health check.
+func NewUncheckedBackendConfig(lb BalancerHandler, backendName string) *BackendConfig {
+	return &BackendConfig{
+		Options:     Options{LB: lb},
+		name:        backendName,
+		consecutive: make(map[string]int),
+		statuses:    make(map[string]*ServerStatus),
+		ejected:     make(map[string]bool),
 	}
 }
 
 // checkHealth returns a nil error in case it was successful and otherwise
 // a non-nil error with a meaningful description why the health check failed.
 func checkHealth(serverURL *url.URL, backend *BackendConfig) error {
+	switch backend.Type {
+	case TypeTCP:
+		return checkTCPHealth(serverURL, backend)
+	case TypeGRPC:
+		return checkGRPCHealth(serverURL, backend)
+	}
+
 	req, err := backend.newRequest(serverURL)
 	if err != nil {
 		return fmt.Errorf("failed to create HTTP request: %s", err)
@@ -218,9 +466,24 @@ func checkHealth(serverURL *url.URL, backend *BackendConfig) error {
 
 	defer resp.Body.Close()
 
-	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusBadRequest {
+	if len(backend.StatusCodes) > 0 {
+		if !backend.StatusCodes.Contains(resp.StatusCode) {
+			return fmt.Errorf("received unexpected status code: %v", resp.StatusCode)
+		}
+	} else if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusBadRequest {
 		return fmt.Errorf("received error status code: %v", resp.StatusCode)
 	}
 
+	if backend.BodyRegex != nil {
+		body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+		if err != nil {
+			return fmt.Errorf("failed to read response body: %s", err)
+		}
+
+		if !backend.BodyRegex.Match(body) {
+			return fmt.Errorf("response body doesn't match %q", backend.BodyRegex)
+		}
+	}
+
 	return nil
 }
@@ -5,11 +5,13 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"regexp"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/pteich/traefik/testhelpers"
+	"github.com/pteich/traefik/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/vulcand/oxy/roundrobin"
@@ -27,6 +29,8 @@ func TestSetBackendsConfiguration(t *testing.T) {
 		desc                       string
 		startHealthy               bool
 		healthSequence             []int
+		rise                       int
+		fall                       int
 		expectedNumRemovedServers  int
 		expectedNumUpsertedServers int
 		expectedGaugeValue         float64
@@ -87,6 +91,42 @@ func TestSetBackendsConfiguration(t *testing.T) {
 			expectedNumUpsertedServers: 1,
 			expectedGaugeValue:         1,
 		},
+		{
+			desc:                       "healthy server failing less than fall times",
+			startHealthy:               true,
+			healthSequence:             []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusOK},
+			fall:                       3,
+			expectedNumRemovedServers:  0,
+			expectedNumUpsertedServers: 0,
+			expectedGaugeValue:         1,
+		},
+		{
+			desc:                       "healthy server failing fall times",
+			startHealthy:               true,
+			healthSequence:             []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable},
+			fall:                       2,
+			expectedNumRemovedServers:  1,
+			expectedNumUpsertedServers: 0,
+			expectedGaugeValue:         0,
+		},
+		{
+			desc:                       "sick server succeeding less than rise times",
+			startHealthy:               false,
+			healthSequence:             []int{http.StatusOK, http.StatusServiceUnavailable, http.StatusOK},
+			rise:                       2,
+			expectedNumRemovedServers:  0,
+			expectedNumUpsertedServers: 0,
+			expectedGaugeValue:         0,
+		},
+		{
+			desc:                       "sick server succeeding rise times",
+			startHealthy:               false,
+			healthSequence:             []int{http.StatusOK, http.StatusOK},
+			rise:                       2,
+			expectedNumRemovedServers:  0,
+			expectedNumUpsertedServers: 1,
+			expectedGaugeValue:         1,
+		},
 	}
 
 	for _, test := range testCases {
@@ -106,6 +146,8 @@ func TestSetBackendsConfiguration(t *testing.T) {
 				Path:     "/path",
 				Interval: healthCheckInterval,
 				LB:       lb,
+				Rise:     test.rise,
+				Fall:     test.fall,
 			}, "backendName")
 
 			serverURL := testhelpers.MustParseURL(ts.URL)
@@ -149,6 +191,76 @@ func TestSetBackendsConfiguration(t *testing.T) {
 	}
 }
 
+func TestCheckHealthExpectations(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer ts.Close()
+
+	testCases := []struct {
+		desc        string
+		options     Options
+		expectedErr bool
+	}{
+		{
+			desc:        "default status codes",
+			options:     Options{Path: "/health"},
+			expectedErr: true,
+		},
+		{
+			desc:    "matching status code",
+			options: Options{Path: "/health", StatusCodes: types.HTTPCodeRanges{{200, 299}, {401, 401}}},
+		},
+		{
+			desc:        "not matching status code",
+			options:     Options{Path: "/health", StatusCodes: types.HTTPCodeRanges{{200, 299}}},
+			expectedErr: true,
+		},
+		{
+			desc: "matching body",
+			options: Options{
+				Path:        "/health",
+				StatusCodes: types.HTTPCodeRanges{{401, 401}},
+				BodyRegex:   regexp.MustCompile(`"status":\s*"ok"`),
+			},
+		},
+		{
+			desc: "not matching body",
+			options: Options{
+				Path:        "/health",
+				StatusCodes: types.HTTPCodeRanges{{401, 401}},
+				BodyRegex:   regexp.MustCompile(`"status":\s*"up"`),
+			},
+			expectedErr: true,
+		},
+		{
+			desc: "HEAD method without body",
+			options: Options{
+				Path:        "/health",
+				Method:      http.MethodHead,
+				StatusCodes: types.HTTPCodeRanges{{401, 401}},
+				BodyRegex:   regexp.MustCompile(`ok`),
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			backend := NewBackendConfig(test.options, "backendName")
+
+			err := checkHealth(testhelpers.MustParseURL(ts.URL), backend)
+			if test.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestNewRequest(t *testing.T) {
 	type expected struct {
 		err   bool
@@ -246,6 +358,19 @@ func TestNewRequest(t *testing.T) {
 				value: "http://backend1:80/health?powpow=do&do=powpow",
 			},
 		},
+		{
+			desc:      "unix domain socket",
+			serverURL: "unix:///run/app.sock",
+			options: Options{
+				Scheme: "https",
+				Path:   "/health?powpow=do",
+				Port:   8080,
+			},
+			expected: expected{
+				err:   false,
+				value: "unix://%2Frun%2Fapp.sock/health?powpow=do",
+			},
+		},
 		{
 			desc:      "path with invalid path",
 			serverURL: "http://backend1:80",
@@ -0,0 +1,271 @@
+package healthcheck
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/pteich/traefik/log"
+	"github.com/vulcand/oxy/roundrobin"
+	"github.com/vulcand/oxy/utils"
+)
+
+// maxEjectionTime caps the ejection time of the servers ejected several times in a row,
+// unless the base ejection time is longer.
+const maxEjectionTime = 5 * time.Minute
+
+// PassiveOptions are the passive health check options.
+type PassiveOptions struct {
+	// ConsecutiveErrors is the number of consecutive errors (5xx responses and connection errors) ejecting a server.
+	ConsecutiveErrors int
+	// EjectionTime is the time a server is ejected the first time, it doubles for each new ejection in a row.
+	EjectionTime time.Duration
+	// MaxEjectionPercent is the maximum percentage of the servers of the backend which can be ejected.
+	MaxEjectionPercent int
+}
+
+func (opt PassiveOptions) String() string {
+	return fmt.Sprintf("[ConsecutiveErrors: %d EjectionTime: %s MaxEjectionPercent: %d]", opt.ConsecutiveErrors, opt.EjectionTime, opt.MaxEjectionPercent)
+}
+
+// PassiveHealthCheck checks the responses of the servers of a backend to the live traffic,
+// and ejects from the load balancer the servers returning consecutive errors, for a period growing exponentially.
+// It must wrap the handler forwarding the requests to the servers of the load balancer.
+type PassiveHealthCheck struct {
+	PassiveOptions
+	name    string
+	next    http.Handler
+	metrics metricsRegistry
+
+	mutex   sync.Mutex
+	lb      BalancerHandler
+	backend *BackendConfig
+	servers map[string]*passiveServer
+}
+
+type passiveServer struct {
+	errors  int
+	ejected bool
+	// ejections in a row, reset when the server stays up longer than its last ejection time.
+	ejections    int
+	ejectionTime time.Duration
+	returned     time.Time
+}
+
+// NewPassiveHealthCheck creates a new PassiveHealthCheck.
+func NewPassiveHealthCheck(next http.Handler, options PassiveOptions, backendName string, metrics metricsRegistry) *PassiveHealthCheck {
+	return &PassiveHealthCheck{
+		PassiveOptions: options,
+		name:           backendName,
+		next:           next,
+		metrics:        metrics,
+		servers:        make(map[string]*passiveServer),
+	}
+}
+
+// SetBalancer sets the load balancer the servers are ejected from.
+func (p *PassiveHealthCheck) SetBalancer(lb BalancerHandler) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.lb = lb
+}
+
+// SetBackendConfig sets the health check configuration of the backend, recording the ejections in the status of its servers.
+// The servers removed from the load balancer by the active health check are not returned at the end of their ejection.
+func (p *PassiveHealthCheck) SetBackendConfig(backend *BackendConfig) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.backend = backend
+}
+
+func (p *PassiveHealthCheck) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	serverURL := utils.CopyURL(req.URL)
+
+	recorder := newStatusRecorder(rw)
+	p.next.ServeHTTP(recorder, req)
+
+	// A canceled request (e.g. a hedged request losing the race) says nothing about the server.
+	if req.Context().Err() == context.Canceled {
+		return
+	}
+
+	p.observe(serverURL, recorder.Status())
+}
+
+func (p *PassiveHealthCheck) observe(serverURL *url.URL, status int) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	key := serverURL.String()
+	server, ok := p.servers[key]
+	if !ok {
+		server = &passiveServer{}
+		p.servers[key] = server
+	}
+
+	if server.ejected {
+		// response of a request forwarded before the ejection
+		return
+	}
+
+	if status < http.StatusInternalServerError {
+		server.errors = 0
+		return
+	}
+
+	server.errors++
+	if server.errors < p.ConsecutiveErrors || p.lb == nil {
+		return
+	}
+
+	if !p.canEject() {
+		log.Debugf("Passive health check: too many servers ejected, keeping the server. Backend: %q URL: %q", p.name, key)
+		return
+	}
+
+	p.eject(serverURL, server, status)
+}
+
+// canEject returns whether one more server can be ejected, according to the maximum ejection percentage.
+func (p *PassiveHealthCheck) canEject() bool {
+	ejected := 0
+	for _, server := range p.servers {
+		if server.ejected {
+			ejected++
+		}
+	}
+
+	total := len(p.lb.Servers()) + ejected
+	return (ejected+1)*100 <= p.MaxEjectionPercent*total
+}
+
+func (p *PassiveHealthCheck) eject(serverURL *url.URL, server *passiveServer, status int) {
+	weight := 1
+	if rr, ok := p.lb.(weightedBalancer); ok {
+		if serverWeight, gotWeight := rr.ServerWeight(serverURL); gotWeight {
+			weight = serverWeight
+		}
+	}
+
+	if err := p.lb.RemoveServer(serverURL); err != nil {
+		// the server may have been removed in the meantime, e.g. by the active health check
+		log.Debugf("Passive health check: unable to eject the server. Backend: %q URL: %q Reason: %v", p.name, serverURL, err)
+		return
+	}
+
+	if server.ejections > 0 && time.Since(server.returned) > server.ejectionTime {
+		server.ejections = 0
+	}
+	server.ejections++
+
+	server.ejectionTime = p.EjectionTime << uint(server.ejections-1)
+	if limit := maxDuration(p.EjectionTime, maxEjectionTime); server.ejectionTime > limit || server.ejectionTime <= 0 {
+		server.ejectionTime = limit
+	}
+
+	server.ejected = true
+	server.errors = 0
+
+	log.Warnf("Passive health check failed: Eject from server list. Backend: %q URL: %q Weight: %d Ejection time: %s", p.name, serverURL, weight, server.ejectionTime)
+	p.metrics.BackendServerUpGauge().With("backend", p.name, "url", serverURL.String()).Set(0)
+
+	if p.backend != nil {
+		p.backend.eject(serverURL, fmt.Errorf("ejected by the passive health check for %s after %d consecutive errors, last status code: %d",
+			server.ejectionTime, p.ConsecutiveErrors, status))
+	}
+
+	time.AfterFunc(server.ejectionTime, func() {
+		p.restore(serverURL, server, weight)
+	})
+}
+
+func (p *PassiveHealthCheck) restore(serverURL *url.URL, server *passiveServer, weight int) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	server.ejected = false
+	server.returned = time.Now()
+
+	if p.backend != nil && !p.backend.restore(serverURL) {
+		log.Debugf("Passive health check ejection over, but the server is down for the active health check. Backend: %q URL: %q", p.name, serverURL)
+		return
+	}
+
+	log.Warnf("Passive health check ejection over: Returning to server list. Backend: %q URL: %q Weight: %d", p.name, serverURL, weight)
+	if err := p.lb.UpsertServer(serverURL, roundrobin.Weight(weight)); err != nil {
+		log.Errorf("Passive health check: unable to return the server. Backend: %q URL: %q Reason: %v", p.name, serverURL, err)
+		return
+	}
+
+	p.metrics.BackendServerUpGauge().With("backend", p.name, "url", serverURL.String()).Set(1)
+}
+
+func maxDuration(a, b time.Duration) time.Duration {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// statusRecorder records the status code of the response.
+type statusRecorder interface {
+	http.ResponseWriter
+	Status() int
+}
+
+type statusRecorderWithoutCloseNotify struct {
+	http.ResponseWriter
+	status int
+}
+
+// newStatusRecorder returns an initialized statusRecorder.
+func newStatusRecorder(rw http.ResponseWriter) statusRecorder {
+	recorder := &statusRecorderWithoutCloseNotify{ResponseWriter: rw, status: http.StatusOK}
+	if _, ok := rw.(http.CloseNotifier); ok {
+		return &statusRecorderWithCloseNotify{recorder}
+	}
+	return recorder
+}
+
+// WriteHeader captures the status code for later retrieval.
+func (s *statusRecorderWithoutCloseNotify) WriteHeader(status int) {
+	s.status = status
+	s.ResponseWriter.WriteHeader(status)
+}
+
+// Status gets the response status.
+func (s *statusRecorderWithoutCloseNotify) Status() int {
+	return s.status
+}
+
+// Hijack hijacks the connection.
+func (s *statusRecorderWithoutCloseNotify) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := s.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("%T is not a http.Hijacker", s.ResponseWriter)
+	}
+	return hijacker.Hijack()
+}
+
+// Flush sends any buffered data to the client.
+func (s *statusRecorderWithoutCloseNotify) Flush() {
+	if flusher, ok := s.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+type statusRecorderWithCloseNotify struct {
+	*statusRecorderWithoutCloseNotify
+}
+
+// CloseNotify returns a channel that receives at most a single value (true) when the client connection has gone away.
+func (s *statusRecorderWithCloseNotify) CloseNotify() <-chan bool {
+	return s.ResponseWriter.(http.CloseNotifier).CloseNotify()
+}
@@ -0,0 +1,162 @@
+package healthcheck
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pteich/traefik/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+func TestPassiveHealthCheckEjection(t *testing.T) {
+	metrics := testhelpers.NewCollectingHealthCheckMetrics()
+	passive, lb := newTestPassiveHealthCheck(t, metrics, map[string]int{"b": http.StatusBadGateway}, "a", "b", "c")
+
+	// b gets a request out of three
+	for i := 0; i < 6; i++ {
+		lb.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+	}
+
+	assert.Equal(t, []string{"http://a", "http://c"}, serverURLs(lb))
+
+	passive.mutex.Lock()
+	assert.Equal(t, []string{"backend", "backendName", "url", "http://b"}, metrics.Gauge.LastLabelValues)
+	assert.Equal(t, float64(0), metrics.Gauge.GaugeValue)
+	passive.mutex.Unlock()
+
+	assert.Eventually(t, func() bool {
+		return len(lb.Servers()) == 3
+	}, time.Second, 10*time.Millisecond)
+
+	passive.mutex.Lock()
+	assert.Equal(t, float64(1), metrics.Gauge.GaugeValue)
+	passive.mutex.Unlock()
+}
+
+func TestPassiveHealthCheckConsecutiveErrors(t *testing.T) {
+	passive, lb := newTestPassiveHealthCheck(t, testhelpers.NewCollectingHealthCheckMetrics(), nil, "a", "b")
+	a := testhelpers.MustParseURL("http://a")
+
+	passive.observe(a, http.StatusInternalServerError)
+	passive.observe(a, http.StatusOK)
+	passive.observe(a, http.StatusServiceUnavailable)
+	assert.Len(t, lb.Servers(), 2)
+
+	// The client errors are not counted.
+	passive.observe(a, http.StatusNotFound)
+	passive.observe(a, http.StatusGatewayTimeout)
+	assert.Len(t, lb.Servers(), 2)
+
+	passive.observe(a, http.StatusGatewayTimeout)
+	assert.Equal(t, []string{"http://b"}, serverURLs(lb))
+}
+
+func TestPassiveHealthCheckMaxEjectionPercent(t *testing.T) {
+	passive, lb := newTestPassiveHealthCheck(t, testhelpers.NewCollectingHealthCheckMetrics(), nil, "a", "b")
+
+	for _, u := range []string{"http://a", "http://b"} {
+		for i := 0; i < 2; i++ {
+			passive.observe(testhelpers.MustParseURL(u), http.StatusBadGateway)
+		}
+	}
+
+	assert.Equal(t, []string{"http://b"}, serverURLs(lb))
+}
+
+func TestPassiveHealthCheckEjectionTime(t *testing.T) {
+	passive, lb := newTestPassiveHealthCheck(t, testhelpers.NewCollectingHealthCheckMetrics(), nil, "a", "b")
+	a := testhelpers.MustParseURL("http://a")
+
+	ejectionTime := func() time.Duration {
+		passive.mutex.Lock()
+		defer passive.mutex.Unlock()
+		return passive.servers["http://a"].ejectionTime
+	}
+
+	// The ejection time doubles while the server keeps failing when it gets back.
+	for _, expected := range []time.Duration{50 * time.Millisecond, 100 * time.Millisecond} {
+		passive.observe(a, http.StatusBadGateway)
+		passive.observe(a, http.StatusBadGateway)
+		require.Len(t, lb.Servers(), 1)
+		assert.Equal(t, expected, ejectionTime())
+
+		assert.Eventually(t, func() bool {
+			return len(lb.Servers()) == 2
+		}, time.Second, 10*time.Millisecond)
+	}
+
+	// The ejection time starts over once the server stayed up longer than its last ejection time.
+	time.Sleep(150 * time.Millisecond)
+	passive.observe(a, http.StatusBadGateway)
+	passive.observe(a, http.StatusBadGateway)
+	assert.Equal(t, 50*time.Millisecond, ejectionTime())
+}
+
+func TestPassiveHealthCheckActiveDisabled(t *testing.T) {
+	metrics := testhelpers.NewCollectingHealthCheckMetrics()
+	passive, lb := newTestPassiveHealthCheck(t, metrics, nil, "a", "b")
+	a := testhelpers.MustParseURL("http://a")
+
+	active := NewBackendConfig(Options{}, "backendName")
+	passive.SetBackendConfig(active)
+
+	passive.observe(a, http.StatusBadGateway)
+	passive.observe(a, http.StatusBadGateway)
+	require.Equal(t, []string{"http://b"}, serverURLs(lb))
+
+	// The active health check removes the server while it is ejected.
+	active.lock.Lock()
+	active.disabledURLs = append(active.disabledURLs, backendURL{url: a, weight: 1})
+	active.lock.Unlock()
+
+	assert.Eventually(t, func() bool {
+		passive.mutex.Lock()
+		defer passive.mutex.Unlock()
+		return !passive.servers["http://a"].ejected
+	}, time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, []string{"http://b"}, serverURLs(lb))
+
+	passive.mutex.Lock()
+	assert.Equal(t, float64(0), metrics.Gauge.GaugeValue)
+	passive.mutex.Unlock()
+}
+
+func newTestPassiveHealthCheck(t *testing.T, metrics metricsRegistry, statuses map[string]int, hosts ...string) (*PassiveHealthCheck, *roundrobin.RoundRobin) {
+	t.Helper()
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if status, ok := statuses[req.URL.Host]; ok {
+			rw.WriteHeader(status)
+		}
+	})
+
+	passive := NewPassiveHealthCheck(next, PassiveOptions{
+		ConsecutiveErrors:  2,
+		EjectionTime:       50 * time.Millisecond,
+		MaxEjectionPercent: 50,
+	}, "backendName", metrics)
+
+	lb, err := roundrobin.New(passive)
+	require.NoError(t, err)
+
+	for _, host := range hosts {
+		require.NoError(t, lb.UpsertServer(testhelpers.MustParseURL("http://"+host)))
+	}
+
+	passive.SetBalancer(lb)
+
+	return passive, lb
+}
+
+func serverURLs(lb BalancerHandler) []string {
+	var urls []string
+	for _, u := range lb.Servers() {
+		urls = append(urls, u.String())
+	}
+	return urls
+}
@@ -0,0 +1,275 @@
+package healthcheck
+
+import (
+	"net/url"
+	"sort"
+	"time"
+)
+
+// States of the servers.
+const (
+	// StateUp is the state of the servers in the load balancer.
+	StateUp = "up"
+	// StateDown is the state of the servers removed from the load balancer by the active or the passive health check.
+	StateDown = "down"
+)
+
+// eventsBufferSize is the number of events buffered for each subscriber, the events are dropped for the slower subscribers.
+const eventsBufferSize = 32
+
+// ServerStatus is the state of a server, and the result of its last health check.
+type ServerStatus struct {
+	URL       string    `json:"url"`
+	State     string    `json:"state"`
+	LastCheck time.Time `json:"last_check"`
+	Latency   string    `json:"latency"`
+	// LastError is the error of the last failed health check, even if the server recovered since.
+	LastError     string     `json:"last_error,omitempty"`
+	LastErrorTime *time.Time `json:"last_error_time,omitempty"`
+}
+
+// Event is a transition of a server to the up or down state.
+type Event struct {
+	Backend string    `json:"backend"`
+	URL     string    `json:"url"`
+	State   string    `json:"state"`
+	Time    time.Time `json:"time"`
+	Error   string    `json:"error,omitempty"`
+}
+
+// check runs the health check of a server, and records the time, latency and error of the probe.
+func (b *BackendConfig) check(u *url.URL) error {
+	start := time.Now()
+	err := checkHealth(u, b)
+	latency := time.Since(start)
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	status := b.getStatus(u)
+	status.LastCheck = start
+	status.Latency = latency.String()
+	if err != nil {
+		status.LastError = err.Error()
+		status.LastErrorTime = &start
+	}
+
+	return err
+}
+
+// setStates sets the state of the checked servers, and forgets the servers no longer in the backend.
+// The servers ejected by the passive health check are not checked, they stay down.
+func (b *BackendConfig) setStates(states map[string]string) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	for rawURL, status := range b.statuses {
+		state, ok := states[rawURL]
+		if !ok {
+			if b.ejected[rawURL] {
+				status.State = StateDown
+				continue
+			}
+			delete(b.statuses, rawURL)
+			continue
+		}
+		status.State = state
+	}
+}
+
+// eject records the ejection of a server by the passive health check, and notifies it.
+func (b *BackendConfig) eject(u *url.URL, err error) {
+	b.lock.Lock()
+
+	now := time.Now()
+	status := b.getStatus(u)
+	status.State = StateDown
+	status.LastError = err.Error()
+	status.LastErrorTime = &now
+	b.ejected[u.String()] = true
+
+	notify := b.notify
+	b.lock.Unlock()
+
+	if notify != nil {
+		notify([]Event{newEvent(b, u, StateDown, err)})
+	}
+}
+
+// restore records the end of the ejection of a server by the passive health check, and notifies it
+// unless the server is down for the active health check.
+// It returns false when the server is down for the active health check.
+func (b *BackendConfig) restore(u *url.URL) bool {
+	b.lock.Lock()
+
+	delete(b.ejected, u.String())
+	if b.isDisabledLocked(u) {
+		b.lock.Unlock()
+		return false
+	}
+
+	b.getStatus(u).State = StateUp
+
+	notify := b.notify
+	b.lock.Unlock()
+
+	if notify != nil {
+		notify([]Event{newEvent(b, u, StateUp, nil)})
+	}
+	return true
+}
+
+// getStatus returns the status of the server, creating it if needed. The lock must be held.
+func (b *BackendConfig) getStatus(u *url.URL) *ServerStatus {
+	status, ok := b.statuses[u.String()]
+	if !ok {
+		status = &ServerStatus{URL: u.String()}
+		b.statuses[u.String()] = status
+	}
+	return status
+}
+
+// Statuses returns the status of the servers of the backend, sorted by URL.
+// Without active health check, the servers of the load balancer are up, and the servers ejected by the passive health check are down.
+func (b *BackendConfig) Statuses() []ServerStatus {
+	if !b.checked {
+		return b.uncheckedStatuses()
+	}
+
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	statuses := make([]ServerStatus, 0, len(b.statuses))
+	for _, status := range b.statuses {
+		// The state of the servers checked for the first time is not known yet.
+		if status.State == "" {
+			continue
+		}
+		statuses = append(statuses, *status)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].URL < statuses[j].URL
+	})
+
+	return statuses
+}
+
+func (b *BackendConfig) uncheckedStatuses() []ServerStatus {
+	var servers []*url.URL
+	if b.LB != nil {
+		servers = b.LB.Servers()
+	}
+
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	byURL := make(map[string]ServerStatus)
+	for _, u := range servers {
+		status := ServerStatus{URL: u.String()}
+		if known, ok := b.statuses[u.String()]; ok {
+			status = *known
+		}
+		status.State = StateUp
+		byURL[status.URL] = status
+	}
+
+	for rawURL := range b.ejected {
+		if status, ok := b.statuses[rawURL]; ok {
+			byURL[rawURL] = *status
+		}
+	}
+
+	statuses := make([]ServerStatus, 0, len(byURL))
+	for _, status := range byURL {
+		statuses = append(statuses, status)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].URL < statuses[j].URL
+	})
+
+	return statuses
+}
+
+// BackendStatuses returns the status of the servers of the backends, by backend name.
+// When a backend is checked for several frontends or entry points, the status of the last checked server is kept.
+func (hc *HealthCheck) BackendStatuses() map[string][]ServerStatus {
+	hc.lock.RLock()
+	defer hc.lock.RUnlock()
+
+	byBackend := make(map[string]map[string]ServerStatus)
+	for _, backend := range hc.Backends {
+		servers, ok := byBackend[backend.name]
+		if !ok {
+			servers = make(map[string]ServerStatus)
+			byBackend[backend.name] = servers
+		}
+
+		for _, status := range backend.Statuses() {
+			if previous, ok := servers[status.URL]; !ok || status.LastCheck.After(previous.LastCheck) {
+				servers[status.URL] = status
+			}
+		}
+	}
+
+	statuses := make(map[string][]ServerStatus)
+	for name, servers := range byBackend {
+		statuses[name] = make([]ServerStatus, 0, len(servers))
+		for _, status := range servers {
+			statuses[name] = append(statuses[name], status)
+		}
+
+		sort.Slice(statuses[name], func(i, j int) bool {
+			return statuses[name][i].URL < statuses[name][j].URL
+		})
+	}
+
+	return statuses
+}
+
+// Subscribe returns a channel receiving the transitions of the servers, until Unsubscribe is called.
+func (hc *HealthCheck) Subscribe() chan Event {
+	hc.lock.Lock()
+	defer hc.lock.Unlock()
+
+	events := make(chan Event, eventsBufferSize)
+	hc.subscribers[events] = struct{}{}
+	return events
+}
+
+// Unsubscribe stops sending the transitions of the servers to the channel.
+func (hc *HealthCheck) Unsubscribe(events chan Event) {
+	hc.lock.Lock()
+	defer hc.lock.Unlock()
+
+	delete(hc.subscribers, events)
+}
+
+func newEvent(backend *BackendConfig, u *url.URL, state string, err error) Event {
+	event := Event{
+		Backend: backend.name,
+		URL:     u.String(),
+		State:   state,
+		Time:    time.Now(),
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	return event
+}
+
+// notify sends the transitions of the servers to the subscribers, without waiting for the slower ones.
+func (hc *HealthCheck) notify(events []Event) {
+	hc.lock.RLock()
+	defer hc.lock.RUnlock()
+
+	for subscriber := range hc.subscribers {
+		for _, event := range events {
+			select {
+			case subscriber <- event:
+			default:
+			}
+		}
+	}
+}
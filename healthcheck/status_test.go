@@ -0,0 +1,153 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pteich/traefik/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckBackendStatusesAndEvents(t *testing.T) {
+	status := http.StatusServiceUnavailable
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer ts.Close()
+
+	serverURL := testhelpers.MustParseURL(ts.URL)
+	lb := &testLoadBalancer{RWMutex: &sync.RWMutex{}, servers: []*url.URL{serverURL}}
+	backend := NewBackendConfig(Options{Path: "/health", LB: lb}, "backendName")
+
+	hc := newHealthCheck(testhelpers.NewCollectingHealthCheckMetrics())
+	hc.Backends = map[string]*BackendConfig{"backendName": backend}
+
+	events := hc.Subscribe()
+	defer hc.Unsubscribe(events)
+
+	hc.checkBackend(backend)
+
+	require.Len(t, events, 1)
+	event := <-events
+	assert.Equal(t, "backendName", event.Backend)
+	assert.Equal(t, ts.URL, event.URL)
+	assert.Equal(t, StateDown, event.State)
+	assert.Contains(t, event.Error, "503")
+
+	statuses := hc.BackendStatuses()
+	require.Len(t, statuses["backendName"], 1)
+	down := statuses["backendName"][0]
+	assert.Equal(t, ts.URL, down.URL)
+	assert.Equal(t, StateDown, down.State)
+	assert.False(t, down.LastCheck.IsZero())
+	assert.NotEmpty(t, down.Latency)
+	assert.Contains(t, down.LastError, "503")
+	require.NotNil(t, down.LastErrorTime)
+
+	status = http.StatusOK
+	hc.checkBackend(backend)
+
+	require.Len(t, events, 1)
+	event = <-events
+	assert.Equal(t, StateUp, event.State)
+	assert.Empty(t, event.Error)
+
+	statuses = hc.BackendStatuses()
+	require.Len(t, statuses["backendName"], 1)
+	up := statuses["backendName"][0]
+	assert.Equal(t, StateUp, up.State)
+	assert.True(t, up.LastCheck.After(down.LastCheck))
+	// The error of the last failed check is kept.
+	assert.Equal(t, down.LastError, up.LastError)
+	assert.Equal(t, down.LastErrorTime, up.LastErrorTime)
+
+	// The servers no longer in the backend are forgotten.
+	lb.servers = nil
+	hc.checkBackend(backend)
+
+	assert.Empty(t, hc.BackendStatuses()["backendName"])
+}
+
+func TestPassiveEjectionStatusesAndEvents(t *testing.T) {
+	passive, lb := newTestPassiveHealthCheck(t, testhelpers.NewCollectingHealthCheckMetrics(), nil, "a", "b")
+	a := testhelpers.MustParseURL("http://a")
+
+	// The backends without active health check are registered for the status of their servers.
+	backend := NewUncheckedBackendConfig(lb, "backendName")
+	passive.SetBackendConfig(backend)
+
+	hc := newHealthCheck(testhelpers.NewCollectingHealthCheckMetrics())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	hc.SetBackendsConfiguration(ctx, map[string]*BackendConfig{"backendName": backend})
+
+	events := hc.Subscribe()
+	defer hc.Unsubscribe(events)
+
+	assert.Equal(t, []ServerStatus{{URL: "http://a", State: StateUp}, {URL: "http://b", State: StateUp}}, hc.BackendStatuses()["backendName"])
+
+	passive.observe(a, http.StatusBadGateway)
+	passive.observe(a, http.StatusBadGateway)
+
+	require.Len(t, events, 1)
+	event := <-events
+	assert.Equal(t, "http://a", event.URL)
+	assert.Equal(t, StateDown, event.State)
+	assert.Contains(t, event.Error, "502")
+
+	statuses := hc.BackendStatuses()["backendName"]
+	require.Len(t, statuses, 2)
+	assert.Equal(t, StateDown, statuses[0].State)
+	assert.Contains(t, statuses[0].LastError, "passive health check")
+	assert.Equal(t, StateUp, statuses[1].State)
+
+	// The server returns at the end of its ejection.
+	select {
+	case event = <-events:
+		assert.Equal(t, "http://a", event.URL)
+		assert.Equal(t, StateUp, event.State)
+	case <-time.After(time.Second):
+		t.Fatal("ejection not over")
+	}
+
+	require.Eventually(t, func() bool {
+		return len(lb.Servers()) == 2
+	}, time.Second, 10*time.Millisecond)
+
+	statuses = hc.BackendStatuses()["backendName"]
+	require.Len(t, statuses, 2)
+	assert.Equal(t, StateUp, statuses[0].State)
+	assert.NotEmpty(t, statuses[0].LastError)
+}
+
+func TestCheckBackendKeepsEjectedServers(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	serverURL := testhelpers.MustParseURL(ts.URL)
+	lb := &testLoadBalancer{RWMutex: &sync.RWMutex{}, servers: []*url.URL{serverURL}}
+	backend := NewBackendConfig(Options{Path: "/health", LB: lb}, "backendName")
+
+	hc := newHealthCheck(testhelpers.NewCollectingHealthCheckMetrics())
+	hc.Backends = map[string]*BackendConfig{"backendName": backend}
+
+	hc.checkBackend(backend)
+	require.Len(t, hc.BackendStatuses()["backendName"], 1)
+
+	// The server ejected by the passive health check is not checked, but stays down in the statuses.
+	backend.eject(serverURL, errors.New("ejected"))
+	lb.servers = nil
+	hc.checkBackend(backend)
+
+	statuses := hc.BackendStatuses()["backendName"]
+	require.Len(t, statuses, 1)
+	assert.Equal(t, StateDown, statuses[0].State)
+	assert.Equal(t, "ejected", statuses[0].LastError)
+}
@@ -0,0 +1,70 @@
+package healthcheck
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+)
+
+// checkTCPHealth returns a nil error if a connection to the server can be established,
+// with a TLS handshake when the server uses TLS.
+func checkTCPHealth(serverURL *url.URL, backend *BackendConfig) error {
+	dialer := &net.Dialer{Timeout: backend.requestTimeout}
+
+	if serverURL.Scheme == unixScheme {
+		conn, err := dialer.Dial(unixScheme, serverURL.Path)
+		if err != nil {
+			return fmt.Errorf("connection failed: %s", err)
+		}
+		return conn.Close()
+	}
+
+	scheme := serverURL.Scheme
+	if len(backend.Scheme) > 0 {
+		scheme = backend.Scheme
+	}
+
+	port := serverURL.Port()
+	if backend.Port != 0 {
+		port = strconv.Itoa(backend.Port)
+	}
+	if port == "" {
+		port = defaultPort(scheme)
+	}
+
+	addr := net.JoinHostPort(serverURL.Hostname(), port)
+
+	if scheme != "https" {
+		conn, err := dialer.Dial("tcp", addr)
+		if err != nil {
+			return fmt.Errorf("connection failed: %s", err)
+		}
+		return conn.Close()
+	}
+
+	tlsConfig := &tls.Config{}
+	if backend.TLSConfig != nil {
+		tlsConfig = backend.TLSConfig.Clone()
+	}
+	if tlsConfig.ServerName == "" {
+		tlsConfig.ServerName = serverURL.Hostname()
+		if backend.Hostname != "" {
+			tlsConfig.ServerName = backend.Hostname
+		}
+	}
+
+	conn, err := tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
+	if err != nil {
+		return fmt.Errorf("TLS connection failed: %s", err)
+	}
+	return conn.Close()
+}
+
+func defaultPort(scheme string) string {
+	if scheme == "https" {
+		return "443"
+	}
+	return "80"
+}
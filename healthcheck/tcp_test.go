@@ -0,0 +1,83 @@
+package healthcheck
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/pteich/traefik/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckTCPHealth(t *testing.T) {
+	tcpServer := httptest.NewServer(http.NotFoundHandler())
+	defer tcpServer.Close()
+
+	tlsServer := httptest.NewTLSServer(http.NotFoundHandler())
+	defer tlsServer.Close()
+
+	// A free port, nothing listens on it.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	closedPort := listener.Addr().(*net.TCPAddr).Port
+	require.NoError(t, listener.Close())
+
+	tcpPort := tcpServer.Listener.Addr().(*net.TCPAddr).Port
+
+	testCases := []struct {
+		desc        string
+		serverURL   string
+		options     Options
+		expectedErr bool
+	}{
+		{
+			desc:      "connection succeeds",
+			serverURL: tcpServer.URL,
+		},
+		{
+			desc:        "connection refused",
+			serverURL:   "http://127.0.0.1:" + strconv.Itoa(closedPort),
+			expectedErr: true,
+		},
+		{
+			desc:      "port override",
+			serverURL: "http://127.0.0.1:" + strconv.Itoa(closedPort),
+			options:   Options{Port: tcpPort},
+		},
+		{
+			desc:      "TLS handshake succeeds",
+			serverURL: tlsServer.URL,
+			options:   Options{TLSConfig: &tls.Config{InsecureSkipVerify: true}},
+		},
+		{
+			desc:        "TLS handshake fails with an untrusted certificate",
+			serverURL:   tlsServer.URL,
+			expectedErr: true,
+		},
+		{
+			desc:        "TLS handshake fails on a server without TLS",
+			serverURL:   tcpServer.URL,
+			options:     Options{Scheme: "https", TLSConfig: &tls.Config{InsecureSkipVerify: true}},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			test.options.Type = TypeTCP
+			backend := NewBackendConfig(test.options, "backendName")
+
+			err := checkHealth(testhelpers.MustParseURL(test.serverURL), backend)
+			if test.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package hostresolver
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// defaultResolvConfig is the resolver configuration file used when none is set.
+const defaultResolvConfig = "/etc/resolv.conf"
+
+// lookupTimeout is the timeout of the lookups of the server addresses, per name server.
+const lookupTimeout = 5 * time.Second
+
+// Address is the address of a server, resolved from DNS records.
+type Address struct {
+	Host   string
+	Port   int
+	Weight int
+}
+
+// LookupSRV resolves the SRV records of the name into the addresses of the servers of lowest priority,
+// and returns them with the lowest TTL of the records.
+func (hr *Resolver) LookupSRV(name string) ([]Address, time.Duration, error) {
+	resp, err := hr.lookup(name, dns.TypeSRV)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	addresses, ttl := srvAddresses(resp.Answer)
+	return addresses, ttl, nil
+}
+
+// LookupHost resolves the A and AAAA records of the name into the addresses of the servers listening on the port,
+// and returns them with the lowest TTL of the records.
+func (hr *Resolver) LookupHost(name string, port int) ([]Address, time.Duration, error) {
+	var records []dns.RR
+	for _, qtype := range []uint16{dns.TypeA, dns.TypeAAAA} {
+		resp, err := hr.lookup(name, qtype)
+		if err != nil {
+			return nil, 0, err
+		}
+		records = append(records, resp.Answer...)
+	}
+
+	addresses, ttl := hostAddresses(records, port)
+	return addresses, ttl, nil
+}
+
+// lookup sends the query to the name servers of the resolver configuration, and returns the first answer.
+func (hr *Resolver) lookup(name string, qtype uint16) (*dns.Msg, error) {
+	resolvConfig := hr.ResolvConfig
+	if resolvConfig == "" {
+		resolvConfig = defaultResolvConfig
+	}
+
+	config, err := dns.ClientConfigFromFile(resolvConfig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid resolver configuration file: %s", resolvConfig)
+	}
+
+	client := &dns.Client{Timeout: lookupTimeout}
+
+	m := &dns.Msg{}
+	m.SetQuestion(dns.Fqdn(name), qtype)
+
+	err = errors.New("no name server")
+	for _, server := range config.Servers {
+		var resp *dns.Msg
+		resp, _, err = client.Exchange(m, net.JoinHostPort(server, config.Port))
+		if err != nil {
+			err = fmt.Errorf("exchange error for server %s: %v", server, err)
+			continue
+		}
+
+		if resp.Rcode != dns.RcodeSuccess && resp.Rcode != dns.RcodeNameError {
+			err = fmt.Errorf("%s answer for server %s", dns.RcodeToString[resp.Rcode], server)
+			continue
+		}
+
+		return resp, nil
+	}
+
+	return nil, fmt.Errorf("failed to resolve %s %s: %v", dns.TypeToString[qtype], name, err)
+}
+
+// srvAddresses returns the addresses of the SRV records of lowest priority, and the lowest TTL of the records.
+func srvAddresses(records []dns.RR) ([]Address, time.Duration) {
+	var srvs []*dns.SRV
+	for _, record := range records {
+		srv, ok := record.(*dns.SRV)
+		if !ok {
+			continue
+		}
+
+		if len(srvs) > 0 && srv.Priority > srvs[0].Priority {
+			continue
+		}
+		if len(srvs) > 0 && srv.Priority < srvs[0].Priority {
+			srvs = nil
+		}
+		srvs = append(srvs, srv)
+	}
+
+	var addresses []Address
+	var ttl uint32
+	for i, srv := range srvs {
+		if i == 0 || srv.Hdr.Ttl < ttl {
+			ttl = srv.Hdr.Ttl
+		}
+
+		addresses = append(addresses, Address{
+			Host:   strings.TrimSuffix(srv.Target, "."),
+			Port:   int(srv.Port),
+			Weight: int(srv.Weight),
+		})
+	}
+
+	return addresses, time.Duration(ttl) * time.Second
+}
+
+// hostAddresses returns the addresses of the A and AAAA records with the port, and the lowest TTL of the records.
+func hostAddresses(records []dns.RR, port int) ([]Address, time.Duration) {
+	var addresses []Address
+	var ttl uint32
+	for _, record := range records {
+		var ip net.IP
+		switch rr := record.(type) {
+		case *dns.A:
+			ip = rr.A
+		case *dns.AAAA:
+			ip = rr.AAAA
+		default:
+			continue
+		}
+
+		if len(addresses) == 0 || record.Header().Ttl < ttl {
+			ttl = record.Header().Ttl
+		}
+
+		addresses = append(addresses, Address{Host: ip.String(), Port: port, Weight: 1})
+	}
+
+	return addresses, time.Duration(ttl) * time.Second
+}
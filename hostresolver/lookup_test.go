@@ -0,0 +1,89 @@
+package hostresolver
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSRVAddresses(t *testing.T) {
+	testCases := []struct {
+		desc              string
+		records           []dns.RR
+		expectedAddresses []Address
+		expectedTTL       time.Duration
+	}{
+		{
+			desc: "no records",
+		},
+		{
+			desc: "records of same priority",
+			records: []dns.RR{
+				newSRV("app1.example.com.", 8080, 10, 5, 60),
+				newSRV("app2.example.com.", 8081, 10, 1, 30),
+			},
+			expectedAddresses: []Address{
+				{Host: "app1.example.com", Port: 8080, Weight: 5},
+				{Host: "app2.example.com", Port: 8081, Weight: 1},
+			},
+			expectedTTL: 30 * time.Second,
+		},
+		{
+			desc: "only the records of lowest priority",
+			records: []dns.RR{
+				newSRV("backup.example.com.", 8080, 20, 1, 10),
+				newSRV("app1.example.com.", 8080, 10, 1, 60),
+				&dns.CNAME{Hdr: dns.RR_Header{Ttl: 5}, Target: "other.example.com."},
+				newSRV("app2.example.com.", 8080, 10, 1, 60),
+			},
+			expectedAddresses: []Address{
+				{Host: "app1.example.com", Port: 8080, Weight: 1},
+				{Host: "app2.example.com", Port: 8080, Weight: 1},
+			},
+			expectedTTL: 60 * time.Second,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			addresses, ttl := srvAddresses(test.records)
+			assert.Equal(t, test.expectedAddresses, addresses)
+			assert.Equal(t, test.expectedTTL, ttl)
+		})
+	}
+}
+
+func TestHostAddresses(t *testing.T) {
+	records := []dns.RR{
+		&dns.CNAME{Hdr: dns.RR_Header{Ttl: 5}, Target: "app.example.com."},
+		&dns.A{Hdr: dns.RR_Header{Ttl: 60}, A: net.ParseIP("10.0.0.1")},
+		&dns.A{Hdr: dns.RR_Header{Ttl: 0}, A: net.ParseIP("10.0.0.2")},
+		&dns.AAAA{Hdr: dns.RR_Header{Ttl: 30}, AAAA: net.ParseIP("fd00::1")},
+	}
+
+	addresses, ttl := hostAddresses(records, 8080)
+
+	expected := []Address{
+		{Host: "10.0.0.1", Port: 8080, Weight: 1},
+		{Host: "10.0.0.2", Port: 8080, Weight: 1},
+		{Host: "fd00::1", Port: 8080, Weight: 1},
+	}
+	assert.Equal(t, expected, addresses)
+	assert.Equal(t, time.Duration(0), ttl)
+}
+
+func newSRV(target string, port, priority, weight uint16, ttl uint32) *dns.SRV {
+	return &dns.SRV{
+		Hdr:      dns.RR_Header{Ttl: ttl},
+		Target:   target,
+		Port:     port,
+		Priority: priority,
+		Weight:   weight,
+	}
+}
@@ -23,6 +23,9 @@ const (
 	ddMetricsBackendReqsName      = "backend.request.total"
 	ddMetricsBackendLatencyName   = "backend.request.duration"
 	ddRetriesTotalName            = "backend.retries.total"
+	ddHedgesTotalName             = "backend.hedges.total"
+	ddMirrorsDroppedTotalName     = "backend.mirrors.dropped.total"
+	ddConcurrencyLimitName        = "backend.concurrency.limit"
 	ddConfigReloadsName           = "config.reload.total"
 	ddConfigReloadsFailureTagName = "failure"
 	ddLastConfigReloadSuccessName = "config.reload.lastSuccessTimestamp"
@@ -52,6 +55,9 @@ func RegisterDatadog(config *types.Datadog) Registry {
 		backendReqsCounter:             datadogClient.NewCounter(ddMetricsBackendReqsName, 1.0),
 		backendReqDurationHistogram:    datadogClient.NewHistogram(ddMetricsBackendLatencyName, 1.0),
 		backendRetriesCounter:          datadogClient.NewCounter(ddRetriesTotalName, 1.0),
+		backendHedgesCounter:           datadogClient.NewCounter(ddHedgesTotalName, 1.0),
+		backendMirrorsDroppedCounter:   datadogClient.NewCounter(ddMirrorsDroppedTotalName, 1.0),
+		backendConcurrencyLimitGauge:   datadogClient.NewGauge(ddConcurrencyLimitName),
 		backendOpenConnsGauge:          datadogClient.NewGauge(ddOpenConnsName),
 		backendServerUpGauge:           datadogClient.NewGauge(ddServerUpName),
 	}
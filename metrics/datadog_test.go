@@ -27,6 +27,9 @@ func TestDatadog(t *testing.T) {
 		"traefik.backend.request.total:1.000000|c|#service:test,code:404,method:GET\n",
 		"traefik.backend.request.total:1.000000|c|#service:test,code:200,method:GET\n",
 		"traefik.backend.retries.total:2.000000|c|#service:test\n",
+		"traefik.backend.hedges.total:1.000000|c|#service:test\n",
+		"traefik.backend.mirrors.dropped.total:1.000000|c|#service:test\n",
+		"traefik.backend.concurrency.limit:20.000000|g|#service:test\n",
 		"traefik.backend.request.duration:10000.000000|h|#service:test,code:200\n",
 		"traefik.config.reload.total:1.000000|c\n",
 		"traefik.config.reload.total:1.000000|c|#failure:true\n",
@@ -42,6 +45,9 @@ func TestDatadog(t *testing.T) {
 		datadogRegistry.BackendReqDurationHistogram().With("service", "test", "code", strconv.Itoa(http.StatusOK)).Observe(10000)
 		datadogRegistry.BackendRetriesCounter().With("service", "test").Add(1)
 		datadogRegistry.BackendRetriesCounter().With("service", "test").Add(1)
+		datadogRegistry.BackendHedgesCounter().With("service", "test").Add(1)
+		datadogRegistry.BackendMirrorsDroppedCounter().With("service", "test").Add(1)
+		datadogRegistry.BackendConcurrencyLimitGauge().With("service", "test").Set(20)
 		datadogRegistry.ConfigReloadsCounter().Add(1)
 		datadogRegistry.ConfigReloadsFailureCounter().Add(1)
 		datadogRegistry.EntrypointReqsCounter().With("entrypoint", "test").Add(1)
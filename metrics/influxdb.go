@@ -29,6 +29,9 @@ const (
 	influxDBMetricsBackendReqsName      = "traefik.backend.requests.total"
 	influxDBMetricsBackendLatencyName   = "traefik.backend.request.duration"
 	influxDBRetriesTotalName            = "traefik.backend.retries.total"
+	influxDBHedgesTotalName             = "traefik.backend.hedges.total"
+	influxDBMirrorsDroppedTotalName     = "traefik.backend.mirrors.dropped.total"
+	influxDBConcurrencyLimitName        = "traefik.backend.concurrency.limit"
 	influxDBConfigReloadsName           = "traefik.config.reload.total"
 	influxDBConfigReloadsFailureName    = influxDBConfigReloadsName + ".failure"
 	influxDBLastConfigReloadSuccessName = "traefik.config.reload.lastSuccessTimestamp"
@@ -61,6 +64,9 @@ func RegisterInfluxDB(config *types.InfluxDB) Registry {
 		backendReqsCounter:             influxDBClient.NewCounter(influxDBMetricsBackendReqsName),
 		backendReqDurationHistogram:    influxDBClient.NewHistogram(influxDBMetricsBackendLatencyName),
 		backendRetriesCounter:          influxDBClient.NewCounter(influxDBRetriesTotalName),
+		backendHedgesCounter:           influxDBClient.NewCounter(influxDBHedgesTotalName),
+		backendMirrorsDroppedCounter:   influxDBClient.NewCounter(influxDBMirrorsDroppedTotalName),
+		backendConcurrencyLimitGauge:   influxDBClient.NewGauge(influxDBConcurrencyLimitName),
 		backendOpenConnsGauge:          influxDBClient.NewGauge(influxDBOpenConnsName),
 		backendServerUpGauge:           influxDBClient.NewGauge(influxDBServerUpName),
 	}
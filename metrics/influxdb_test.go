@@ -31,6 +31,9 @@ func TestInfluxDB(t *testing.T) {
 		`(traefik\.backend\.requests\.total,backend=test,code=404,method=GET count=1) [\d]{19}`,
 		`(traefik\.backend\.request\.duration,backend=test,code=200 p50=10000,p90=10000,p95=10000,p99=10000) [\d]{19}`,
 		`(traefik\.backend\.retries\.total(?:,code=[\d]{3},method=GET)?,backend=test count=2) [\d]{19}`,
+		`(traefik\.backend\.hedges\.total,backend=test count=1) [\d]{19}`,
+		`(traefik\.backend\.mirrors\.dropped\.total,backend=test count=1) [\d]{19}`,
+		`(traefik\.backend\.concurrency\.limit,backend=test value=20) [\d]{19}`,
 		`(traefik\.config\.reload\.total(?:[a-z=0-9A-Z,]+)? count=1) [\d]{19}`,
 		`(traefik\.config\.reload\.total\.failure(?:[a-z=0-9A-Z,]+)? count=1) [\d]{19}`,
 		`(traefik\.backend\.server\.up,backend=test(?:[a-z=0-9A-Z,]+)?,url=http://127.0.0.1 value=1) [\d]{19}`,
@@ -41,6 +44,9 @@ func TestInfluxDB(t *testing.T) {
 		influxDBRegistry.BackendReqsCounter().With("backend", "test", "code", strconv.Itoa(http.StatusNotFound), "method", http.MethodGet).Add(1)
 		influxDBRegistry.BackendRetriesCounter().With("backend", "test").Add(1)
 		influxDBRegistry.BackendRetriesCounter().With("backend", "test").Add(1)
+		influxDBRegistry.BackendHedgesCounter().With("backend", "test").Add(1)
+		influxDBRegistry.BackendMirrorsDroppedCounter().With("backend", "test").Add(1)
+		influxDBRegistry.BackendConcurrencyLimitGauge().With("backend", "test").Set(20)
 		influxDBRegistry.BackendReqDurationHistogram().With("backend", "test", "code", strconv.Itoa(http.StatusOK)).Observe(10000)
 		influxDBRegistry.ConfigReloadsCounter().Add(1)
 		influxDBRegistry.ConfigReloadsFailureCounter().Add(1)
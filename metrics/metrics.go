@@ -26,6 +26,9 @@ type Registry interface {
 	BackendReqDurationHistogram() metrics.Histogram
 	BackendOpenConnsGauge() metrics.Gauge
 	BackendRetriesCounter() metrics.Counter
+	BackendHedgesCounter() metrics.Counter
+	BackendMirrorsDroppedCounter() metrics.Counter
+	BackendConcurrencyLimitGauge() metrics.Gauge
 	BackendServerUpGauge() metrics.Gauge
 }
 
@@ -50,6 +53,9 @@ func NewMultiRegistry(registries []Registry) Registry {
 	var backendReqDurationHistogram []metrics.Histogram
 	var backendOpenConnsGauge []metrics.Gauge
 	var backendRetriesCounter []metrics.Counter
+	var backendHedgesCounter []metrics.Counter
+	var backendMirrorsDroppedCounter []metrics.Counter
+	var backendConcurrencyLimitGauge []metrics.Gauge
 	var backendServerUpGauge []metrics.Gauge
 
 	for _, r := range registries {
@@ -86,6 +92,15 @@ func NewMultiRegistry(registries []Registry) Registry {
 		if r.BackendRetriesCounter() != nil {
 			backendRetriesCounter = append(backendRetriesCounter, r.BackendRetriesCounter())
 		}
+		if r.BackendHedgesCounter() != nil {
+			backendHedgesCounter = append(backendHedgesCounter, r.BackendHedgesCounter())
+		}
+		if r.BackendMirrorsDroppedCounter() != nil {
+			backendMirrorsDroppedCounter = append(backendMirrorsDroppedCounter, r.BackendMirrorsDroppedCounter())
+		}
+		if r.BackendConcurrencyLimitGauge() != nil {
+			backendConcurrencyLimitGauge = append(backendConcurrencyLimitGauge, r.BackendConcurrencyLimitGauge())
+		}
 		if r.BackendServerUpGauge() != nil {
 			backendServerUpGauge = append(backendServerUpGauge, r.BackendServerUpGauge())
 		}
@@ -104,6 +119,9 @@ func NewMultiRegistry(registries []Registry) Registry {
 		backendReqDurationHistogram:    multi.NewHistogram(backendReqDurationHistogram...),
 		backendOpenConnsGauge:          multi.NewGauge(backendOpenConnsGauge...),
 		backendRetriesCounter:          multi.NewCounter(backendRetriesCounter...),
+		backendHedgesCounter:           multi.NewCounter(backendHedgesCounter...),
+		backendMirrorsDroppedCounter:   multi.NewCounter(backendMirrorsDroppedCounter...),
+		backendConcurrencyLimitGauge:   multi.NewGauge(backendConcurrencyLimitGauge...),
 		backendServerUpGauge:           multi.NewGauge(backendServerUpGauge...),
 	}
 }
@@ -121,6 +139,9 @@ type standardRegistry struct {
 	backendReqDurationHistogram    metrics.Histogram
 	backendOpenConnsGauge          metrics.Gauge
 	backendRetriesCounter          metrics.Counter
+	backendHedgesCounter           metrics.Counter
+	backendMirrorsDroppedCounter   metrics.Counter
+	backendConcurrencyLimitGauge   metrics.Gauge
 	backendServerUpGauge           metrics.Gauge
 }
 
@@ -172,6 +193,18 @@ func (r *standardRegistry) BackendRetriesCounter() metrics.Counter {
 	return r.backendRetriesCounter
 }
 
+func (r *standardRegistry) BackendHedgesCounter() metrics.Counter {
+	return r.backendHedgesCounter
+}
+
+func (r *standardRegistry) BackendMirrorsDroppedCounter() metrics.Counter {
+	return r.backendMirrorsDroppedCounter
+}
+
+func (r *standardRegistry) BackendConcurrencyLimitGauge() metrics.Gauge {
+	return r.backendConcurrencyLimitGauge
+}
+
 func (r *standardRegistry) BackendServerUpGauge() metrics.Gauge {
 	return r.backendServerUpGauge
 }
@@ -36,12 +36,15 @@ const (
 	// backend level.
 
 	// MetricBackendPrefix prefix of all backend metric names
-	MetricBackendPrefix     = MetricNamePrefix + "backend_"
-	backendReqsTotalName    = MetricBackendPrefix + "requests_total"
-	backendReqDurationName  = MetricBackendPrefix + "request_duration_seconds"
-	backendOpenConnsName    = MetricBackendPrefix + "open_connections"
-	backendRetriesTotalName = MetricBackendPrefix + "retries_total"
-	backendServerUpName     = MetricBackendPrefix + "server_up"
+	MetricBackendPrefix            = MetricNamePrefix + "backend_"
+	backendReqsTotalName           = MetricBackendPrefix + "requests_total"
+	backendReqDurationName         = MetricBackendPrefix + "request_duration_seconds"
+	backendOpenConnsName           = MetricBackendPrefix + "open_connections"
+	backendRetriesTotalName        = MetricBackendPrefix + "retries_total"
+	backendHedgesTotalName         = MetricBackendPrefix + "hedges_total"
+	backendMirrorsDroppedTotalName = MetricBackendPrefix + "mirrors_dropped_total"
+	backendConcLimitName           = MetricBackendPrefix + "concurrency_limit"
+	backendServerUpName            = MetricBackendPrefix + "server_up"
 )
 
 // promState holds all metric state internally and acts as the only Collector we register for Prometheus.
@@ -154,6 +157,18 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 		Name: backendRetriesTotalName,
 		Help: "How many request retries happened on a backend.",
 	}, []string{"backend"})
+	backendHedges := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+		Name: backendHedgesTotalName,
+		Help: "How many hedged requests were sent on a backend.",
+	}, []string{"backend"})
+	backendMirrorsDropped := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+		Name: backendMirrorsDroppedTotalName,
+		Help: "How many mirrored requests were dropped on a backend, because of too many in-flight mirrored requests.",
+	}, []string{"backend"})
+	backendConcLimit := newGaugeFrom(promState.collectors, stdprometheus.GaugeOpts{
+		Name: backendConcLimitName,
+		Help: "Current adaptive concurrency limit of a backend.",
+	}, []string{"backend"})
 	backendServerUp := newGaugeFrom(promState.collectors, stdprometheus.GaugeOpts{
 		Name: backendServerUpName,
 		Help: "Backend server is up, described by gauge value of 0 or 1.",
@@ -171,6 +186,9 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 		backendReqDurations.hv.Describe,
 		backendOpenConns.gv.Describe,
 		backendRetries.cv.Describe,
+		backendHedges.cv.Describe,
+		backendMirrorsDropped.cv.Describe,
+		backendConcLimit.gv.Describe,
 		backendServerUp.gv.Describe,
 	}
 
@@ -187,6 +205,9 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 		backendReqDurationHistogram:    backendReqDurations,
 		backendOpenConnsGauge:          backendOpenConns,
 		backendRetriesCounter:          backendRetries,
+		backendHedgesCounter:           backendHedges,
+		backendMirrorsDroppedCounter:   backendMirrorsDropped,
+		backendConcurrencyLimitGauge:   backendConcLimit,
 		backendServerUpGauge:           backendServerUp,
 	}
 }
@@ -148,6 +148,18 @@ func TestPrometheus(t *testing.T) {
 		BackendRetriesCounter().
 		With("backend", "backend1").
 		Add(1)
+	prometheusRegistry.
+		BackendHedgesCounter().
+		With("backend", "backend1").
+		Add(1)
+	prometheusRegistry.
+		BackendMirrorsDroppedCounter().
+		With("backend", "backend1").
+		Add(1)
+	prometheusRegistry.
+		BackendConcurrencyLimitGauge().
+		With("backend", "backend1").
+		Set(20)
 	prometheusRegistry.
 		BackendServerUpGauge().
 		With("backend", "backend1", "url", "http://127.0.0.10:80").
@@ -243,6 +255,27 @@ func TestPrometheus(t *testing.T) {
 			},
 			assert: buildGreaterThanCounterAssert(t, backendRetriesTotalName, 1),
 		},
+		{
+			name: backendHedgesTotalName,
+			labels: map[string]string{
+				"backend": "backend1",
+			},
+			assert: buildGreaterThanCounterAssert(t, backendHedgesTotalName, 1),
+		},
+		{
+			name: backendMirrorsDroppedTotalName,
+			labels: map[string]string{
+				"backend": "backend1",
+			},
+			assert: buildGreaterThanCounterAssert(t, backendMirrorsDroppedTotalName, 1),
+		},
+		{
+			name: backendConcLimitName,
+			labels: map[string]string{
+				"backend": "backend1",
+			},
+			assert: buildGaugeAssert(t, backendConcLimitName, 20),
+		},
 		{
 			name: backendServerUpName,
 			labels: map[string]string{
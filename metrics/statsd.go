@@ -22,6 +22,9 @@ const (
 	statsdMetricsBackendReqsName      = "backend.request.total"
 	statsdMetricsBackendLatencyName   = "backend.request.duration"
 	statsdRetriesTotalName            = "backend.retries.total"
+	statsdHedgesTotalName             = "backend.hedges.total"
+	statsdMirrorsDroppedTotalName     = "backend.mirrors.dropped.total"
+	statsdConcurrencyLimitName        = "backend.concurrency.limit"
 	statsdConfigReloadsName           = "config.reload.total"
 	statsdConfigReloadsFailureName    = statsdConfigReloadsName + ".failure"
 	statsdLastConfigReloadSuccessName = "config.reload.lastSuccessTimestamp"
@@ -51,6 +54,9 @@ func RegisterStatsd(config *types.Statsd) Registry {
 		backendReqsCounter:             statsdClient.NewCounter(statsdMetricsBackendReqsName, 1.0),
 		backendReqDurationHistogram:    statsdClient.NewTiming(statsdMetricsBackendLatencyName, 1.0),
 		backendRetriesCounter:          statsdClient.NewCounter(statsdRetriesTotalName, 1.0),
+		backendHedgesCounter:           statsdClient.NewCounter(statsdHedgesTotalName, 1.0),
+		backendMirrorsDroppedCounter:   statsdClient.NewCounter(statsdMirrorsDroppedTotalName, 1.0),
+		backendConcurrencyLimitGauge:   statsdClient.NewGauge(statsdConcurrencyLimitName),
 		backendOpenConnsGauge:          statsdClient.NewGauge(statsdOpenConnsName),
 		backendServerUpGauge:           statsdClient.NewGauge(statsdServerUpName),
 	}
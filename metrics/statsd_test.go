@@ -26,6 +26,9 @@ func TestStatsD(t *testing.T) {
 		// We are only validating counts, as it is nearly impossible to validate latency, since it varies every run
 		"traefik.backend.request.total:2.000000|c\n",
 		"traefik.backend.retries.total:2.000000|c\n",
+		"traefik.backend.hedges.total:1.000000|c\n",
+		"traefik.backend.mirrors.dropped.total:1.000000|c\n",
+		"traefik.backend.concurrency.limit:20.000000|g\n",
 		"traefik.backend.request.duration:10000.000000|ms",
 		"traefik.config.reload.total:1.000000|c\n",
 		"traefik.config.reload.total:1.000000|c\n",
@@ -40,6 +43,9 @@ func TestStatsD(t *testing.T) {
 		statsdRegistry.BackendReqsCounter().With("service", "test", "code", strconv.Itoa(http.StatusNotFound), "method", http.MethodGet).Add(1)
 		statsdRegistry.BackendRetriesCounter().With("service", "test").Add(1)
 		statsdRegistry.BackendRetriesCounter().With("service", "test").Add(1)
+		statsdRegistry.BackendHedgesCounter().With("service", "test").Add(1)
+		statsdRegistry.BackendMirrorsDroppedCounter().With("service", "test").Add(1)
+		statsdRegistry.BackendConcurrencyLimitGauge().With("service", "test").Set(20)
 		statsdRegistry.BackendReqDurationHistogram().With("service", "test", "code", strconv.Itoa(http.StatusOK)).Observe(10000)
 		statsdRegistry.ConfigReloadsCounter().Add(1)
 		statsdRegistry.ConfigReloadsFailureCounter().Add(1)
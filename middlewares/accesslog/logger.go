@@ -146,6 +146,33 @@ func GetLogDataTable(req *http.Request) *LogData {
 	return &LogData{Core: make(CoreLogData)}
 }
 
+// WithAttemptLogData returns a copy of ctx holding a copy of its logging data, for an attempt of the request
+// running concurrently with other attempts (e.g. the hedged requests), and a function saving the logging data
+// of the attempt into the logging data of ctx, for the attempt whose response is used.
+func WithAttemptLogData(ctx context.Context) (context.Context, func()) {
+	table, ok := ctx.Value(DataTableKey).(*LogData)
+	if !ok {
+		return ctx, func() {}
+	}
+
+	attemptTable := &LogData{
+		Core:               make(CoreLogData, len(table.Core)),
+		Request:            table.Request,
+		OriginResponse:     table.OriginResponse,
+		DownstreamResponse: table.DownstreamResponse,
+	}
+	for k, v := range table.Core {
+		attemptTable.Core[k] = v
+	}
+
+	return context.WithValue(ctx, DataTableKey, attemptTable), func() {
+		for k, v := range attemptTable.Core {
+			table.Core[k] = v
+		}
+		table.OriginResponse = attemptTable.OriginResponse
+	}
+}
+
 func (l *LogHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
 	now := time.Now().UTC()
 
@@ -1,6 +1,7 @@
 package accesslog
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -339,6 +340,30 @@ func TestLoggerJSON(t *testing.T) {
 	}
 }
 
+func TestWithAttemptLogData(t *testing.T) {
+	table := &LogData{Core: CoreLogData{FrontendName: testFrontendName}}
+	ctx := context.WithValue(context.Background(), DataTableKey, table)
+
+	attemptCtx, save := WithAttemptLogData(ctx)
+	attemptTable := attemptCtx.Value(DataTableKey).(*LogData)
+	assert.Equal(t, testFrontendName, attemptTable.Core[FrontendName])
+
+	// The attempt doesn't modify the logging data of the request until it is saved.
+	attemptTable.Core[BackendName] = testBackendName
+	attemptTable.OriginResponse = http.Header{"X-Foo": {"bar"}}
+	assert.NotContains(t, table.Core, BackendName)
+
+	save()
+	assert.Equal(t, testBackendName, table.Core[BackendName])
+	assert.Equal(t, testFrontendName, table.Core[FrontendName])
+	assert.Equal(t, "bar", table.OriginResponse.Get("X-Foo"))
+
+	// Without logging data, the context is not modified.
+	noTableCtx, save := WithAttemptLogData(context.Background())
+	save()
+	assert.Equal(t, context.Background(), noTableCtx)
+}
+
 func TestNewLogHandlerOutputStdout(t *testing.T) {
 	testCases := []struct {
 		desc        string
@@ -0,0 +1,209 @@
+package middlewares
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pteich/traefik/log"
+)
+
+// Algorithms of the adaptive concurrency limit.
+const (
+	// ConcurrencyGradient adjusts the limit to the ratio between the usual latency and the current latency of the backend.
+	ConcurrencyGradient = "gradient"
+	// ConcurrencyAIMD increases the limit additively while the latency stays below a threshold, and decreases it multiplicatively otherwise.
+	ConcurrencyAIMD = "aimd"
+)
+
+const (
+	// concurrencyRetryAfter is the delay in seconds after which the shed requests are invited to retry.
+	concurrencyRetryAfter = 1
+
+	// concurrencyAIMDBackoffRatio is the ratio of the limit kept when the backend is overloaded.
+	concurrencyAIMDBackoffRatio = 0.9
+
+	// concurrencyGradientTolerance is the latency increase tolerated before decreasing the limit.
+	concurrencyGradientTolerance = 1.5
+	// concurrencyGradientSmoothing is the weight of each new limit computed from a request.
+	concurrencyGradientSmoothing = 0.2
+	// concurrencyGradientLongWindow is the number of requests over which the usual latency is averaged.
+	concurrencyGradientLongWindow = 600
+)
+
+// AdaptiveConcurrencyOptions are the options of the adaptive concurrency limit.
+type AdaptiveConcurrencyOptions struct {
+	Algorithm    string
+	InitialLimit int
+	MinLimit     int
+	MaxLimit     int
+	// LatencyThreshold is the latency above which the AIMD algorithm decreases the limit.
+	LatencyThreshold time.Duration
+}
+
+func (opt AdaptiveConcurrencyOptions) String() string {
+	return fmt.Sprintf("[Algorithm: %s InitialLimit: %d MinLimit: %d MaxLimit: %d LatencyThreshold: %s]",
+		opt.Algorithm, opt.InitialLimit, opt.MinLimit, opt.MaxLimit, opt.LatencyThreshold)
+}
+
+// concurrencyLimitAlgorithm computes the new concurrency limit from the outcome of a request.
+type concurrencyLimitAlgorithm interface {
+	// update returns the new limit, given the latency of a request, the number of requests in flight when it started,
+	// and whether the backend signaled an overload.
+	update(limit float64, latency time.Duration, inFlight int, overloaded bool) float64
+}
+
+// AdaptiveConcurrencyLimiter limits the number of requests in flight to a backend,
+// the limit adapting to the latency of the backend. The requests over the limit are rejected with a 503.
+type AdaptiveConcurrencyLimiter struct {
+	AdaptiveConcurrencyOptions
+	next        http.Handler
+	backendName string
+	metrics     concurrencyMetrics
+	algorithm   concurrencyLimitAlgorithm
+
+	mutex    sync.Mutex
+	limit    float64
+	inFlight int
+}
+
+// NewAdaptiveConcurrencyLimiter creates a new AdaptiveConcurrencyLimiter.
+func NewAdaptiveConcurrencyLimiter(next http.Handler, options AdaptiveConcurrencyOptions, backendName string, metrics concurrencyMetrics) (*AdaptiveConcurrencyLimiter, error) {
+	var algorithm concurrencyLimitAlgorithm
+	switch options.Algorithm {
+	case ConcurrencyGradient:
+		algorithm = &gradientLimit{}
+	case ConcurrencyAIMD:
+		algorithm = &aimdLimit{latencyThreshold: options.LatencyThreshold}
+	default:
+		return nil, fmt.Errorf("unknown adaptive concurrency algorithm %q", options.Algorithm)
+	}
+
+	if options.MinLimit < 1 || options.MaxLimit < options.MinLimit {
+		return nil, fmt.Errorf("invalid limits, min: %d max: %d", options.MinLimit, options.MaxLimit)
+	}
+
+	l := &AdaptiveConcurrencyLimiter{
+		AdaptiveConcurrencyOptions: options,
+		next:                       next,
+		backendName:                backendName,
+		metrics:                    metrics,
+		algorithm:                  algorithm,
+	}
+	l.limit = l.clamp(float64(options.InitialLimit))
+	l.metrics.BackendConcurrencyLimitGauge().With("backend", backendName).Set(math.Floor(l.limit))
+
+	return l, nil
+}
+
+func (l *AdaptiveConcurrencyLimiter) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	l.mutex.Lock()
+	if l.inFlight >= int(l.limit) {
+		l.mutex.Unlock()
+
+		log.Debugf("Adaptive concurrency limit of backend %s reached, rejecting request: %v", l.backendName, req.URL)
+		rw.Header().Set("Retry-After", strconv.Itoa(concurrencyRetryAfter))
+		rw.WriteHeader(http.StatusServiceUnavailable)
+		rw.Write([]byte(http.StatusText(http.StatusServiceUnavailable)))
+		return
+	}
+	l.inFlight++
+	inFlight := l.inFlight
+	l.mutex.Unlock()
+
+	start := time.Now()
+	recorder := &responseRecorder{rw, http.StatusOK}
+	l.next.ServeHTTP(recorder, req)
+	latency := time.Since(start)
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.inFlight--
+
+	// A canceled request says nothing about the latency of the backend.
+	if req.Context().Err() == context.Canceled {
+		return
+	}
+
+	previous := math.Floor(l.limit)
+	l.limit = l.clamp(l.algorithm.update(l.limit, latency, inFlight, isOverloadStatus(recorder.statusCode)))
+
+	if current := math.Floor(l.limit); current != previous {
+		l.metrics.BackendConcurrencyLimitGauge().With("backend", l.backendName).Set(current)
+	}
+}
+
+func (l *AdaptiveConcurrencyLimiter) clamp(limit float64) float64 {
+	return math.Max(float64(l.MinLimit), math.Min(float64(l.MaxLimit), limit))
+}
+
+// isOverloadStatus returns whether the status code of a response signals an overloaded backend.
+func isOverloadStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// aimdLimit is the additive increase, multiplicative decrease algorithm.
+type aimdLimit struct {
+	latencyThreshold time.Duration
+}
+
+func (a *aimdLimit) update(limit float64, latency time.Duration, inFlight int, overloaded bool) float64 {
+	if overloaded || latency > a.latencyThreshold {
+		return limit * concurrencyAIMDBackoffRatio
+	}
+
+	// The limit only increases when it is actually used, so it doesn't grow unbounded with a light traffic.
+	if float64(inFlight)*2 >= limit {
+		return limit + 1
+	}
+	return limit
+}
+
+// gradientLimit is the gradient algorithm, comparing the latency of each request to the long term average latency.
+// The average is only accessed under the lock of the limiter.
+type gradientLimit struct {
+	longLatency float64
+	samples     int
+}
+
+func (g *gradientLimit) update(limit float64, latency time.Duration, inFlight int, overloaded bool) float64 {
+	sample := float64(latency)
+	if sample <= 0 {
+		sample = 1
+	}
+
+	if g.samples < concurrencyGradientLongWindow {
+		g.samples++
+	}
+	g.longLatency += (sample - g.longLatency) / float64(g.samples)
+
+	// The average recovers faster when the latency drops well below it, e.g. once a load spike is over.
+	if g.longLatency > 2*sample {
+		g.longLatency *= 0.95
+	}
+
+	// The limit only changes when it is actually used.
+	if float64(inFlight)*2 < limit && !overloaded {
+		return limit
+	}
+
+	gradient := math.Max(0.5, math.Min(1, concurrencyGradientTolerance*g.longLatency/sample))
+	if overloaded {
+		gradient = 0.5
+	}
+
+	// The queue size lets the limit grow while the latency is stable.
+	newLimit := limit*gradient + math.Sqrt(limit)
+
+	return limit*(1-concurrencyGradientSmoothing) + newLimit*concurrencyGradientSmoothing
+}
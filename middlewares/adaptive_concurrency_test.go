@@ -0,0 +1,165 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/pteich/traefik/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdaptiveConcurrencyLimiterShedding(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		started <- struct{}{}
+		<-release
+	})
+
+	limiter, err := NewAdaptiveConcurrencyLimiter(next, AdaptiveConcurrencyOptions{
+		Algorithm:    ConcurrencyGradient,
+		InitialLimit: 1,
+		MinLimit:     1,
+		MaxLimit:     1,
+	}, "backendName", newCollectingConcurrencyMetrics())
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	go func() {
+		limiter.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+		close(done)
+	}()
+	<-started
+
+	recorder := httptest.NewRecorder()
+	limiter.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	assert.Equal(t, "1", recorder.Header().Get("Retry-After"))
+
+	close(release)
+	<-done
+
+	// The request in flight is over, the next one is served.
+	go func() { <-started }()
+	recorder = httptest.NewRecorder()
+	limiter.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestAdaptiveConcurrencyLimiterOverload(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	metrics := newCollectingConcurrencyMetrics()
+	limiter, err := NewAdaptiveConcurrencyLimiter(next, AdaptiveConcurrencyOptions{
+		Algorithm:        ConcurrencyAIMD,
+		InitialLimit:     10,
+		MinLimit:         2,
+		MaxLimit:         100,
+		LatencyThreshold: time.Second,
+	}, "backendName", metrics)
+	require.NoError(t, err)
+
+	assert.Equal(t, float64(10), metrics.gauge.GaugeValue)
+	assert.Equal(t, []string{"backend", "backendName"}, metrics.gauge.LastLabelValues)
+
+	for i := 0; i < 50; i++ {
+		limiter.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+	}
+
+	assert.Equal(t, float64(2), metrics.gauge.GaugeValue)
+}
+
+func TestNewAdaptiveConcurrencyLimiterErrors(t *testing.T) {
+	_, err := NewAdaptiveConcurrencyLimiter(http.NotFoundHandler(), AdaptiveConcurrencyOptions{Algorithm: "vegas", MinLimit: 1, MaxLimit: 10}, "backendName", newCollectingConcurrencyMetrics())
+	assert.Error(t, err)
+
+	_, err = NewAdaptiveConcurrencyLimiter(http.NotFoundHandler(), AdaptiveConcurrencyOptions{Algorithm: ConcurrencyAIMD, MinLimit: 10, MaxLimit: 1}, "backendName", newCollectingConcurrencyMetrics())
+	assert.Error(t, err)
+}
+
+func TestAIMDLimit(t *testing.T) {
+	aimd := &aimdLimit{latencyThreshold: 100 * time.Millisecond}
+
+	testCases := []struct {
+		desc       string
+		latency    time.Duration
+		inFlight   int
+		overloaded bool
+		expected   float64
+	}{
+		{
+			desc:     "increases when used",
+			latency:  10 * time.Millisecond,
+			inFlight: 5,
+			expected: 11,
+		},
+		{
+			desc:     "unchanged when barely used",
+			latency:  10 * time.Millisecond,
+			inFlight: 1,
+			expected: 10,
+		},
+		{
+			desc:     "decreases on high latency",
+			latency:  200 * time.Millisecond,
+			inFlight: 5,
+			expected: 9,
+		},
+		{
+			desc:       "decreases on overload",
+			latency:    10 * time.Millisecond,
+			inFlight:   5,
+			overloaded: true,
+			expected:   9,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.InDelta(t, test.expected, aimd.update(10, test.latency, test.inFlight, test.overloaded), 0.001)
+		})
+	}
+}
+
+func TestGradientLimit(t *testing.T) {
+	gradient := &gradientLimit{}
+
+	// The limit grows while the latency is stable.
+	limit := 10.0
+	for i := 0; i < 100; i++ {
+		limit = gradient.update(limit, 10*time.Millisecond, int(limit), false)
+	}
+	assert.True(t, limit > 20, "limit %f should have grown", limit)
+
+	// The limit shrinks when the latency increases.
+	grown := limit
+	for i := 0; i < 20; i++ {
+		limit = gradient.update(limit, 100*time.Millisecond, int(limit), false)
+	}
+	assert.True(t, limit < grown/2, "limit %f should have shrunk from %f", limit, grown)
+
+	// The limit is unchanged when barely used.
+	assert.Equal(t, limit, gradient.update(limit, time.Second, 0, false))
+}
+
+type collectingConcurrencyMetrics struct {
+	gauge *testhelpers.CollectingGauge
+}
+
+func newCollectingConcurrencyMetrics() *collectingConcurrencyMetrics {
+	return &collectingConcurrencyMetrics{gauge: &testhelpers.CollectingGauge{}}
+}
+
+func (m *collectingConcurrencyMetrics) BackendConcurrencyLimitGauge() gokitmetrics.Gauge {
+	return m.gauge
+}
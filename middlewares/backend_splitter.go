@@ -0,0 +1,108 @@
+package middlewares
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/pteich/traefik/log"
+)
+
+type splitBackend struct {
+	name          string
+	handler       http.Handler
+	weight        int
+	currentWeight int
+}
+
+// BackendSplitter is a handler splitting the traffic of a frontend between several backends,
+// according to their weights (smooth weighted round robin).
+// If a sticky cookie is configured, a client keeps being sent to the backend it was first assigned to.
+type BackendSplitter struct {
+	lock         sync.Mutex
+	backends     []*splitBackend
+	stickyCookie *http.Cookie
+}
+
+// NewBackendSplitter creates a new BackendSplitter.
+// The sticky cookie is used as a template for the cookie set on the responses, it can be nil.
+func NewBackendSplitter(stickyCookie *http.Cookie) *BackendSplitter {
+	return &BackendSplitter{stickyCookie: stickyCookie}
+}
+
+// AddBackend adds a backend handler with the given weight.
+// A backend with a weight of zero doesn't receive any traffic.
+func (b *BackendSplitter) AddBackend(name string, weight int, handler http.Handler) {
+	if weight < 0 {
+		weight = 0
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.backends = append(b.backends, &splitBackend{name: name, handler: handler, weight: weight})
+}
+
+func (b *BackendSplitter) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if backend := b.stickyBackend(req); backend != nil {
+		backend.handler.ServeHTTP(rw, req)
+		return
+	}
+
+	backend := b.nextBackend()
+	if backend == nil {
+		log.Debugf("No backend with a positive weight available for %s", req.URL)
+		rw.WriteHeader(http.StatusServiceUnavailable)
+		rw.Write([]byte(http.StatusText(http.StatusServiceUnavailable)))
+		return
+	}
+
+	if b.stickyCookie != nil {
+		cookie := *b.stickyCookie
+		cookie.Value = backend.name
+		http.SetCookie(rw, &cookie)
+	}
+
+	backend.handler.ServeHTTP(rw, req)
+}
+
+func (b *BackendSplitter) stickyBackend(req *http.Request) *splitBackend {
+	if b.stickyCookie == nil {
+		return nil
+	}
+
+	cookie, err := req.Cookie(b.stickyCookie.Name)
+	if err != nil {
+		return nil
+	}
+
+	for _, backend := range b.backends {
+		if backend.name == cookie.Value && backend.weight > 0 {
+			return backend
+		}
+	}
+	return nil
+}
+
+func (b *BackendSplitter) nextBackend() *splitBackend {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	var selected *splitBackend
+	total := 0
+
+	for _, backend := range b.backends {
+		backend.currentWeight += backend.weight
+		total += backend.weight
+
+		if selected == nil || backend.currentWeight > selected.currentWeight {
+			selected = backend
+		}
+	}
+
+	if total == 0 {
+		return nil
+	}
+
+	selected.currentWeight -= total
+	return selected
+}
@@ -0,0 +1,94 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackendSplitter(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		weights  map[string]int
+		requests int
+		expected map[string]int
+	}{
+		{
+			desc:     "split according to weights",
+			weights:  map[string]int{"foo": 3, "bar": 1},
+			requests: 8,
+			expected: map[string]int{"foo": 6, "bar": 2},
+		},
+		{
+			desc:     "ignore backend with a weight of zero",
+			weights:  map[string]int{"foo": 1, "bar": 0},
+			requests: 4,
+			expected: map[string]int{"foo": 4},
+		},
+		{
+			desc:     "service unavailable without positive weight",
+			weights:  map[string]int{"foo": 0},
+			requests: 2,
+			expected: map[string]int{"": 2},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			splitter := NewBackendSplitter(nil)
+			for name, weight := range test.weights {
+				splitter.AddBackend(name, weight, newNamedHandler(name))
+			}
+
+			actual := make(map[string]int)
+			for i := 0; i < test.requests; i++ {
+				recorder := httptest.NewRecorder()
+				splitter.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+
+				actual[recorder.Header().Get("X-Backend")]++
+			}
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestBackendSplitterStickiness(t *testing.T) {
+	splitter := NewBackendSplitter(&http.Cookie{Name: "sticky", Path: "/"})
+	splitter.AddBackend("foo", 1, newNamedHandler("foo"))
+	splitter.AddBackend("bar", 1, newNamedHandler("bar"))
+
+	recorder := httptest.NewRecorder()
+	splitter.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+
+	cookies := recorder.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, "sticky", cookies[0].Name)
+
+	backend := recorder.Header().Get("X-Backend")
+	assert.Equal(t, backend, cookies[0].Value)
+
+	for i := 0; i < 3; i++ {
+		recorder = httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+		req.AddCookie(cookies[0])
+
+		splitter.ServeHTTP(recorder, req)
+
+		assert.Equal(t, backend, recorder.Header().Get("X-Backend"))
+		assert.Empty(t, recorder.Result().Cookies())
+	}
+}
+
+func newNamedHandler(name string) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("X-Backend", name)
+		rw.WriteHeader(http.StatusOK)
+	})
+}
@@ -0,0 +1,351 @@
+package middlewares
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pteich/traefik/log"
+)
+
+const (
+	// hedgingLatencySamples is the number of the latest response times from which the percentile delay is computed.
+	hedgingLatencySamples = 1000
+	// hedgingMinLatencySamples is the number of response times needed before using the percentile delay.
+	hedgingMinLatencySamples = 100
+	// hedgingLatencyRefresh is the number of new response times after which the percentile delay is computed again.
+	hedgingLatencyRefresh = 100
+)
+
+// HedgingOptions are the options of the hedged requests.
+type HedgingOptions struct {
+	// Delay is the delay after which a request is hedged.
+	Delay time.Duration
+	// Percentile is the percentile of the response times of the backend used as delay, once known, instead of Delay.
+	Percentile float64
+	// AttemptContext, when set, derives the context of each attempt from the request context, for the attempts
+	// not to share the state of the request (e.g. the access log data), and returns a function saving the state of
+	// the attempt whose response is used into the request context.
+	AttemptContext func(ctx context.Context) (context.Context, func())
+}
+
+func (opt HedgingOptions) String() string {
+	return fmt.Sprintf("[Delay: %s Percentile: %g]", opt.Delay, opt.Percentile)
+}
+
+// Hedging is a middleware sending a second request (a hedge) to another server when the first one hasn't answered within a delay,
+// and using the response arriving first. Only the GET and HEAD requests without body are hedged.
+// The handler forwarding the requests to the servers must be wrapped by NewRetryServerFilter,
+// and the load balancer by NewRetryServerBalancer, for the hedge to go to another server.
+// A request is not hedged when no other server is available.
+type Hedging struct {
+	HedgingOptions
+	next        http.Handler
+	balancer    *RetryServerBalancer
+	backendName string
+	metrics     hedgeMetrics
+	latencies   *hedgingLatencies
+}
+
+// NewHedging creates a new Hedging, the balancer being the load balancer of the backend, on which next ends.
+func NewHedging(next http.Handler, balancer *RetryServerBalancer, options HedgingOptions, backendName string, metrics hedgeMetrics) *Hedging {
+	hedging := &Hedging{
+		HedgingOptions: options,
+		next:           next,
+		balancer:       balancer,
+		backendName:    backendName,
+		metrics:        metrics,
+	}
+
+	if options.Percentile > 0 {
+		hedging.latencies = newHedgingLatencies(options.Percentile)
+	}
+
+	return hedging
+}
+
+func (h *Hedging) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if !isHedgeable(req) {
+		h.next.ServeHTTP(rw, req)
+		return
+	}
+
+	// The servers tried are tracked in the context, so the hedge avoids the server of the first request.
+	if getRetryServers(req) == nil {
+		req = req.WithContext(context.WithValue(req.Context(), retryServersKey, newRetryServers()))
+	}
+
+	race := &hedgeRace{rw: rw, start: time.Now(), latencies: h.latencies, attemptContext: h.AttemptContext}
+	done := make(chan *hedgeResponseWriter, 2)
+
+	pending := 1
+	race.launch(h.next, req, done)
+
+	var hedgeTimer <-chan time.Time
+	if delay, ok := h.delay(); ok {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		hedgeTimer = timer.C
+	}
+
+	var winner *hedgeResponseWriter
+	for pending > 0 {
+		select {
+		case attempt := <-done:
+			pending--
+
+			// An attempt done without writing anything is the response, if none was written yet.
+			if attempt.claim() {
+				winner = attempt
+				attempt.save()
+			}
+
+		case <-hedgeTimer:
+			hedgeTimer = nil
+
+			if race.hasWinner() {
+				continue
+			}
+
+			// The hedge would go to the server of the first request again.
+			if !h.balancer.hasUntriedServer(req) {
+				log.Debugf("Not hedging request to backend %s, no other server available: %v", h.backendName, req.URL)
+				continue
+			}
+
+			log.Debugf("Hedging request to backend %s after %s: %v", h.backendName, time.Since(race.start), req.URL)
+			h.metrics.BackendHedgesCounter().With("backend", h.backendName).Add(1)
+
+			pending++
+			race.launch(h.next, req, done)
+		}
+	}
+
+	if winner != nil && winner.panicValue != nil {
+		panic(winner.panicValue)
+	}
+}
+
+// delay returns the delay after which a request is hedged, and false if it is not hedged.
+func (h *Hedging) delay() (time.Duration, bool) {
+	if h.latencies != nil {
+		if delay, ok := h.latencies.delay(); ok {
+			return delay, true
+		}
+	}
+
+	return h.Delay, h.Delay > 0
+}
+
+func isHedgeable(req *http.Request) bool {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return false
+	}
+
+	if req.ContentLength != 0 || len(req.TransferEncoding) > 0 {
+		return false
+	}
+
+	// The connection upgrades (e.g. websockets) can't be hedged.
+	return req.Header.Get("Upgrade") == ""
+}
+
+// hedgeRace is the race of the attempts of a request, the first attempt writing its response wins.
+type hedgeRace struct {
+	rw             http.ResponseWriter
+	start          time.Time
+	latencies      *hedgingLatencies
+	attemptContext func(ctx context.Context) (context.Context, func())
+
+	mutex    sync.Mutex
+	winner   *hedgeResponseWriter
+	attempts []*hedgeResponseWriter
+}
+
+// launch runs an attempt of the request in a new goroutine, notifying done once it is done.
+// Each attempt has its own copy of the request, as the handlers may modify it (e.g. the tracing headers).
+func (r *hedgeRace) launch(next http.Handler, req *http.Request, done chan<- *hedgeResponseWriter) {
+	ctx := req.Context()
+	save := func() {}
+	if r.attemptContext != nil {
+		ctx, save = r.attemptContext(ctx)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+
+	attempt := &hedgeResponseWriter{
+		race:    r,
+		headers: make(http.Header),
+		cancel:  cancel,
+		save:    save,
+	}
+
+	r.mutex.Lock()
+	r.attempts = append(r.attempts, attempt)
+	r.mutex.Unlock()
+
+	go func() {
+		defer func() {
+			if panicValue := recover(); panicValue != nil {
+				if panicValue != http.ErrAbortHandler {
+					log.Errorf("Error in hedged request %v: %v", req.URL, panicValue)
+				}
+				attempt.panicValue = panicValue
+			}
+
+			cancel()
+			done <- attempt
+		}()
+
+		next.ServeHTTP(attempt, req.Clone(ctx))
+	}()
+}
+
+func (r *hedgeRace) hasWinner() bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.winner != nil
+}
+
+// hedgeResponseWriter is the response writer of an attempt, writing the response only if the attempt wins the race.
+type hedgeResponseWriter struct {
+	race    *hedgeRace
+	headers http.Header
+	cancel  context.CancelFunc
+	// save saves the state of the attempt into the request context, once the attempt is done.
+	save       func()
+	won        bool
+	written    bool
+	panicValue interface{}
+}
+
+// claim makes the attempt the winner of the race if there is none yet, canceling the other attempts,
+// and returns whether the attempt is the winner.
+func (w *hedgeResponseWriter) claim() bool {
+	r := w.race
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.winner != nil {
+		return r.winner == w
+	}
+
+	r.winner = w
+	w.won = true
+
+	for _, attempt := range r.attempts {
+		if attempt != w {
+			attempt.cancel()
+		}
+	}
+
+	if r.latencies != nil {
+		r.latencies.add(time.Since(r.start))
+	}
+
+	headers := r.rw.Header()
+	for header, value := range w.headers {
+		headers[header] = value
+	}
+
+	return true
+}
+
+func (w *hedgeResponseWriter) Header() http.Header {
+	if w.won {
+		return w.race.rw.Header()
+	}
+	return w.headers
+}
+
+func (w *hedgeResponseWriter) WriteHeader(code int) {
+	if w.written || !w.claim() {
+		return
+	}
+
+	w.written = true
+	w.race.rw.WriteHeader(code)
+}
+
+func (w *hedgeResponseWriter) Write(buf []byte) (int, error) {
+	if !w.written {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if !w.won {
+		return len(buf), nil
+	}
+	return w.race.rw.Write(buf)
+}
+
+func (w *hedgeResponseWriter) Flush() {
+	if !w.won {
+		return
+	}
+
+	if flusher, ok := w.race.rw.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// hedgingLatencies computes a percentile of the latest response times of a backend.
+type hedgingLatencies struct {
+	percentile float64
+
+	mutex   sync.Mutex
+	samples []time.Duration
+	next    int
+	// added is the number of response times added since the percentile was last computed.
+	added int
+	value time.Duration
+}
+
+func newHedgingLatencies(percentile float64) *hedgingLatencies {
+	return &hedgingLatencies{
+		percentile: percentile,
+		samples:    make([]time.Duration, 0, hedgingLatencySamples),
+	}
+}
+
+func (l *hedgingLatencies) add(latency time.Duration) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if len(l.samples) < hedgingLatencySamples {
+		l.samples = append(l.samples, latency)
+	} else {
+		l.samples[l.next] = latency
+		l.next = (l.next + 1) % hedgingLatencySamples
+	}
+
+	l.added++
+	if len(l.samples) < hedgingMinLatencySamples || (l.value > 0 && l.added < hedgingLatencyRefresh) {
+		return
+	}
+
+	sorted := make([]time.Duration, len(l.samples))
+	copy(sorted, l.samples)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	index := int(float64(len(sorted))*l.percentile/100+0.5) - 1
+	if index < 0 {
+		index = 0
+	} else if index >= len(sorted) {
+		index = len(sorted) - 1
+	}
+
+	l.value = sorted[index]
+	l.added = 0
+}
+
+// delay returns the percentile of the response times, and false while there are not enough response times.
+func (l *hedgingLatencies) delay() (time.Duration, bool) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return l.value, l.value > 0
+}
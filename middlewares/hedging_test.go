@@ -0,0 +1,275 @@
+package middlewares
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/pteich/traefik/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+func TestHedging(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		method         string
+		body           string
+		slow           bool
+		expectedServed []string
+		expectedBody   string
+		expectedHedges float64
+	}{
+		{
+			desc:           "hedged when the first server is slow",
+			method:         http.MethodGet,
+			slow:           true,
+			expectedServed: []string{"a", "b"},
+			expectedBody:   "b",
+			expectedHedges: 1,
+		},
+		{
+			desc:           "not hedged when the first server answers in time",
+			method:         http.MethodGet,
+			expectedServed: []string{"a"},
+			expectedBody:   "a",
+		},
+		{
+			desc:           "not hedged for a non idempotent method",
+			method:         http.MethodPost,
+			slow:           true,
+			expectedServed: []string{"a"},
+			expectedBody:   "a",
+		},
+		{
+			desc:           "not hedged for a request with a body",
+			method:         http.MethodGet,
+			body:           "body",
+			slow:           true,
+			expectedServed: []string{"a"},
+			expectedBody:   "a",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var mutex sync.Mutex
+			var served []string
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				mutex.Lock()
+				served = append(served, req.URL.Host)
+				mutex.Unlock()
+
+				if test.slow && req.URL.Host == "a" {
+					select {
+					case <-req.Context().Done():
+						return
+					case <-time.After(500 * time.Millisecond):
+					}
+				}
+
+				rw.Header().Set("X-Server", req.URL.Host)
+				rw.Write([]byte(req.URL.Host))
+			})
+
+			filter := NewRetryServerFilter(next)
+			rr, err := roundrobin.New(filter)
+			require.NoError(t, err)
+			require.NoError(t, rr.UpsertServer(testhelpers.MustParseURL("http://a"), roundrobin.Weight(10)))
+			require.NoError(t, rr.UpsertServer(testhelpers.MustParseURL("http://b")))
+
+			metrics := newCollectingHedgeMetrics()
+			lb := NewRetryServerBalancer(rr, filter)
+			hedging := NewHedging(lb, lb, HedgingOptions{Delay: 50 * time.Millisecond}, "backendName", metrics)
+
+			var body io.Reader
+			if test.body != "" {
+				body = strings.NewReader(test.body)
+			}
+
+			recorder := httptest.NewRecorder()
+			hedging.ServeHTTP(recorder, httptest.NewRequest(test.method, "http://localhost", body))
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			assert.Equal(t, test.expectedBody, recorder.Body.String())
+			assert.Equal(t, test.expectedBody, recorder.Header().Get("X-Server"))
+			assert.Equal(t, test.expectedServed, served)
+			assert.Equal(t, test.expectedHedges, metrics.counter.CounterValue)
+			if test.expectedHedges > 0 {
+				assert.Equal(t, []string{"backend", "backendName"}, metrics.counter.LastLabelValues)
+			}
+		})
+	}
+}
+
+func TestHedgingFirstResponseWins(t *testing.T) {
+	// The hedge answers first, even though the first request answers before the hedging completes.
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Host == "a" {
+			time.Sleep(100 * time.Millisecond)
+			rw.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		rw.WriteHeader(http.StatusAccepted)
+		time.Sleep(200 * time.Millisecond)
+		rw.Write([]byte("b"))
+	})
+
+	filter := NewRetryServerFilter(next)
+	rr, err := roundrobin.New(filter)
+	require.NoError(t, err)
+	require.NoError(t, rr.UpsertServer(testhelpers.MustParseURL("http://a"), roundrobin.Weight(10)))
+	require.NoError(t, rr.UpsertServer(testhelpers.MustParseURL("http://b")))
+
+	lb := NewRetryServerBalancer(rr, filter)
+	hedging := NewHedging(lb, lb, HedgingOptions{Delay: 20 * time.Millisecond}, "backendName", newCollectingHedgeMetrics())
+
+	recorder := httptest.NewRecorder()
+	hedging.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+
+	assert.Equal(t, http.StatusAccepted, recorder.Code)
+	assert.Equal(t, "b", recorder.Body.String())
+}
+
+func TestHedgingWithoutOtherServer(t *testing.T) {
+	var served []string
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		served = append(served, req.URL.Host)
+		time.Sleep(100 * time.Millisecond)
+		rw.Write([]byte(req.URL.Host))
+	})
+
+	filter := NewRetryServerFilter(next)
+	rr, err := roundrobin.New(filter)
+	require.NoError(t, err)
+	require.NoError(t, rr.UpsertServer(testhelpers.MustParseURL("http://a")))
+
+	metrics := newCollectingHedgeMetrics()
+	lb := NewRetryServerBalancer(rr, filter)
+	hedging := NewHedging(lb, lb, HedgingOptions{Delay: 20 * time.Millisecond}, "backendName", metrics)
+
+	recorder := httptest.NewRecorder()
+	hedging.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+
+	// The request is not hedged to the same server.
+	assert.Equal(t, "a", recorder.Body.String())
+	assert.Equal(t, []string{"a"}, served)
+	assert.Zero(t, metrics.counter.CounterValue)
+}
+
+func TestHedgingAttempts(t *testing.T) {
+	type attemptKey struct{}
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		// The handlers may modify the request of their attempt.
+		req.Header.Set("X-Attempt", req.URL.Host)
+		*req.Context().Value(attemptKey{}).(*string) = req.URL.Host
+
+		if req.URL.Host == "a" {
+			select {
+			case <-req.Context().Done():
+				return
+			case <-time.After(500 * time.Millisecond):
+			}
+		}
+		rw.Write([]byte(req.Header.Get("X-Attempt")))
+	})
+
+	filter := NewRetryServerFilter(next)
+	rr, err := roundrobin.New(filter)
+	require.NoError(t, err)
+	require.NoError(t, rr.UpsertServer(testhelpers.MustParseURL("http://a"), roundrobin.Weight(10)))
+	require.NoError(t, rr.UpsertServer(testhelpers.MustParseURL("http://b")))
+
+	var saved string
+	options := HedgingOptions{
+		Delay: 20 * time.Millisecond,
+		AttemptContext: func(ctx context.Context) (context.Context, func()) {
+			state := new(string)
+			return context.WithValue(ctx, attemptKey{}, state), func() {
+				saved = *state
+			}
+		},
+	}
+
+	lb := NewRetryServerBalancer(rr, filter)
+	hedging := NewHedging(lb, lb, options, "backendName", newCollectingHedgeMetrics())
+
+	req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+	recorder := httptest.NewRecorder()
+	hedging.ServeHTTP(recorder, req)
+
+	assert.Equal(t, "b", recorder.Body.String())
+	assert.Empty(t, req.Header.Get("X-Attempt"))
+	// Only the state of the attempt whose response is used is saved.
+	assert.Equal(t, "b", saved)
+}
+
+func TestHedgingLatencies(t *testing.T) {
+	latencies := newHedgingLatencies(90)
+
+	for i := 1; i < hedgingMinLatencySamples; i++ {
+		latencies.add(time.Duration(i) * time.Millisecond)
+	}
+
+	_, ok := latencies.delay()
+	assert.False(t, ok)
+
+	latencies.add(hedgingMinLatencySamples * time.Millisecond)
+
+	delay, ok := latencies.delay()
+	require.True(t, ok)
+	assert.Equal(t, 90*time.Millisecond, delay)
+
+	// The percentile is computed again once enough new response times were added, from the latest ones only.
+	for i := 0; i < hedgingLatencySamples; i++ {
+		latencies.add(time.Second)
+	}
+
+	delay, ok = latencies.delay()
+	require.True(t, ok)
+	assert.Equal(t, time.Second, delay)
+}
+
+func TestHedgingPercentileDelay(t *testing.T) {
+	hedging := NewHedging(http.NotFoundHandler(), nil, HedgingOptions{Delay: time.Second, Percentile: 95}, "backendName", newCollectingHedgeMetrics())
+
+	// The delay is used until enough response times are known.
+	delay, ok := hedging.delay()
+	require.True(t, ok)
+	assert.Equal(t, time.Second, delay)
+
+	for i := 0; i < hedgingMinLatencySamples; i++ {
+		hedging.latencies.add(10 * time.Millisecond)
+	}
+
+	delay, ok = hedging.delay()
+	require.True(t, ok)
+	assert.Equal(t, 10*time.Millisecond, delay)
+
+	_, ok = NewHedging(http.NotFoundHandler(), nil, HedgingOptions{Percentile: 95}, "backendName", newCollectingHedgeMetrics()).delay()
+	assert.False(t, ok)
+}
+
+type collectingHedgeMetrics struct {
+	counter *testhelpers.CollectingCounter
+}
+
+func newCollectingHedgeMetrics() *collectingHedgeMetrics {
+	return &collectingHedgeMetrics{counter: &testhelpers.CollectingCounter{}}
+}
+
+func (m *collectingHedgeMetrics) BackendHedgesCounter() gokitmetrics.Counter {
+	return m.counter
+}
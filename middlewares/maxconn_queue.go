@@ -0,0 +1,231 @@
+package middlewares
+
+import (
+	"container/heap"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pteich/traefik/log"
+	"github.com/vulcand/oxy/utils"
+)
+
+// MaxConnQueueOptions are the options of the queue of the requests waiting for a connection.
+type MaxConnQueueOptions struct {
+	// Length is the maximum number of requests waiting for a connection, per request source.
+	Length int
+	// Timeout is the maximum time a request waits for a connection.
+	Timeout time.Duration
+	// PriorityHeader is the request header holding the priority of the request, the higher the sooner served.
+	PriorityHeader string
+	// Priority is the priority of the requests without priority header.
+	Priority int
+}
+
+func (opt MaxConnQueueOptions) String() string {
+	return fmt.Sprintf("[Length: %d Timeout: %s PriorityHeader: %q Priority: %d]", opt.Length, opt.Timeout, opt.PriorityHeader, opt.Priority)
+}
+
+// MaxConnQueue limits the number of connections per request source, like oxy's connlimit,
+// but the requests over the limit wait in a bounded queue, by priority, for a connection to be released.
+// The requests are rejected with a 429 when the queue is full or their wait times out.
+type MaxConnQueue struct {
+	MaxConnQueueOptions
+	next           http.Handler
+	extract        utils.SourceExtractor
+	maxConnections int64
+
+	mutex       sync.Mutex
+	connections map[string]int64
+	queues      map[string]*connQueue
+	sequence    uint64
+}
+
+// NewMaxConnQueue creates a new MaxConnQueue.
+func NewMaxConnQueue(next http.Handler, extract utils.SourceExtractor, maxConnections int64, options MaxConnQueueOptions) *MaxConnQueue {
+	return &MaxConnQueue{
+		MaxConnQueueOptions: options,
+		next:                next,
+		extract:             extract,
+		maxConnections:      maxConnections,
+		connections:         make(map[string]int64),
+		queues:              make(map[string]*connQueue),
+	}
+}
+
+func (q *MaxConnQueue) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	token, amount, err := q.extract.Extract(req)
+	if err != nil {
+		log.Errorf("Failed to extract source of the connection: %v", err)
+		utils.DefaultHandler.ServeHTTP(rw, req, err)
+		return
+	}
+
+	if !q.acquire(rw, req, token, amount) {
+		return
+	}
+	defer q.release(token, amount)
+
+	q.next.ServeHTTP(rw, req)
+}
+
+// acquire gets a connection for the request, waiting in the queue if needed,
+// and returns false if the request was rejected.
+func (q *MaxConnQueue) acquire(rw http.ResponseWriter, req *http.Request, token string, amount int64) bool {
+	q.mutex.Lock()
+
+	if q.connections[token] < q.maxConnections {
+		q.connections[token] += amount
+		q.mutex.Unlock()
+		return true
+	}
+
+	queue := q.queues[token]
+	if queue == nil {
+		queue = &connQueue{}
+		q.queues[token] = queue
+	}
+
+	if queue.Len() >= q.Length {
+		q.mutex.Unlock()
+
+		log.Debugf("Limiting request source %s: max connections reached and queue full", token)
+		q.reject(rw, "max connections reached, queue full")
+		return false
+	}
+
+	q.sequence++
+	waiter := &connWaiter{
+		priority: q.priority(req),
+		sequence: q.sequence,
+		amount:   amount,
+		ready:    make(chan struct{}),
+	}
+	heap.Push(queue, waiter)
+	q.mutex.Unlock()
+
+	timer := time.NewTimer(q.Timeout)
+	defer timer.Stop()
+
+	var reason string
+	select {
+	case <-waiter.ready:
+		return true
+	case <-timer.C:
+		reason = "max connections reached, queue timeout"
+	case <-req.Context().Done():
+	}
+
+	q.mutex.Lock()
+
+	// The connection may have been handed over in the meantime.
+	if waiter.index < 0 {
+		q.mutex.Unlock()
+		return true
+	}
+
+	heap.Remove(queue, waiter.index)
+	if queue.Len() == 0 {
+		delete(q.queues, token)
+	}
+	q.mutex.Unlock()
+
+	if reason != "" {
+		log.Debugf("Limiting request source %s: %s", token, reason)
+		q.reject(rw, reason)
+	}
+	return false
+}
+
+// release releases a connection, handing it over to the first request of the queue if any.
+func (q *MaxConnQueue) release(token string, amount int64) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.connections[token] -= amount
+
+	if queue := q.queues[token]; queue != nil && queue.Len() > 0 {
+		waiter := heap.Pop(queue).(*connWaiter)
+		if queue.Len() == 0 {
+			delete(q.queues, token)
+		}
+
+		q.connections[token] += waiter.amount
+		close(waiter.ready)
+		return
+	}
+
+	if q.connections[token] <= 0 {
+		delete(q.connections, token)
+	}
+}
+
+func (q *MaxConnQueue) priority(req *http.Request) int {
+	if q.PriorityHeader == "" {
+		return q.Priority
+	}
+
+	value := req.Header.Get(q.PriorityHeader)
+	if value == "" {
+		return q.Priority
+	}
+
+	priority, err := strconv.Atoi(value)
+	if err != nil {
+		log.Debugf("Invalid priority %q in header %s, using the default priority", value, q.PriorityHeader)
+		return q.Priority
+	}
+	return priority
+}
+
+func (q *MaxConnQueue) reject(rw http.ResponseWriter, reason string) {
+	rw.WriteHeader(http.StatusTooManyRequests)
+	rw.Write([]byte(reason))
+}
+
+// connWaiter is a request waiting for a connection.
+type connWaiter struct {
+	priority int
+	sequence uint64
+	amount   int64
+	ready    chan struct{}
+	// index is the index of the waiter in the queue, -1 once it left the queue.
+	index int
+}
+
+// connQueue is a priority queue of the requests waiting for a connection,
+// the requests of same priority are served in their arrival order.
+type connQueue []*connWaiter
+
+func (q connQueue) Len() int { return len(q) }
+
+func (q connQueue) Less(i, j int) bool {
+	if q[i].priority != q[j].priority {
+		return q[i].priority > q[j].priority
+	}
+	return q[i].sequence < q[j].sequence
+}
+
+func (q connQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *connQueue) Push(x interface{}) {
+	waiter := x.(*connWaiter)
+	waiter.index = len(*q)
+	*q = append(*q, waiter)
+}
+
+func (q *connQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	waiter := old[n-1]
+	old[n-1] = nil
+	waiter.index = -1
+	*q = old[:n-1]
+	return waiter
+}
@@ -0,0 +1,154 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vulcand/oxy/utils"
+)
+
+func TestMaxConnQueue(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		options       MaxConnQueueOptions
+		hold          time.Duration
+		expectedCodes []int
+	}{
+		{
+			desc:          "queued requests are served once the connection is released",
+			options:       MaxConnQueueOptions{Length: 2, Timeout: time.Second},
+			hold:          50 * time.Millisecond,
+			expectedCodes: []int{http.StatusOK, http.StatusOK, http.StatusOK},
+		},
+		{
+			desc:          "rejected when the queue is full",
+			options:       MaxConnQueueOptions{Length: 1, Timeout: time.Second},
+			hold:          50 * time.Millisecond,
+			expectedCodes: []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			desc:          "rejected when the wait times out",
+			options:       MaxConnQueueOptions{Length: 2, Timeout: 20 * time.Millisecond},
+			hold:          200 * time.Millisecond,
+			expectedCodes: []int{http.StatusOK, http.StatusTooManyRequests, http.StatusTooManyRequests},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				time.Sleep(test.hold)
+			})
+
+			extractor, err := utils.NewExtractor("request.host")
+			require.NoError(t, err)
+
+			queue := NewMaxConnQueue(next, extractor, 1, test.options)
+
+			// The first request gets the connection, the others wait for it.
+			first := make(chan int)
+			go func() {
+				first <- serveMaxConnQueue(queue, nil)
+			}()
+			waitForConnections(t, queue, 1)
+
+			codes := make([]int, 2)
+			var wg sync.WaitGroup
+			for i := range codes {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					codes[i] = serveMaxConnQueue(queue, nil)
+				}(i)
+			}
+			wg.Wait()
+			sort.Ints(codes)
+
+			assert.Equal(t, test.expectedCodes, append([]int{<-first}, codes...))
+		})
+	}
+}
+
+func TestMaxConnQueuePriority(t *testing.T) {
+	var mutex sync.Mutex
+	var served []string
+	release := make(chan struct{})
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		mutex.Lock()
+		served = append(served, req.Header.Get("X-Name"))
+		mutex.Unlock()
+
+		if req.Header.Get("X-Name") == "first" {
+			<-release
+		}
+	})
+
+	extractor, err := utils.NewExtractor("request.host")
+	require.NoError(t, err)
+
+	queue := NewMaxConnQueue(next, extractor, 1, MaxConnQueueOptions{Length: 3, Timeout: time.Second, PriorityHeader: "X-Priority", Priority: 5})
+
+	var wg sync.WaitGroup
+	serve := func(name, priority string) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			serveMaxConnQueue(queue, map[string]string{"X-Name": name, "X-Priority": priority})
+		}()
+	}
+
+	serve("first", "")
+	waitForConnections(t, queue, 1)
+
+	serve("low", "1")
+	waitForQueued(t, queue, 1)
+	serve("default", "")
+	waitForQueued(t, queue, 2)
+	serve("high", "10")
+	waitForQueued(t, queue, 3)
+
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, []string{"first", "high", "default", "low"}, served)
+}
+
+func serveMaxConnQueue(queue *MaxConnQueue, headers map[string]string) int {
+	req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	recorder := httptest.NewRecorder()
+	queue.ServeHTTP(recorder, req)
+	return recorder.Code
+}
+
+func waitForConnections(t *testing.T, queue *MaxConnQueue, expected int64) {
+	t.Helper()
+
+	assert.Eventually(t, func() bool {
+		queue.mutex.Lock()
+		defer queue.mutex.Unlock()
+		return queue.connections["localhost"] == expected
+	}, time.Second, time.Millisecond)
+}
+
+func waitForQueued(t *testing.T, queue *MaxConnQueue, expected int) {
+	t.Helper()
+
+	assert.Eventually(t, func() bool {
+		queue.mutex.Lock()
+		defer queue.mutex.Unlock()
+		return queue.queues["localhost"] != nil && queue.queues["localhost"].Len() == expected
+	}, time.Second, time.Millisecond)
+}
@@ -40,6 +40,12 @@ func NewBackendMetricsMiddleware(registry metrics.Registry, backendName string)
 	}
 }
 
+// NewBackendMirrorMetricsMiddleware creates a new metrics middleware for the mirrored requests sent to a Backend.
+// The mirrored requests are reported under the backend name suffixed by "-mirror".
+func NewBackendMirrorMetricsMiddleware(registry metrics.Registry, backendName string) negroni.Handler {
+	return NewBackendMetricsMiddleware(registry, backendName+"-mirror")
+}
+
 type metricsMiddleware struct {
 	// Important: Since this int64 field is using sync/atomic, it has to be at the top of the struct due to a bug on 32-bit platform
 	// See: https://golang.org/pkg/sync/atomic/ for more information
@@ -113,6 +119,18 @@ type retryMetrics interface {
 	BackendRetriesCounter() gokitmetrics.Counter
 }
 
+type hedgeMetrics interface {
+	BackendHedgesCounter() gokitmetrics.Counter
+}
+
+type mirrorMetrics interface {
+	BackendMirrorsDroppedCounter() gokitmetrics.Counter
+}
+
+type concurrencyMetrics interface {
+	BackendConcurrencyLimitGauge() gokitmetrics.Gauge
+}
+
 // NewMetricsRetryListener instantiates a MetricsRetryListener with the given retryMetrics.
 func NewMetricsRetryListener(retryMetrics retryMetrics, backendName string) RetryListener {
 	return &MetricsRetryListener{retryMetrics: retryMetrics, backendName: backendName}
@@ -0,0 +1,197 @@
+package middlewares
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pteich/traefik/log"
+	"github.com/pteich/traefik/safe"
+)
+
+const (
+	// DefaultMirrorMaxBodySize is the default maximum size of a request body buffered to be mirrored.
+	DefaultMirrorMaxBodySize int64 = 1 << 20
+	// mirrorTimeout is the maximum duration of a mirrored request.
+	mirrorTimeout = 30 * time.Second
+	// mirrorMaxInFlight is the maximum number of in-flight mirrored requests, the next ones are dropped.
+	mirrorMaxInFlight = 100
+)
+
+// Mirroring is a handler sending a copy of a percentage of the requests to a mirror handler.
+// The responses of the mirror are discarded, the client only gets the response of the primary handler.
+type Mirroring struct {
+	handler       http.Handler
+	mirrorHandler http.Handler
+	percent       int
+	maxBodySize   int64
+	backendName   string
+	metrics       mirrorMetrics
+	timeout       time.Duration
+	// inFlight is a semaphore limiting the number of in-flight mirrored requests.
+	inFlight chan struct{}
+
+	lock     sync.Mutex
+	total    uint64
+	mirrored uint64
+}
+
+// NewMirroring creates a new Mirroring, sending percent (between 0 and 100) of the requests to the mirror backend.
+// A maxBodySize lower or equal to zero uses DefaultMirrorMaxBodySize.
+func NewMirroring(handler http.Handler, mirrorHandler http.Handler, percent int, maxBodySize int64, backendName string, metrics mirrorMetrics) (*Mirroring, error) {
+	if percent < 0 || percent > 100 {
+		return nil, fmt.Errorf("invalid mirror percent %d, must be between 0 and 100", percent)
+	}
+	if maxBodySize <= 0 {
+		maxBodySize = DefaultMirrorMaxBodySize
+	}
+
+	return &Mirroring{
+		handler:       handler,
+		mirrorHandler: mirrorHandler,
+		percent:       percent,
+		maxBodySize:   maxBodySize,
+		backendName:   backendName,
+		metrics:       metrics,
+		timeout:       mirrorTimeout,
+		inFlight:      make(chan struct{}, mirrorMaxInFlight),
+	}, nil
+}
+
+func (m *Mirroring) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if !m.shouldMirror(req) {
+		m.handler.ServeHTTP(rw, req)
+		return
+	}
+
+	// A slow mirror backend must not pile up goroutines and buffered bodies.
+	select {
+	case m.inFlight <- struct{}{}:
+	default:
+		log.Debugf("Not mirroring %s: too many in-flight mirrored requests", req.URL)
+		m.metrics.BackendMirrorsDroppedCounter().With("backend", m.backendName).Add(1)
+		m.handler.ServeHTTP(rw, req)
+		return
+	}
+
+	body, err := m.bufferBody(req)
+	if err != nil {
+		<-m.inFlight
+		log.Debugf("Not mirroring %s: %v", req.URL, err)
+		m.handler.ServeHTTP(rw, req)
+		return
+	}
+
+	mirrorURL := *req.URL
+
+	// The mirrored request keeps the values of the request context (e.g. the tracing span),
+	// but is not canceled with the request.
+	ctx, cancel := context.WithTimeout(detachedContext{parent: req.Context()}, m.timeout)
+	mirrorReq := req.WithContext(ctx)
+	mirrorReq.URL = &mirrorURL
+	mirrorReq.Header = cloneHeader(req.Header)
+	mirrorReq.Body = http.NoBody
+	if len(body) > 0 {
+		mirrorReq.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	safe.Go(func() {
+		defer func() { <-m.inFlight }()
+		defer cancel()
+
+		m.mirrorHandler.ServeHTTP(&discardResponseWriter{header: make(http.Header)}, mirrorReq)
+	})
+
+	m.handler.ServeHTTP(rw, req)
+}
+
+func (m *Mirroring) shouldMirror(req *http.Request) bool {
+	if m.percent == 0 || isWebsocketRequest(req) || req.ContentLength > m.maxBodySize {
+		return false
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.total++
+	if m.mirrored*100 >= m.total*uint64(m.percent) {
+		return false
+	}
+
+	m.mirrored++
+	return true
+}
+
+// bufferBody reads the request body up to the maximum body size, so it can be sent to the mirror.
+// The body of the original request is replaced, so it can still be read by the primary handler.
+func (m *Mirroring) bufferBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(req.Body, m.maxBodySize+1))
+	if err != nil {
+		req.Body = ioutil.NopCloser(io.MultiReader(bytes.NewReader(body), req.Body))
+		return nil, err
+	}
+
+	if int64(len(body)) > m.maxBodySize {
+		req.Body = ioutil.NopCloser(io.MultiReader(bytes.NewReader(body), req.Body))
+		return nil, fmt.Errorf("request body larger than %d bytes", m.maxBodySize)
+	}
+
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+func cloneHeader(h http.Header) http.Header {
+	h2 := make(http.Header, len(h))
+	for k, vv := range h {
+		vv2 := make([]string, len(vv))
+		copy(vv2, vv)
+		h2[k] = vv2
+	}
+	return h2
+}
+
+// detachedContext is a context keeping the values of its parent, without its deadline and cancellation.
+type detachedContext struct {
+	parent context.Context
+}
+
+func (c detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (c detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (c detachedContext) Err() error {
+	return nil
+}
+
+func (c detachedContext) Value(key interface{}) interface{} {
+	return c.parent.Value(key)
+}
+
+type discardResponseWriter struct {
+	header http.Header
+}
+
+func (d *discardResponseWriter) Header() http.Header {
+	return d.header
+}
+
+func (d *discardResponseWriter) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+func (d *discardResponseWriter) WriteHeader(statusCode int) {}
+
+func (d *discardResponseWriter) Flush() {}
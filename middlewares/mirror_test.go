@@ -0,0 +1,263 @@
+package middlewares
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/pteich/traefik/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMirroringPercent(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		percent  int
+		requests int
+		expected int
+	}{
+		{
+			desc:     "mirror all the requests",
+			percent:  100,
+			requests: 10,
+			expected: 10,
+		},
+		{
+			desc:     "mirror a quarter of the requests",
+			percent:  25,
+			requests: 20,
+			expected: 5,
+		},
+		{
+			desc:     "mirror nothing",
+			percent:  0,
+			requests: 4,
+			expected: 0,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var wg sync.WaitGroup
+			var lock sync.Mutex
+			mirrored := 0
+
+			primary := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.WriteHeader(http.StatusOK)
+			})
+			mirror := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				defer wg.Done()
+				lock.Lock()
+				mirrored++
+				lock.Unlock()
+				rw.WriteHeader(http.StatusInternalServerError)
+			})
+
+			handler, err := NewMirroring(primary, mirror, test.percent, 0, "mirrorBackend", newCollectingMirrorMetrics())
+			require.NoError(t, err)
+
+			wg.Add(test.expected)
+			for i := 0; i < test.requests; i++ {
+				recorder := httptest.NewRecorder()
+				handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+
+				assert.Equal(t, http.StatusOK, recorder.Code)
+			}
+			waitTimeout(t, &wg)
+
+			assert.Equal(t, test.expected, mirrored)
+		})
+	}
+}
+
+func TestMirroringBody(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		body           string
+		maxBodySize    int64
+		expectedMirror bool
+	}{
+		{
+			desc:           "body smaller than the limit",
+			body:           "courgette",
+			maxBodySize:    20,
+			expectedMirror: true,
+		},
+		{
+			desc:           "body larger than the limit",
+			body:           "courgette",
+			maxBodySize:    4,
+			expectedMirror: false,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			mirrorBodies := make(chan string, 1)
+
+			primary := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				body, err := ioutil.ReadAll(req.Body)
+				require.NoError(t, err)
+				rw.Write(body)
+			})
+			mirror := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				body, err := ioutil.ReadAll(req.Body)
+				require.NoError(t, err)
+				mirrorBodies <- string(body)
+			})
+
+			handler, err := NewMirroring(primary, mirror, 100, test.maxBodySize, "mirrorBackend", newCollectingMirrorMetrics())
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, "http://localhost", strings.NewReader(test.body))
+			req.ContentLength = -1
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.body, recorder.Body.String())
+
+			select {
+			case body := <-mirrorBodies:
+				assert.True(t, test.expectedMirror, "unexpected mirrored request")
+				assert.Equal(t, test.body, body)
+			case <-time.After(100 * time.Millisecond):
+				assert.False(t, test.expectedMirror, "request not mirrored")
+			}
+		})
+	}
+}
+
+func TestMirroringInvalidPercent(t *testing.T) {
+	_, err := NewMirroring(http.NotFoundHandler(), http.NotFoundHandler(), -1, 0, "mirrorBackend", newCollectingMirrorMetrics())
+	assert.Error(t, err)
+
+	_, err = NewMirroring(http.NotFoundHandler(), http.NotFoundHandler(), 101, 0, "mirrorBackend", newCollectingMirrorMetrics())
+	assert.Error(t, err)
+}
+
+func TestMirroringInFlightLimit(t *testing.T) {
+	release := make(chan struct{})
+	mirrorDone := make(chan error, 2)
+
+	primary := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	})
+	mirror := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		select {
+		case <-release:
+		case <-req.Context().Done():
+		}
+		mirrorDone <- req.Context().Err()
+	})
+
+	metrics := newCollectingMirrorMetrics()
+	handler, err := NewMirroring(primary, mirror, 100, 0, "mirrorBackend", metrics)
+	require.NoError(t, err)
+	handler.inFlight = make(chan struct{}, 1)
+	handler.timeout = 50 * time.Millisecond
+
+	for i := 0; i < 2; i++ {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	}
+
+	// The second request is not mirrored while the first one is in flight.
+	assert.Equal(t, float64(1), metrics.counter.CounterValue)
+	assert.Equal(t, []string{"backend", "mirrorBackend"}, metrics.counter.LastLabelValues)
+
+	// The mirrored request is cancelled after the timeout, and frees its slot.
+	select {
+	case err := <-mirrorDone:
+		assert.Equal(t, context.DeadlineExceeded, err)
+	case <-time.After(time.Second):
+		t.Fatal("mirrored request not cancelled")
+	}
+	close(release)
+
+	require.Eventually(t, func() bool { return len(handler.inFlight) == 0 }, time.Second, 10*time.Millisecond)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+
+	select {
+	case err := <-mirrorDone:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("request not mirrored")
+	}
+	assert.Equal(t, float64(1), metrics.counter.CounterValue)
+}
+
+func TestMirroringContext(t *testing.T) {
+	type valueKey struct{}
+
+	primaryDone := make(chan struct{})
+	mirrorDone := make(chan error, 1)
+	var mirrorValue interface{}
+
+	primary := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+	mirror := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		<-primaryDone
+		mirrorValue = req.Context().Value(valueKey{})
+		mirrorDone <- req.Context().Err()
+	})
+
+	handler, err := NewMirroring(primary, mirror, 100, 0, "mirrorBackend", newCollectingMirrorMetrics())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), valueKey{}, "value"))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost", nil).WithContext(ctx))
+
+	// The mirrored request keeps the values of the request context, but is not canceled with the request.
+	cancel()
+	close(primaryDone)
+
+	select {
+	case err := <-mirrorDone:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("request not mirrored")
+	}
+	assert.Equal(t, "value", mirrorValue)
+}
+
+type collectingMirrorMetrics struct {
+	counter *testhelpers.CollectingCounter
+}
+
+func newCollectingMirrorMetrics() *collectingMirrorMetrics {
+	return &collectingMirrorMetrics{counter: &testhelpers.CollectingCounter{}}
+}
+
+func (m *collectingMirrorMetrics) BackendMirrorsDroppedCounter() gokitmetrics.Counter {
+	return m.counter
+}
+
+func waitTimeout(t *testing.T, wg *sync.WaitGroup) {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for the mirrored requests")
+	}
+}
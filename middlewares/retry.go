@@ -2,13 +2,19 @@ package middlewares
 
 import (
 	"bufio"
+	"context"
 	"fmt"
-	"io/ioutil"
+	"io"
+	"math/rand"
 	"net"
 	"net/http"
 	"net/http/httptrace"
+	"strings"
+	"time"
 
 	"github.com/pteich/traefik/log"
+	"github.com/pteich/traefik/types"
+	"github.com/vulcand/oxy/utils"
 )
 
 // Compile time validation that the response writer implements http interfaces correctly.
@@ -17,35 +23,73 @@ var _ Stateful = &retryResponseWriterWithCloseNotify{}
 // Retry is a middleware that retries requests
 type Retry struct {
 	attempts int
+	policy   RetryPolicy
+	budget   *retryBudget
 	next     http.Handler
 	listener RetryListener
 }
 
+// RetryPolicy defines the retries of the requests on top of the retries of the connection errors.
+type RetryPolicy struct {
+	// StatusCodes are the response status codes of the servers retried, for the requests without body.
+	StatusCodes types.HTTPCodeRanges
+	// Methods are the request methods retried on the StatusCodes, all the methods if empty.
+	Methods []string
+	// InitialInterval is the backoff before the first retry, doubled for each retry up to MaxInterval.
+	// The retries are not delayed if zero.
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+	// PerTryTimeout is the timeout of each attempt, the requests timing out are retried.
+	PerTryTimeout time.Duration
+	// BudgetPercent is the maximum percentage of the requests that can be retries, no limit if zero.
+	BudgetPercent int
+}
+
 // NewRetry returns a new Retry instance
 func NewRetry(attempts int, next http.Handler, listener RetryListener) *Retry {
+	return NewRetryWithPolicy(attempts, RetryPolicy{}, next, listener)
+}
+
+// NewRetryWithPolicy returns a new Retry instance retrying according to the policy.
+func NewRetryWithPolicy(attempts int, policy RetryPolicy, next http.Handler, listener RetryListener) *Retry {
+	if policy.MaxInterval < policy.InitialInterval {
+		policy.MaxInterval = policy.InitialInterval
+	}
+
 	return &Retry{
 		attempts: attempts,
+		policy:   policy,
+		budget:   newRetryBudget(policy.BudgetPercent),
 		next:     next,
 		listener: listener,
 	}
 }
 
 func (retry *Retry) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
-	// if we might make multiple attempts, swap the body for an ioutil.NopCloser
+	// if we might make multiple attempts, swap the body for a body which can't be closed by the attempts,
+	// and which records whether it was read, as it can't be sent again.
 	// cf https://github.com/traefik/traefik/issues/1008
+	var body *retryBody
 	if retry.attempts > 1 {
-		body := r.Body
-		if body == nil {
-			body = http.NoBody
+		requestBody := r.Body
+		if requestBody == nil {
+			requestBody = http.NoBody
 		}
-		defer body.Close()
-		r.Body = ioutil.NopCloser(body)
+		defer requestBody.Close()
+
+		body = &retryBody{Reader: requestBody}
+		r.Body = body
+
+		// The servers tried by the attempts are tracked in the context, so the retries can avoid them.
+		r = r.WithContext(context.WithValue(r.Context(), retryServersKey, newRetryServers()))
 	}
 
+	retry.budget.addRequest()
+
 	attempts := 1
 	for {
-		shouldRetry := attempts < retry.attempts
-		retryResponseWriter := newRetryResponseWriter(rw, shouldRetry)
+		canRetry := attempts < retry.attempts && retry.budget.canRetry()
+		retryResponseWriter := newRetryResponseWriter(rw, canRetry)
 
 		// Disable retries when the backend already received request data
 		trace := &httptrace.ClientTrace{
@@ -58,15 +102,114 @@ func (retry *Retry) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
 		}
 		newCtx := httptrace.WithClientTrace(r.Context(), trace)
 
+		cancel := func() {}
+		if retry.policy.PerTryTimeout > 0 {
+			newCtx, cancel = context.WithTimeout(newCtx, retry.policy.PerTryTimeout)
+		}
+
+		retryResponseWriter.SetRetryStatus(func(code int) bool {
+			return canRetry && retry.shouldRetryStatus(r, body, newCtx, code)
+		})
+
 		retry.next.ServeHTTP(retryResponseWriter, r.WithContext(newCtx))
+		cancel()
+
 		if !retryResponseWriter.ShouldRetry() {
 			break
 		}
 
 		attempts++
+		retry.budget.addRetry()
 		log.Debugf("New attempt %d for request: %v", attempts, r.URL)
 		retry.listener.Retried(r, attempts)
+
+		if !retry.wait(r.Context(), attempts-1) {
+			log.Debugf("Request canceled during the backoff of attempt %d: %v", attempts, r.URL)
+			rw.WriteHeader(utils.StatusClientClosedRequest)
+			return
+		}
+	}
+}
+
+// shouldRetryStatus returns whether the response of an attempt with the given status code has to be retried,
+// the request having been sent to the server.
+func (retry *Retry) shouldRetryStatus(r *http.Request, body *retryBody, attemptCtx context.Context, code int) bool {
+	if body == nil || body.read {
+		return false
+	}
+
+	if retry.policy.PerTryTimeout > 0 && attemptCtx.Err() == context.DeadlineExceeded && r.Context().Err() == nil {
+		return true
+	}
+
+	if !retry.policy.StatusCodes.Contains(code) {
+		return false
+	}
+
+	if len(retry.policy.Methods) == 0 {
+		return true
+	}
+
+	for _, method := range retry.policy.Methods {
+		if strings.EqualFold(method, r.Method) {
+			return true
+		}
+	}
+	return false
+}
+
+// wait waits the backoff before the given retry, and returns false if the request is canceled in the meantime.
+func (retry *Retry) wait(ctx context.Context, retryNumber int) bool {
+	backoff := retry.backoff(retryNumber)
+	if backoff <= 0 {
+		return true
+	}
+
+	timer := time.NewTimer(backoff)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// backoff returns the exponential backoff before the given retry, with a jitter of half its duration
+// so the retries of the requests failing together are spread.
+func (retry *Retry) backoff(retryNumber int) time.Duration {
+	if retry.policy.InitialInterval <= 0 {
+		return 0
+	}
+
+	interval := retry.policy.InitialInterval << uint(retryNumber-1)
+	if interval > retry.policy.MaxInterval || interval <= 0 {
+		interval = retry.policy.MaxInterval
+	}
+
+	half := interval / 2
+	return half + time.Duration(rand.Int63n(int64(interval-half)+1))
+}
+
+// retryBody is the body of a request that may be retried.
+// It can't be closed by the attempts, and records whether it was read.
+type retryBody struct {
+	io.Reader
+	read bool
+}
+
+func (b *retryBody) Read(p []byte) (int, error) {
+	n, err := b.Reader.Read(p)
+	if n > 0 {
+		b.read = true
 	}
+	return n, err
+}
+
+// Close does nothing, the body is closed once all the attempts are done.
+func (b *retryBody) Close() error {
+	return nil
 }
 
 // RetryListener is used to inform about retry attempts.
@@ -92,6 +235,7 @@ type retryResponseWriter interface {
 	http.Flusher
 	ShouldRetry() bool
 	DisableRetries()
+	SetRetryStatus(retryStatus func(code int) bool)
 }
 
 func newRetryResponseWriter(rw http.ResponseWriter, shouldRetry bool) retryResponseWriter {
@@ -110,6 +254,7 @@ type retryResponseWriterWithoutCloseNotify struct {
 	responseWriter http.ResponseWriter
 	headers        http.Header
 	shouldRetry    bool
+	retryStatus    func(code int) bool
 	written        bool
 }
 
@@ -121,6 +266,11 @@ func (rr *retryResponseWriterWithoutCloseNotify) DisableRetries() {
 	rr.shouldRetry = false
 }
 
+// SetRetryStatus sets the function telling whether a response of the server has to be retried given its status code.
+func (rr *retryResponseWriterWithoutCloseNotify) SetRetryStatus(retryStatus func(code int) bool) {
+	rr.retryStatus = retryStatus
+}
+
 func (rr *retryResponseWriterWithoutCloseNotify) Header() http.Header {
 	if rr.written {
 		return rr.responseWriter.Header()
@@ -143,6 +293,9 @@ func (rr *retryResponseWriterWithoutCloseNotify) WriteHeader(code int) {
 		// the backend server and so we can be sure that the 503 was produced
 		// inside Traefik already and we don't have to retry in this cases.
 		rr.DisableRetries()
+	} else if !rr.ShouldRetry() && !rr.written && rr.retryStatus != nil && rr.retryStatus(code) {
+		// The request reached the server, but its response is retried according to the retry policy.
+		rr.shouldRetry = true
 	}
 
 	if rr.ShouldRetry() {
@@ -171,6 +324,11 @@ func (rr *retryResponseWriterWithoutCloseNotify) Hijack() (net.Conn, *bufio.Read
 }
 
 func (rr *retryResponseWriterWithoutCloseNotify) Flush() {
+	if rr.ShouldRetry() {
+		// Nothing was written, flushing would send the headers of a response being discarded.
+		return
+	}
+
 	if flusher, ok := rr.responseWriter.(http.Flusher); ok {
 		flusher.Flush()
 	}
@@ -0,0 +1,96 @@
+package middlewares
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// retryBudgetWindow is the sliding window over which the requests and the retries are counted, by seconds.
+	retryBudgetWindow = 10
+	// retryBudgetMinRetries are allowed in the window whatever the budget,
+	// so the requests of a backend with little traffic can be retried.
+	retryBudgetMinRetries = 3
+)
+
+// retryBudget limits the retries to a percentage of the requests, so the retries don't overload the servers.
+// A nil retryBudget doesn't limit the retries.
+type retryBudget struct {
+	percent int
+	now     func() time.Time
+
+	mutex   sync.Mutex
+	buckets [retryBudgetWindow]retryBudgetBucket
+}
+
+type retryBudgetBucket struct {
+	second   int64
+	requests int
+	retries  int
+}
+
+func newRetryBudget(percent int) *retryBudget {
+	if percent <= 0 {
+		return nil
+	}
+
+	return &retryBudget{
+		percent: percent,
+		now:     time.Now,
+	}
+}
+
+func (b *retryBudget) addRequest() {
+	if b == nil {
+		return
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.bucket().requests++
+}
+
+func (b *retryBudget) addRetry() {
+	if b == nil {
+		return
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.bucket().retries++
+}
+
+// canRetry returns whether one more retry stays within the budget.
+func (b *retryBudget) canRetry() bool {
+	if b == nil {
+		return true
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	second := b.now().Unix()
+
+	var requests, retries int
+	for _, bucket := range b.buckets {
+		if second-bucket.second < retryBudgetWindow {
+			requests += bucket.requests
+			retries += bucket.retries
+		}
+	}
+
+	return retries < retryBudgetMinRetries || (retries+1)*100 <= b.percent*requests
+}
+
+// bucket returns the bucket of the current second.
+func (b *retryBudget) bucket() *retryBudgetBucket {
+	second := b.now().Unix()
+
+	bucket := &b.buckets[second%retryBudgetWindow]
+	if bucket.second != second {
+		*bucket = retryBudgetBucket{second: second}
+	}
+	return bucket
+}
@@ -0,0 +1,47 @@
+package middlewares
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryBudget(t *testing.T) {
+	now := time.Now()
+	budget := newRetryBudget(20)
+	budget.now = func() time.Time { return now }
+
+	// The first retries are allowed whatever the budget.
+	for i := 0; i < retryBudgetMinRetries; i++ {
+		budget.addRequest()
+		assert.True(t, budget.canRetry())
+		budget.addRetry()
+	}
+	assert.False(t, budget.canRetry())
+
+	for i := 0; i < 16; i++ {
+		budget.addRequest()
+	}
+	assert.False(t, budget.canRetry())
+
+	budget.addRequest()
+	assert.True(t, budget.canRetry())
+	budget.addRetry()
+	assert.False(t, budget.canRetry())
+
+	// The requests and the retries are forgotten once out of the window.
+	now = now.Add(retryBudgetWindow * time.Second)
+	assert.True(t, budget.canRetry())
+}
+
+func TestRetryBudgetUnlimited(t *testing.T) {
+	budget := newRetryBudget(0)
+	assert.Nil(t, budget)
+
+	for i := 0; i < 10; i++ {
+		budget.addRequest()
+		budget.addRetry()
+	}
+	assert.True(t, budget.canRetry())
+}
@@ -0,0 +1,199 @@
+package middlewares
+
+import (
+	"math/rand"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/pteich/traefik/balancer"
+	"github.com/pteich/traefik/healthcheck"
+	"github.com/vulcand/oxy/utils"
+)
+
+// retryServersKey is the key within the request context of the servers tried by the attempts of the request.
+const retryServersKey key = "RetryServers"
+
+// retryServers records the servers tried by the attempts of a request, which may be concurrent (e.g. the hedged requests).
+type retryServers struct {
+	mutex sync.Mutex
+	tried map[string]bool
+}
+
+func newRetryServers() *retryServers {
+	return &retryServers{tried: make(map[string]bool)}
+}
+
+func getRetryServers(req *http.Request) *retryServers {
+	servers, _ := req.Context().Value(retryServersKey).(*retryServers)
+	return servers
+}
+
+// isTried returns true when the server was tried.
+func (s *retryServers) isTried(u *url.URL) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.tried[u.String()]
+}
+
+// untried returns the given servers not tried yet, none when no server was tried.
+func (s *retryServers) untried(urls []*url.URL) []*url.URL {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if len(s.tried) == 0 {
+		return nil
+	}
+
+	var untried []*url.URL
+	for _, u := range urls {
+		if !s.tried[u.String()] {
+			untried = append(untried, u)
+		}
+	}
+	return untried
+}
+
+// try records the server as tried.
+func (s *retryServers) try(key string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.tried[key] = true
+}
+
+// serverWeighter is a load balancer exposing the weights of its servers.
+type serverWeighter interface {
+	ServerWeight(u *url.URL) (int, bool)
+}
+
+// activeServersBalancer is a load balancer forwarding the requests to a part of its servers only,
+// e.g. the servers of the first priority group.
+type activeServersBalancer interface {
+	ActiveServers() []*url.URL
+}
+
+// RetryServerBalancer is a load balancer making the retries of the requests avoid the servers already tried,
+// when other servers are available.
+// The load balancers able to skip servers (see balancer.ExcludingBalancer) pick the server of the retries among the servers not tried yet,
+// the retries of the other ones go to one of these servers, picked at random by weight.
+// The handler forwarding the requests to the servers must be wrapped by NewRetryServerFilter.
+type RetryServerBalancer struct {
+	healthcheck.BalancerHandler
+	next http.Handler
+}
+
+// NewRetryServerBalancer creates a new RetryServerBalancer.
+// The retries not forwarded by the load balancer go to next, which must be the handler the load balancer forwards the requests to.
+func NewRetryServerBalancer(lb healthcheck.BalancerHandler, next http.Handler) *RetryServerBalancer {
+	return &RetryServerBalancer{BalancerHandler: lb, next: next}
+}
+
+func (b *RetryServerBalancer) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	servers := getRetryServers(req)
+	if servers == nil {
+		b.BalancerHandler.ServeHTTP(rw, req)
+		return
+	}
+
+	untried := servers.untried(b.activeServers())
+	if len(untried) == 0 {
+		b.BalancerHandler.ServeHTTP(rw, req)
+		return
+	}
+
+	if excluding, ok := b.BalancerHandler.(balancer.ExcludingBalancer); ok && excluding.ExcludesServers() {
+		b.BalancerHandler.ServeHTTP(rw, req.WithContext(balancer.WithExcludedServers(req.Context(), servers.isTried)))
+		return
+	}
+
+	server := b.pick(untried)
+	if server == nil {
+		b.BalancerHandler.ServeHTTP(rw, req)
+		return
+	}
+
+	newReq := *req
+	newReq.URL = utils.CopyURL(server)
+	b.next.ServeHTTP(rw, &newReq)
+}
+
+// activeServers returns the servers the load balancer may forward the requests to.
+func (b *RetryServerBalancer) activeServers() []*url.URL {
+	if active, ok := b.BalancerHandler.(activeServersBalancer); ok {
+		return active.ActiveServers()
+	}
+	return b.Servers()
+}
+
+// hasUntriedServer returns true when a server not tried yet by the request can get it.
+func (b *RetryServerBalancer) hasUntriedServer(req *http.Request) bool {
+	servers := getRetryServers(req)
+	if servers == nil {
+		return false
+	}
+
+	for _, u := range servers.untried(b.activeServers()) {
+		if b.weight(u) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// weight returns the weight of the server, 1 when the load balancer doesn't expose it.
+func (b *RetryServerBalancer) weight(u *url.URL) int {
+	if weighter, ok := b.BalancerHandler.(serverWeighter); ok {
+		if weight, ok := weighter.ServerWeight(u); ok {
+			return weight
+		}
+	}
+	return 1
+}
+
+// pick picks one of the servers at random by weight, none when all their weights are zero.
+func (b *RetryServerBalancer) pick(urls []*url.URL) *url.URL {
+	weights := make([]int, len(urls))
+	total := 0
+	for i, u := range urls {
+		weights[i] = b.weight(u)
+		if weights[i] > 0 {
+			total += weights[i]
+		}
+	}
+
+	if total == 0 {
+		return nil
+	}
+
+	n := rand.Intn(total)
+	for i, u := range urls {
+		if weights[i] <= 0 {
+			continue
+		}
+		if n < weights[i] {
+			return u
+		}
+		n -= weights[i]
+	}
+	return nil
+}
+
+type retryServerFilter struct {
+	next http.Handler
+}
+
+// NewRetryServerFilter creates a handler recording the servers tried by the attempts of a request,
+// for the RetryServerBalancer to avoid them. It must wrap the handler forwarding the requests to the servers.
+func NewRetryServerFilter(next http.Handler) http.Handler {
+	return &retryServerFilter{next: next}
+}
+
+func (f *retryServerFilter) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if servers := getRetryServers(req); servers != nil {
+		servers.try(req.URL.String())
+	}
+
+	f.next.ServeHTTP(rw, req)
+}
@@ -0,0 +1,135 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"net/url"
+	"testing"
+
+	"github.com/pteich/traefik/balancer"
+	"github.com/pteich/traefik/testhelpers"
+	"github.com/pteich/traefik/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+func TestRetryServersAvoidTriedServers(t *testing.T) {
+	var served []string
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		httptrace.ContextClientTrace(req.Context()).WroteHeaders()
+
+		served = append(served, req.URL.Host)
+		rw.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	filter := NewRetryServerFilter(next)
+	rr, err := roundrobin.New(filter)
+	require.NoError(t, err)
+
+	// Without avoidance, the retries would go to the server of highest weight again.
+	require.NoError(t, rr.UpsertServer(testhelpers.MustParseURL("http://a"), roundrobin.Weight(10)))
+	require.NoError(t, rr.UpsertServer(testhelpers.MustParseURL("http://b"), roundrobin.Weight(5)))
+	require.NoError(t, rr.UpsertServer(testhelpers.MustParseURL("http://c")))
+
+	lb := &countingBalancer{RoundRobin: rr}
+	retry := NewRetryWithPolicy(3, RetryPolicy{StatusCodes: types.HTTPCodeRanges{{503, 503}}}, NewRetryServerBalancer(lb, filter), &countingRetryListener{})
+
+	recorder := httptest.NewRecorder()
+	retry.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	require.Len(t, served, 3)
+	assert.Equal(t, "a", served[0])
+	assert.ElementsMatch(t, []string{"a", "b", "c"}, served)
+
+	// The load balancer only picks the server of the first attempt.
+	assert.Equal(t, 1, lb.calls)
+}
+
+func TestRetryServersExcludingBalancer(t *testing.T) {
+	var served []string
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		served = append(served, req.URL.Host)
+		rw.WriteHeader(http.StatusBadGateway)
+	})
+
+	filter := NewRetryServerFilter(next)
+	lb := balancer.NewLeastConn(filter, nil)
+	require.NoError(t, lb.UpsertServer(testhelpers.MustParseURL("http://a")))
+	require.NoError(t, lb.UpsertServer(testhelpers.MustParseURL("http://b")))
+	require.NoError(t, lb.UpsertServer(testhelpers.MustParseURL("http://c")))
+
+	retry := NewRetry(3, NewRetryServerBalancer(lb, filter), &countingRetryListener{})
+	retry.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+
+	// The load balancer picks the server of the retries among the servers not tried yet.
+	assert.ElementsMatch(t, []string{"a", "b", "c"}, served)
+}
+
+func TestRetryServersZeroWeight(t *testing.T) {
+	var served []string
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		served = append(served, req.URL.Host)
+		rw.WriteHeader(http.StatusBadGateway)
+	})
+
+	filter := NewRetryServerFilter(next)
+	rr, err := roundrobin.New(filter)
+	require.NoError(t, err)
+	require.NoError(t, rr.UpsertServer(testhelpers.MustParseURL("http://a")))
+	require.NoError(t, rr.UpsertServer(testhelpers.MustParseURL("http://b")))
+	require.NoError(t, rr.UpsertServer(testhelpers.MustParseURL("http://c")))
+
+	lb := &weightedBalancer{RoundRobin: rr, weights: map[string]int{"http://b": 0}}
+	retry := NewRetry(2, NewRetryServerBalancer(lb, filter), &countingRetryListener{})
+
+	retry.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+
+	// The server without weight is not picked for the retry.
+	assert.Equal(t, []string{"a", "c"}, served)
+}
+
+func TestRetryServersSameServerWithoutAlternative(t *testing.T) {
+	var served []string
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		served = append(served, req.URL.Host)
+		if len(served) == 1 {
+			rw.WriteHeader(http.StatusBadGateway)
+		}
+	})
+
+	filter := NewRetryServerFilter(next)
+	rr, err := roundrobin.New(filter)
+	require.NoError(t, err)
+	require.NoError(t, rr.UpsertServer(testhelpers.MustParseURL("http://a")))
+
+	retry := NewRetry(2, NewRetryServerBalancer(rr, filter), &countingRetryListener{})
+
+	recorder := httptest.NewRecorder()
+	retry.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, []string{"a", "a"}, served)
+}
+
+type countingBalancer struct {
+	*roundrobin.RoundRobin
+	calls int
+}
+
+func (b *countingBalancer) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	b.calls++
+	b.RoundRobin.ServeHTTP(rw, req)
+}
+
+type weightedBalancer struct {
+	*roundrobin.RoundRobin
+	weights map[string]int
+}
+
+func (b *weightedBalancer) ServerWeight(u *url.URL) (int, bool) {
+	weight, ok := b.weights[u.String()]
+	return weight, ok
+}
@@ -1,20 +1,25 @@
 package middlewares
 
 import (
+	"context"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"net/http/httptrace"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/pteich/traefik/testhelpers"
+	"github.com/pteich/traefik/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/vulcand/oxy/forward"
 	"github.com/vulcand/oxy/roundrobin"
+	"github.com/vulcand/oxy/utils"
 )
 
 func TestRetry(t *testing.T) {
@@ -303,3 +308,157 @@ func TestMultipleRetriesShouldNotLooseHeaders(t *testing.T) {
 		}
 	}
 }
+
+func TestRetryPolicyStatusCodes(t *testing.T) {
+	testCases := []struct {
+		desc               string
+		method             string
+		body               string
+		policy             RetryPolicy
+		wantRetryAttempts  int
+		wantResponseStatus int
+	}{
+		{
+			desc:               "no retry without status codes",
+			method:             http.MethodGet,
+			wantRetryAttempts:  0,
+			wantResponseStatus: http.StatusServiceUnavailable,
+		},
+		{
+			desc:               "retry on status code",
+			method:             http.MethodGet,
+			policy:             RetryPolicy{StatusCodes: types.HTTPCodeRanges{{502, 503}}},
+			wantRetryAttempts:  2,
+			wantResponseStatus: http.StatusOK,
+		},
+		{
+			desc:               "no retry on other status codes",
+			method:             http.MethodGet,
+			policy:             RetryPolicy{StatusCodes: types.HTTPCodeRanges{{500, 500}}},
+			wantRetryAttempts:  0,
+			wantResponseStatus: http.StatusServiceUnavailable,
+		},
+		{
+			desc:               "retry on status code for the policy methods",
+			method:             http.MethodPut,
+			policy:             RetryPolicy{StatusCodes: types.HTTPCodeRanges{{503, 503}}, Methods: []string{"GET", "put"}},
+			wantRetryAttempts:  2,
+			wantResponseStatus: http.StatusOK,
+		},
+		{
+			desc:               "no retry on status code for the other methods",
+			method:             http.MethodPost,
+			policy:             RetryPolicy{StatusCodes: types.HTTPCodeRanges{{503, 503}}, Methods: []string{"GET"}},
+			wantRetryAttempts:  0,
+			wantResponseStatus: http.StatusServiceUnavailable,
+		},
+		{
+			desc:               "no retry on status code when the body was sent",
+			method:             http.MethodPut,
+			body:               "data",
+			policy:             RetryPolicy{StatusCodes: types.HTTPCodeRanges{{503, 503}}},
+			wantRetryAttempts:  0,
+			wantResponseStatus: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			attempt := 0
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				ioutil.ReadAll(req.Body)
+
+				// Request has been successfully written to backend
+				httptrace.ContextClientTrace(req.Context()).WroteHeaders()
+
+				attempt++
+				if attempt < 3 {
+					rw.Header().Set("X-Attempt", strconv.Itoa(attempt))
+					rw.WriteHeader(http.StatusServiceUnavailable)
+					rw.Write([]byte("overloaded"))
+					return
+				}
+				rw.WriteHeader(http.StatusOK)
+				rw.Write([]byte("OK"))
+			})
+
+			retryListener := &countingRetryListener{}
+			retry := NewRetryWithPolicy(3, test.policy, next, retryListener)
+
+			recorder := httptest.NewRecorder()
+			req := httptest.NewRequest(test.method, "http://localhost", strings.NewReader(test.body))
+			retry.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.wantResponseStatus, recorder.Code)
+			assert.Equal(t, test.wantRetryAttempts, retryListener.timesCalled)
+			if test.wantResponseStatus == http.StatusOK {
+				assert.Equal(t, "OK", recorder.Body.String())
+				assert.Empty(t, recorder.Header().Get("X-Attempt"))
+			}
+		})
+	}
+}
+
+func TestRetryPolicyPerTryTimeout(t *testing.T) {
+	attempt := 0
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		httptrace.ContextClientTrace(req.Context()).WroteHeaders()
+
+		attempt++
+		if attempt == 1 {
+			<-req.Context().Done()
+			rw.WriteHeader(http.StatusGatewayTimeout)
+			return
+		}
+		rw.WriteHeader(http.StatusOK)
+	})
+
+	retryListener := &countingRetryListener{}
+	retry := NewRetryWithPolicy(2, RetryPolicy{PerTryTimeout: 10 * time.Millisecond}, next, retryListener)
+
+	recorder := httptest.NewRecorder()
+	retry.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, 1, retryListener.timesCalled)
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	retry := NewRetryWithPolicy(10, RetryPolicy{InitialInterval: 100 * time.Millisecond, MaxInterval: time.Second}, nil, nil)
+
+	for retryNumber, interval := range map[int]time.Duration{
+		1: 100 * time.Millisecond,
+		2: 200 * time.Millisecond,
+		3: 400 * time.Millisecond,
+		4: 800 * time.Millisecond,
+		5: time.Second,
+		9: time.Second,
+	} {
+		for i := 0; i < 10; i++ {
+			backoff := retry.backoff(retryNumber)
+			assert.True(t, backoff >= interval/2 && backoff <= interval, "retry %d: backoff %s not within [%s, %s]", retryNumber, backoff, interval/2, interval)
+		}
+	}
+
+	retry = NewRetryWithPolicy(10, RetryPolicy{}, nil, nil)
+	assert.Equal(t, time.Duration(0), retry.backoff(1))
+}
+
+func TestRetryPolicyBackoffCanceled(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusBadGateway)
+	})
+
+	retry := NewRetryWithPolicy(2, RetryPolicy{InitialInterval: time.Hour}, next, &countingRetryListener{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	recorder := httptest.NewRecorder()
+	retry.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost", nil).WithContext(ctx))
+
+	assert.Equal(t, utils.StatusClientClosedRequest, recorder.Code)
+}
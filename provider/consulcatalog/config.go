@@ -25,17 +25,23 @@ func (p *Provider) buildConfigurationV2(catalog []catalogUpdate) *types.Configur
 		"hasTag":       hasTag,
 
 		// Backend functions
-		"getNodeBackendName":    getNodeBackendName,
-		"getServiceBackendName": getServiceBackendName,
-		"getBackendAddress":     getBackendAddress,
-		"getServerName":         getServerName,
-		"getCircuitBreaker":     getCircuitBreaker,
-		"getLoadBalancer":       getLoadBalancer,
-		"getMaxConn":            label.GetMaxConn,
-		"getHealthCheck":        label.GetHealthCheck,
-		"getBuffering":          label.GetBuffering,
-		"getResponseForwarding": label.GetResponseForwarding,
-		"getServer":             p.getServer,
+		"getNodeBackendName":     getNodeBackendName,
+		"getServiceBackendName":  getServiceBackendName,
+		"getBackendAddress":      getBackendAddress,
+		"getServerName":          getServerName,
+		"getCircuitBreaker":      getCircuitBreaker,
+		"getLoadBalancer":        getLoadBalancer,
+		"getMaxConn":             label.GetMaxConn,
+		"getHealthCheck":         label.GetHealthCheck,
+		"getPassiveHealthCheck":  label.GetPassiveHealthCheck,
+		"getBuffering":           label.GetBuffering,
+		"getTransport":           label.GetTransport,
+		"getRetry":               label.GetRetry,
+		"getHedging":             label.GetHedging,
+		"getAdaptiveConcurrency": label.GetAdaptiveConcurrency,
+		"getDNSDiscovery":        label.GetDNSDiscovery,
+		"getResponseForwarding":  label.GetResponseForwarding,
+		"getServer":              p.getServer,
 
 		// Frontend functions
 		"getFrontendRule":        p.getFrontendRule,
@@ -49,6 +55,9 @@ func (p *Provider) buildConfigurationV2(catalog []catalogUpdate) *types.Configur
 		"getWhiteList":           label.GetWhiteList,
 		"getRedirect":            label.GetRedirect,
 		"getErrorPages":          label.GetErrorPages,
+		"getWeightedBackends":    label.GetWeightedBackends,
+		"getBackendsStickiness":  label.GetBackendsStickiness,
+		"getMirror":              label.GetMirror,
 		"getRateLimit":           label.GetRateLimit,
 		"getHeaders":             label.GetHeaders,
 	}
@@ -120,8 +129,9 @@ func (p *Provider) getServer(node *api.ServiceEntry) types.Server {
 	address := getBackendAddress(node)
 
 	return types.Server{
-		URL:    fmt.Sprintf("%s://%s", scheme, net.JoinHostPort(address, strconv.Itoa(node.Service.Port))),
-		Weight: p.getWeight(node.Service.Tags),
+		URL:      fmt.Sprintf("%s://%s", scheme, net.JoinHostPort(address, strconv.Itoa(node.Service.Port))),
+		Weight:   p.getWeight(node.Service.Tags),
+		Priority: p.getIntAttribute(label.SuffixServerPriority, node.Service.Tags, 0),
 	}
 }
 
@@ -33,30 +33,39 @@ func (p *Provider) buildConfigurationV2(containersInspected []dockerData) *types
 		"getDomain":        label.GetFuncString(label.TraefikDomain, p.Domain),
 
 		// Backend functions
-		"getIPAddress":          p.getDeprecatedIPAddress, // TODO: Should we expose getIPPort instead?
-		"getServers":            p.getServers,
-		"getMaxConn":            label.GetMaxConn,
-		"getHealthCheck":        label.GetHealthCheck,
-		"getBuffering":          label.GetBuffering,
-		"getResponseForwarding": label.GetResponseForwarding,
-		"getCircuitBreaker":     label.GetCircuitBreaker,
-		"getLoadBalancer":       label.GetLoadBalancer,
+		"getIPAddress":           p.getDeprecatedIPAddress, // TODO: Should we expose getIPPort instead?
+		"getServers":             p.getServers,
+		"getMaxConn":             label.GetMaxConn,
+		"getHealthCheck":         label.GetHealthCheck,
+		"getPassiveHealthCheck":  label.GetPassiveHealthCheck,
+		"getBuffering":           label.GetBuffering,
+		"getTransport":           label.GetTransport,
+		"getRetry":               label.GetRetry,
+		"getHedging":             label.GetHedging,
+		"getAdaptiveConcurrency": label.GetAdaptiveConcurrency,
+		"getDNSDiscovery":        label.GetDNSDiscovery,
+		"getResponseForwarding":  label.GetResponseForwarding,
+		"getCircuitBreaker":      label.GetCircuitBreaker,
+		"getLoadBalancer":        label.GetLoadBalancer,
 
 		// Frontend functions
-		"getBackendName":       getBackendName,
-		"getPriority":          label.GetFuncInt(label.TraefikFrontendPriority, label.DefaultFrontendPriority),
-		"getPassHostHeader":    label.GetFuncBool(label.TraefikFrontendPassHostHeader, label.DefaultPassHostHeader),
-		"getPassTLSCert":       label.GetFuncBool(label.TraefikFrontendPassTLSCert, label.DefaultPassTLSCert),
-		"getPassTLSClientCert": label.GetTLSClientCert,
-		"getEntryPoints":       label.GetFuncSliceString(label.TraefikFrontendEntryPoints),
-		"getBasicAuth":         label.GetFuncSliceString(label.TraefikFrontendAuthBasic), // Deprecated
-		"getAuth":              label.GetAuth,
-		"getFrontendRule":      p.getFrontendRule,
-		"getRedirect":          label.GetRedirect,
-		"getErrorPages":        label.GetErrorPages,
-		"getRateLimit":         label.GetRateLimit,
-		"getHeaders":           label.GetHeaders,
-		"getWhiteList":         label.GetWhiteList,
+		"getBackendName":        getBackendName,
+		"getPriority":           label.GetFuncInt(label.TraefikFrontendPriority, label.DefaultFrontendPriority),
+		"getPassHostHeader":     label.GetFuncBool(label.TraefikFrontendPassHostHeader, label.DefaultPassHostHeader),
+		"getPassTLSCert":        label.GetFuncBool(label.TraefikFrontendPassTLSCert, label.DefaultPassTLSCert),
+		"getPassTLSClientCert":  label.GetTLSClientCert,
+		"getEntryPoints":        label.GetFuncSliceString(label.TraefikFrontendEntryPoints),
+		"getBasicAuth":          label.GetFuncSliceString(label.TraefikFrontendAuthBasic), // Deprecated
+		"getAuth":               label.GetAuth,
+		"getFrontendRule":       p.getFrontendRule,
+		"getRedirect":           label.GetRedirect,
+		"getErrorPages":         label.GetErrorPages,
+		"getWeightedBackends":   label.GetWeightedBackends,
+		"getBackendsStickiness": label.GetBackendsStickiness,
+		"getMirror":             label.GetMirror,
+		"getRateLimit":          label.GetRateLimit,
+		"getHeaders":            label.GetHeaders,
+		"getWhiteList":          label.GetWhiteList,
 	}
 
 	// filter containers
@@ -393,8 +402,9 @@ func (p *Provider) getServers(containers []dockerData) map[string]types.Server {
 		}
 
 		servers[serverName] = types.Server{
-			URL:    serverURL,
-			Weight: label.GetIntValue(container.SegmentLabels, label.TraefikWeight, label.DefaultWeight),
+			URL:      serverURL,
+			Weight:   label.GetIntValue(container.SegmentLabels, label.TraefikWeight, label.DefaultWeight),
+			Priority: label.GetIntValue(container.SegmentLabels, label.TraefikServerPriority, 0),
 		}
 	}
 
@@ -21,33 +21,42 @@ import (
 func (p *Provider) buildConfigurationV2(instances []ecsInstance) (*types.Configuration, error) {
 	var ecsFuncMap = template.FuncMap{
 		// Backend functions
-		"getHost":               getHost,
-		"getPort":               getPort,
-		"getCircuitBreaker":     label.GetCircuitBreaker,
-		"getLoadBalancer":       label.GetLoadBalancer,
-		"getMaxConn":            label.GetMaxConn,
-		"getHealthCheck":        label.GetHealthCheck,
-		"getBuffering":          label.GetBuffering,
-		"getResponseForwarding": label.GetResponseForwarding,
+		"getHost":                getHost,
+		"getPort":                getPort,
+		"getCircuitBreaker":      label.GetCircuitBreaker,
+		"getLoadBalancer":        label.GetLoadBalancer,
+		"getMaxConn":             label.GetMaxConn,
+		"getHealthCheck":         label.GetHealthCheck,
+		"getPassiveHealthCheck":  label.GetPassiveHealthCheck,
+		"getBuffering":           label.GetBuffering,
+		"getTransport":           label.GetTransport,
+		"getRetry":               label.GetRetry,
+		"getHedging":             label.GetHedging,
+		"getAdaptiveConcurrency": label.GetAdaptiveConcurrency,
+		"getDNSDiscovery":        label.GetDNSDiscovery,
+		"getResponseForwarding":  label.GetResponseForwarding,
 
 		"getServers": getServers,
 
 		// Frontend functions
-		"filterFrontends":      filterFrontends,
-		"getFrontendRule":      p.getFrontendRule,
-		"getFrontendName":      p.getFrontendName,
-		"getPassHostHeader":    label.GetFuncBool(label.TraefikFrontendPassHostHeader, label.DefaultPassHostHeader),
-		"getPassTLSCert":       label.GetFuncBool(label.TraefikFrontendPassTLSCert, label.DefaultPassTLSCert),
-		"getPassTLSClientCert": label.GetTLSClientCert,
-		"getPriority":          label.GetFuncInt(label.TraefikFrontendPriority, label.DefaultFrontendPriority),
-		"getBasicAuth":         label.GetFuncSliceString(label.TraefikFrontendAuthBasic), // Deprecated
-		"getAuth":              label.GetAuth,
-		"getEntryPoints":       label.GetFuncSliceString(label.TraefikFrontendEntryPoints),
-		"getRedirect":          label.GetRedirect,
-		"getErrorPages":        label.GetErrorPages,
-		"getRateLimit":         label.GetRateLimit,
-		"getHeaders":           label.GetHeaders,
-		"getWhiteList":         label.GetWhiteList,
+		"filterFrontends":       filterFrontends,
+		"getFrontendRule":       p.getFrontendRule,
+		"getFrontendName":       p.getFrontendName,
+		"getPassHostHeader":     label.GetFuncBool(label.TraefikFrontendPassHostHeader, label.DefaultPassHostHeader),
+		"getPassTLSCert":        label.GetFuncBool(label.TraefikFrontendPassTLSCert, label.DefaultPassTLSCert),
+		"getPassTLSClientCert":  label.GetTLSClientCert,
+		"getPriority":           label.GetFuncInt(label.TraefikFrontendPriority, label.DefaultFrontendPriority),
+		"getBasicAuth":          label.GetFuncSliceString(label.TraefikFrontendAuthBasic), // Deprecated
+		"getAuth":               label.GetAuth,
+		"getEntryPoints":        label.GetFuncSliceString(label.TraefikFrontendEntryPoints),
+		"getRedirect":           label.GetRedirect,
+		"getErrorPages":         label.GetErrorPages,
+		"getWeightedBackends":   label.GetWeightedBackends,
+		"getBackendsStickiness": label.GetBackendsStickiness,
+		"getMirror":             label.GetMirror,
+		"getRateLimit":          label.GetRateLimit,
+		"getHeaders":            label.GetHeaders,
+		"getWhiteList":          label.GetWhiteList,
 	}
 
 	services := make(map[string][]ecsInstance)
@@ -225,8 +234,9 @@ func getServers(instances []ecsInstance) map[string]types.Server {
 		}
 
 		servers[serverName] = types.Server{
-			URL:    serverURL,
-			Weight: label.GetIntValue(instance.SegmentLabels, label.TraefikWeight, label.DefaultWeight),
+			URL:      serverURL,
+			Weight:   label.GetIntValue(instance.SegmentLabels, label.TraefikWeight, label.DefaultWeight),
+			Priority: label.GetIntValue(instance.SegmentLabels, label.TraefikServerPriority, 0),
 		}
 	}
 
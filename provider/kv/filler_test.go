@@ -105,6 +105,12 @@ func withErrorPage(name string, backend string, query string, statuses ...string
 	}
 }
 
+func withWeightedBackend(name string, weight string) func(map[string]string) {
+	return func(pairs map[string]string) {
+		withPair(pathFrontendBackends+name+pathFrontendBackendsWeight, weight)(pairs)
+	}
+}
+
 func withRateLimit(extractorFunc string, opts ...func(map[string]string)) func(map[string]string) {
 	return func(pairs map[string]string) {
 		pairs[pathFrontendRateLimitExtractorFunc] = extractorFunc
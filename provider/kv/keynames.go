@@ -1,33 +1,84 @@
 package kv
 
 const (
-	pathBackends                                = "/backends/"
-	pathBackendCircuitBreakerExpression         = "/circuitbreaker/expression"
-	pathBackendResponseForwardingFlushInterval  = "/responseforwarding/flushinterval"
-	pathBackendHealthCheckScheme                = "/healthcheck/scheme"
-	pathBackendHealthCheckPath                  = "/healthcheck/path"
-	pathBackendHealthCheckPort                  = "/healthcheck/port"
-	pathBackendHealthCheckInterval              = "/healthcheck/interval"
-	pathBackendHealthCheckHostname              = "/healthcheck/hostname"
-	pathBackendHealthCheckHeaders               = "/healthcheck/headers/"
-	pathBackendLoadBalancerMethod               = "/loadbalancer/method"
-	pathBackendLoadBalancerSticky               = "/loadbalancer/sticky"
-	pathBackendLoadBalancerStickiness           = "/loadbalancer/stickiness"
-	pathBackendLoadBalancerStickinessCookieName = "/loadbalancer/stickiness/cookiename"
-	pathBackendLoadBalancerStickinessSecure     = "/loadbalancer/stickiness/secure"
-	pathBackendLoadBalancerStickinessHTTPOnly   = "/loadbalancer/stickiness/httponly"
-	pathBackendLoadBalancerStickinessSameSite   = "/loadbalancer/stickiness/samesite"
-	pathBackendMaxConnAmount                    = "/maxconn/amount"
-	pathBackendMaxConnExtractorFunc             = "/maxconn/extractorfunc"
-	pathBackendServers                          = "/servers/"
-	pathBackendServerURL                        = "/url"
-	pathBackendServerWeight                     = "/weight"
-	pathBackendBuffering                        = "/buffering/"
-	pathBackendBufferingMaxResponseBodyBytes    = pathBackendBuffering + "maxresponsebodybytes"
-	pathBackendBufferingMemResponseBodyBytes    = pathBackendBuffering + "memresponsebodybytes"
-	pathBackendBufferingMaxRequestBodyBytes     = pathBackendBuffering + "maxrequestbodybytes"
-	pathBackendBufferingMemRequestBodyBytes     = pathBackendBuffering + "memrequestbodybytes"
-	pathBackendBufferingRetryExpression         = pathBackendBuffering + "retryexpression"
+	pathBackends                                    = "/backends/"
+	pathBackendCircuitBreakerExpression             = "/circuitbreaker/expression"
+	pathBackendResponseForwardingFlushInterval      = "/responseforwarding/flushinterval"
+	pathBackendHealthCheckType                      = "/healthcheck/type"
+	pathBackendHealthCheckService                   = "/healthcheck/service"
+	pathBackendHealthCheckScheme                    = "/healthcheck/scheme"
+	pathBackendHealthCheckPath                      = "/healthcheck/path"
+	pathBackendHealthCheckPort                      = "/healthcheck/port"
+	pathBackendHealthCheckInterval                  = "/healthcheck/interval"
+	pathBackendHealthCheckHostname                  = "/healthcheck/hostname"
+	pathBackendHealthCheckHeaders                   = "/healthcheck/headers/"
+	pathBackendHealthCheckMethod                    = "/healthcheck/method"
+	pathBackendHealthCheckStatusCodes               = "/healthcheck/statuscodes"
+	pathBackendHealthCheckBodyRegex                 = "/healthcheck/bodyregex"
+	pathBackendHealthCheckTimeout                   = "/healthcheck/timeout"
+	pathBackendHealthCheckRise                      = "/healthcheck/rise"
+	pathBackendHealthCheckFall                      = "/healthcheck/fall"
+	pathBackendPassiveHealthCheck                   = "/passivehealthcheck/"
+	pathBackendPassiveHealthCheckConsecutiveErrors  = pathBackendPassiveHealthCheck + "consecutiveerrors"
+	pathBackendPassiveHealthCheckEjectionTime       = pathBackendPassiveHealthCheck + "ejectiontime"
+	pathBackendPassiveHealthCheckMaxEjectionPercent = pathBackendPassiveHealthCheck + "maxejectionpercent"
+	pathBackendTransport                            = "/transport/"
+	pathBackendTransportDialTimeout                 = pathBackendTransport + "dialtimeout"
+	pathBackendTransportResponseHeaderTimeout       = pathBackendTransport + "responseheadertimeout"
+	pathBackendTransportIdleConnTimeout             = pathBackendTransport + "idleconntimeout"
+	pathBackendTransportMaxIdleConnsPerHost         = pathBackendTransport + "maxidleconnsperhost"
+	pathBackendTransportServerName                  = pathBackendTransport + "servername"
+	pathBackendTransportRootCAs                     = pathBackendTransport + "rootcas"
+	pathBackendTransportClientCert                  = pathBackendTransport + "clientcert"
+	pathBackendTransportClientKey                   = pathBackendTransport + "clientkey"
+	pathBackendTransportInsecureSkipVerify          = pathBackendTransport + "insecureskipverify"
+	pathBackendTransportDisableHTTP2                = pathBackendTransport + "disablehttp2"
+	pathBackendRetry                                = "/retry/"
+	pathBackendRetryAttempts                        = pathBackendRetry + "attempts"
+	pathBackendRetryStatusCodes                     = pathBackendRetry + "statuscodes"
+	pathBackendRetryMethods                         = pathBackendRetry + "methods"
+	pathBackendRetryInitialInterval                 = pathBackendRetry + "initialinterval"
+	pathBackendRetryMaxInterval                     = pathBackendRetry + "maxinterval"
+	pathBackendRetryPerTryTimeout                   = pathBackendRetry + "pertrytimeout"
+	pathBackendRetryBudgetPercent                   = pathBackendRetry + "budgetpercent"
+	pathBackendHedging                              = "/hedging/"
+	pathBackendHedgingDelay                         = pathBackendHedging + "delay"
+	pathBackendHedgingPercentile                    = pathBackendHedging + "percentile"
+	pathBackendAdaptiveConcurrency                  = "/adaptiveconcurrency/"
+	pathBackendAdaptiveConcurrencyAlgorithm         = pathBackendAdaptiveConcurrency + "algorithm"
+	pathBackendAdaptiveConcurrencyInitialLimit      = pathBackendAdaptiveConcurrency + "initiallimit"
+	pathBackendAdaptiveConcurrencyMinLimit          = pathBackendAdaptiveConcurrency + "minlimit"
+	pathBackendAdaptiveConcurrencyMaxLimit          = pathBackendAdaptiveConcurrency + "maxlimit"
+	pathBackendAdaptiveConcurrencyLatencyThreshold  = pathBackendAdaptiveConcurrency + "latencythreshold"
+	pathBackendDNSDiscovery                         = "/dnsdiscovery/"
+	pathBackendDNSDiscoveryName                     = pathBackendDNSDiscovery + "name"
+	pathBackendDNSDiscoveryPort                     = pathBackendDNSDiscovery + "port"
+	pathBackendDNSDiscoveryScheme                   = pathBackendDNSDiscovery + "scheme"
+	pathBackendDNSDiscoveryInterval                 = pathBackendDNSDiscovery + "interval"
+	pathBackendLoadBalancerMethod                   = "/loadbalancer/method"
+	pathBackendLoadBalancerSticky                   = "/loadbalancer/sticky"
+	pathBackendLoadBalancerStickiness               = "/loadbalancer/stickiness"
+	pathBackendLoadBalancerStickinessCookieName     = "/loadbalancer/stickiness/cookiename"
+	pathBackendLoadBalancerStickinessSecure         = "/loadbalancer/stickiness/secure"
+	pathBackendLoadBalancerStickinessHTTPOnly       = "/loadbalancer/stickiness/httponly"
+	pathBackendLoadBalancerStickinessSameSite       = "/loadbalancer/stickiness/samesite"
+	pathBackendLoadBalancerHashKey                  = "/loadbalancer/hashkey"
+	pathBackendLoadBalancerSlowStart                = "/loadbalancer/slowstart"
+	pathBackendMaxConnAmount                        = "/maxconn/amount"
+	pathBackendMaxConnExtractorFunc                 = "/maxconn/extractorfunc"
+	pathBackendMaxConnQueueLength                   = "/maxconn/queuelength"
+	pathBackendMaxConnQueueTimeout                  = "/maxconn/queuetimeout"
+	pathBackendMaxConnPriorityHeader                = "/maxconn/priorityheader"
+	pathBackendServers                              = "/servers/"
+	pathBackendServerURL                            = "/url"
+	pathBackendServerWeight                         = "/weight"
+	pathBackendServerPriority                       = "/priority"
+	pathBackendBuffering                            = "/buffering/"
+	pathBackendBufferingMaxResponseBodyBytes        = pathBackendBuffering + "maxresponsebodybytes"
+	pathBackendBufferingMemResponseBodyBytes        = pathBackendBuffering + "memresponsebodybytes"
+	pathBackendBufferingMaxRequestBodyBytes         = pathBackendBuffering + "maxrequestbodybytes"
+	pathBackendBufferingMemRequestBodyBytes         = pathBackendBuffering + "memrequestbodybytes"
+	pathBackendBufferingRetryExpression             = pathBackendBuffering + "retryexpression"
 
 	pathFrontends                                            = "/frontends/"
 	pathFrontendBackend                                      = "/backend"
@@ -82,21 +133,31 @@ const (
 	pathFrontendAuthForwardTLSKey                = pathFrontendAuthForwardTLS + "key"
 	pathFrontendAuthForwardTrustForwardHeader    = pathFrontendAuthForward + "trustforwardheader"
 
-	pathFrontendEntryPoints            = "/entrypoints"
-	pathFrontendRedirectEntryPoint     = "/redirect/entrypoint"
-	pathFrontendRedirectRegex          = "/redirect/regex"
-	pathFrontendRedirectReplacement    = "/redirect/replacement"
-	pathFrontendRedirectPermanent      = "/redirect/permanent"
-	pathFrontendErrorPages             = "/errors/"
-	pathFrontendErrorPagesBackend      = "/backend"
-	pathFrontendErrorPagesQuery        = "/query"
-	pathFrontendErrorPagesStatus       = "/status"
-	pathFrontendRateLimit              = "/ratelimit/"
-	pathFrontendRateLimitRateSet       = pathFrontendRateLimit + "rateset/"
-	pathFrontendRateLimitExtractorFunc = pathFrontendRateLimit + "extractorfunc"
-	pathFrontendRateLimitPeriod        = "/period"
-	pathFrontendRateLimitAverage       = "/average"
-	pathFrontendRateLimitBurst         = "/burst"
+	pathFrontendEntryPoints                  = "/entrypoints"
+	pathFrontendRedirectEntryPoint           = "/redirect/entrypoint"
+	pathFrontendRedirectRegex                = "/redirect/regex"
+	pathFrontendRedirectReplacement          = "/redirect/replacement"
+	pathFrontendRedirectPermanent            = "/redirect/permanent"
+	pathFrontendErrorPages                   = "/errors/"
+	pathFrontendErrorPagesBackend            = "/backend"
+	pathFrontendErrorPagesQuery              = "/query"
+	pathFrontendErrorPagesStatus             = "/status"
+	pathFrontendBackends                     = "/backends/"
+	pathFrontendBackendsWeight               = "/weight"
+	pathFrontendBackendsStickiness           = "/backendsstickiness"
+	pathFrontendBackendsStickinessCookieName = "/backendsstickiness/cookiename"
+	pathFrontendBackendsStickinessSecure     = "/backendsstickiness/secure"
+	pathFrontendBackendsStickinessHTTPOnly   = "/backendsstickiness/httponly"
+	pathFrontendBackendsStickinessSameSite   = "/backendsstickiness/samesite"
+	pathFrontendMirrorBackend                = "/mirror/backend"
+	pathFrontendMirrorPercent                = "/mirror/percent"
+	pathFrontendMirrorMaxBodySize            = "/mirror/maxbodysize"
+	pathFrontendRateLimit                    = "/ratelimit/"
+	pathFrontendRateLimitRateSet             = pathFrontendRateLimit + "rateset/"
+	pathFrontendRateLimitExtractorFunc       = pathFrontendRateLimit + "extractorfunc"
+	pathFrontendRateLimitPeriod              = "/period"
+	pathFrontendRateLimitAverage             = "/average"
+	pathFrontendRateLimitBurst               = "/burst"
 
 	pathFrontendCustomRequestHeaders    = "/headers/customrequestheaders/"
 	pathFrontendCustomResponseHeaders   = "/headers/customresponseheaders/"
@@ -45,20 +45,23 @@ func (p *Provider) buildConfiguration() (*types.Configuration, error) {
 		"getTLSSection": p.getTLSSection,
 
 		// Frontend functions
-		"getBackendName":       p.getFuncString(pathFrontendBackend, ""),
-		"getPriority":          p.getFuncInt(pathFrontendPriority, label.DefaultFrontendPriority),
-		"getPassHostHeader":    p.getPassHostHeader(),
-		"getPassTLSCert":       p.getFuncBool(pathFrontendPassTLSCert, label.DefaultPassTLSCert),
-		"getPassTLSClientCert": p.getTLSClientCert,
-		"getEntryPoints":       p.getFuncList(pathFrontendEntryPoints),
-		"getBasicAuth":         p.getFuncList(pathFrontendBasicAuth), // Deprecated
-		"getAuth":              p.getAuth,
-		"getRoutes":            p.getRoutes,
-		"getRedirect":          p.getRedirect,
-		"getErrorPages":        p.getErrorPages,
-		"getRateLimit":         p.getRateLimit,
-		"getHeaders":           p.getHeaders,
-		"getWhiteList":         p.getWhiteList,
+		"getBackendName":        p.getFuncString(pathFrontendBackend, ""),
+		"getPriority":           p.getFuncInt(pathFrontendPriority, label.DefaultFrontendPriority),
+		"getPassHostHeader":     p.getPassHostHeader(),
+		"getPassTLSCert":        p.getFuncBool(pathFrontendPassTLSCert, label.DefaultPassTLSCert),
+		"getPassTLSClientCert":  p.getTLSClientCert,
+		"getEntryPoints":        p.getFuncList(pathFrontendEntryPoints),
+		"getBasicAuth":          p.getFuncList(pathFrontendBasicAuth), // Deprecated
+		"getAuth":               p.getAuth,
+		"getRoutes":             p.getRoutes,
+		"getRedirect":           p.getRedirect,
+		"getErrorPages":         p.getErrorPages,
+		"getWeightedBackends":   p.getWeightedBackends,
+		"getBackendsStickiness": p.getBackendsStickiness,
+		"getMirror":             p.getMirror,
+		"getRateLimit":          p.getRateLimit,
+		"getHeaders":            p.getHeaders,
+		"getWhiteList":          p.getWhiteList,
 
 		// Backend functions
 		"getServers":              p.getServers,
@@ -67,7 +70,13 @@ func (p *Provider) buildConfiguration() (*types.Configuration, error) {
 		"getLoadBalancer":         p.getLoadBalancer,
 		"getMaxConn":              p.getMaxConn,
 		"getHealthCheck":          p.getHealthCheck,
+		"getPassiveHealthCheck":   p.getPassiveHealthCheck,
 		"getBuffering":            p.getBuffering,
+		"getTransport":            p.getTransport,
+		"getRetry":                p.getRetry,
+		"getHedging":              p.getHedging,
+		"getAdaptiveConcurrency":  p.getAdaptiveConcurrency,
+		"getDNSDiscovery":         p.getDNSDiscovery,
 		"getSticky":               p.getSticky,               // Deprecated [breaking]
 		"hasStickinessLabel":      p.hasStickinessLabel,      // Deprecated [breaking]
 		"getStickinessCookieName": p.getStickinessCookieName, // Deprecated [breaking]
@@ -79,8 +88,11 @@ func (p *Provider) buildConfiguration() (*types.Configuration, error) {
 	}
 
 	for key, frontend := range configuration.Frontends {
-		if _, ok := configuration.Backends[frontend.Backend]; !ok {
-			delete(configuration.Frontends, key)
+		for _, backend := range frontend.GetBackends() {
+			if _, ok := configuration.Backends[backend.Name]; !ok {
+				delete(configuration.Frontends, key)
+				break
+			}
 		}
 	}
 
@@ -200,6 +212,47 @@ func (p *Provider) getErrorPages(rootPath string) map[string]*types.ErrorPage {
 	return errorPages
 }
 
+func (p *Provider) getWeightedBackends(rootPath string) []types.WeightedBackend {
+	var backends []types.WeightedBackend
+
+	for _, pathBackend := range p.list(rootPath, pathFrontendBackends) {
+		backends = append(backends, types.WeightedBackend{
+			Name:   p.last(pathBackend),
+			Weight: p.getInt(label.DefaultWeight, pathBackend, pathFrontendBackendsWeight),
+		})
+	}
+
+	return backends
+}
+
+func (p *Provider) getBackendsStickiness(rootPath string) *types.Stickiness {
+	if !p.getBool(false, rootPath, pathFrontendBackendsStickiness) {
+		return nil
+	}
+
+	return &types.Stickiness{
+		CookieName: p.get("", rootPath, pathFrontendBackendsStickinessCookieName),
+		Secure:     p.getBool(false, rootPath, pathFrontendBackendsStickinessSecure),
+		HTTPOnly:   p.getBool(false, rootPath, pathFrontendBackendsStickinessHTTPOnly),
+		SameSite:   p.get("", rootPath, pathFrontendBackendsStickinessSameSite),
+	}
+}
+
+func (p *Provider) getMirror(rootPath string) *types.Mirror {
+	backend := p.get("", rootPath, pathFrontendMirrorBackend)
+	if len(backend) == 0 {
+		return nil
+	}
+
+	percent := p.getInt(label.DefaultFrontendMirrorPercent, rootPath, pathFrontendMirrorPercent)
+
+	return &types.Mirror{
+		Backend:     backend,
+		Percent:     &percent,
+		MaxBodySize: p.getInt64(label.DefaultFrontendMirrorMaxBodySize, rootPath, pathFrontendMirrorMaxBodySize),
+	}
+}
+
 func (p *Provider) getRateLimit(rootPath string) *types.RateLimit {
 	extractorFunc := p.get("", rootPath, pathFrontendRateLimitExtractorFunc)
 	if len(extractorFunc) == 0 {
@@ -273,8 +326,10 @@ func (p *Provider) getHeaders(rootPath string) *types.Headers {
 
 func (p *Provider) getLoadBalancer(rootPath string) *types.LoadBalancer {
 	lb := &types.LoadBalancer{
-		Method: p.get(label.DefaultBackendLoadBalancerMethod, rootPath, pathBackendLoadBalancerMethod),
-		Sticky: p.getSticky(rootPath),
+		Method:    p.get(label.DefaultBackendLoadBalancerMethod, rootPath, pathBackendLoadBalancerMethod),
+		Sticky:    p.getSticky(rootPath),
+		HashKey:   p.get("", rootPath, pathBackendLoadBalancerHashKey),
+		SlowStart: p.get("", rootPath, pathBackendLoadBalancerSlowStart),
 	}
 
 	if p.getBool(false, rootPath, pathBackendLoadBalancerStickiness) {
@@ -325,31 +380,146 @@ func (p *Provider) getMaxConn(rootPath string) *types.MaxConn {
 	}
 
 	return &types.MaxConn{
-		Amount:        amount,
-		ExtractorFunc: extractorFunc,
+		Amount:         amount,
+		ExtractorFunc:  extractorFunc,
+		QueueLength:    p.getInt(0, rootPath, pathBackendMaxConnQueueLength),
+		QueueTimeout:   p.get("", rootPath, pathBackendMaxConnQueueTimeout),
+		PriorityHeader: p.get("", rootPath, pathBackendMaxConnPriorityHeader),
 	}
 }
 
 func (p *Provider) getHealthCheck(rootPath string) *types.HealthCheck {
 	path := p.get("", rootPath, pathBackendHealthCheckPath)
+	healthCheckType := p.get("", rootPath, pathBackendHealthCheckType)
 
-	if len(path) == 0 {
+	if len(path) == 0 && len(healthCheckType) == 0 {
 		return nil
 	}
 
+	service := p.get("", rootPath, pathBackendHealthCheckService)
 	scheme := p.get("", rootPath, pathBackendHealthCheckScheme)
 	port := p.getInt(label.DefaultBackendHealthCheckPort, rootPath, pathBackendHealthCheckPort)
 	interval := p.get("30s", rootPath, pathBackendHealthCheckInterval)
 	hostname := p.get("", rootPath, pathBackendHealthCheckHostname)
 	headers := p.getMap(rootPath, pathBackendHealthCheckHeaders)
+	method := p.get("", rootPath, pathBackendHealthCheckMethod)
+	statusCodes := p.getSlice(rootPath, pathBackendHealthCheckStatusCodes)
+	bodyRegex := p.get("", rootPath, pathBackendHealthCheckBodyRegex)
+	timeout := p.get("", rootPath, pathBackendHealthCheckTimeout)
+	rise := p.getInt(0, rootPath, pathBackendHealthCheckRise)
+	fall := p.getInt(0, rootPath, pathBackendHealthCheckFall)
 
 	return &types.HealthCheck{
-		Scheme:   scheme,
-		Path:     path,
-		Port:     port,
-		Interval: interval,
-		Hostname: hostname,
-		Headers:  headers,
+		Type:        healthCheckType,
+		Service:     service,
+		Scheme:      scheme,
+		Path:        path,
+		Port:        port,
+		Interval:    interval,
+		Hostname:    hostname,
+		Headers:     headers,
+		Method:      method,
+		StatusCodes: statusCodes,
+		BodyRegex:   bodyRegex,
+		Timeout:     timeout,
+		Rise:        rise,
+		Fall:        fall,
+	}
+}
+
+func (p *Provider) getPassiveHealthCheck(rootPath string) *types.PassiveHealthCheck {
+	if len(p.list(rootPath, pathBackendPassiveHealthCheck)) == 0 {
+		return nil
+	}
+
+	return &types.PassiveHealthCheck{
+		ConsecutiveErrors:  p.getInt(0, rootPath, pathBackendPassiveHealthCheckConsecutiveErrors),
+		EjectionTime:       p.get("", rootPath, pathBackendPassiveHealthCheckEjectionTime),
+		MaxEjectionPercent: p.getInt(0, rootPath, pathBackendPassiveHealthCheckMaxEjectionPercent),
+	}
+}
+
+func (p *Provider) getTransport(rootPath string) *types.Transport {
+	if len(p.list(rootPath, pathBackendTransport)) == 0 {
+		return nil
+	}
+
+	var rootCAs tls.FilesOrContents
+	for _, rootCA := range p.getSlice(rootPath, pathBackendTransportRootCAs) {
+		rootCAs = append(rootCAs, tls.FileOrContent(rootCA))
+	}
+
+	var insecureSkipVerify *bool
+	if p.has(rootPath, pathBackendTransportInsecureSkipVerify) {
+		value := p.getBool(false, rootPath, pathBackendTransportInsecureSkipVerify)
+		insecureSkipVerify = &value
+	}
+
+	return &types.Transport{
+		DialTimeout:           p.get("", rootPath, pathBackendTransportDialTimeout),
+		ResponseHeaderTimeout: p.get("", rootPath, pathBackendTransportResponseHeaderTimeout),
+		IdleConnTimeout:       p.get("", rootPath, pathBackendTransportIdleConnTimeout),
+		MaxIdleConnsPerHost:   p.getInt(0, rootPath, pathBackendTransportMaxIdleConnsPerHost),
+		ServerName:            p.get("", rootPath, pathBackendTransportServerName),
+		RootCAs:               rootCAs,
+		ClientCert:            tls.FileOrContent(p.get("", rootPath, pathBackendTransportClientCert)),
+		ClientKey:             tls.FileOrContent(p.get("", rootPath, pathBackendTransportClientKey)),
+		InsecureSkipVerify:    insecureSkipVerify,
+		DisableHTTP2:          p.getBool(false, rootPath, pathBackendTransportDisableHTTP2),
+	}
+}
+
+func (p *Provider) getRetry(rootPath string) *types.Retry {
+	if len(p.list(rootPath, pathBackendRetry)) == 0 {
+		return nil
+	}
+
+	return &types.Retry{
+		Attempts:        p.getInt(0, rootPath, pathBackendRetryAttempts),
+		StatusCodes:     p.getSlice(rootPath, pathBackendRetryStatusCodes),
+		Methods:         p.getSlice(rootPath, pathBackendRetryMethods),
+		InitialInterval: p.get("", rootPath, pathBackendRetryInitialInterval),
+		MaxInterval:     p.get("", rootPath, pathBackendRetryMaxInterval),
+		PerTryTimeout:   p.get("", rootPath, pathBackendRetryPerTryTimeout),
+		BudgetPercent:   p.getInt(0, rootPath, pathBackendRetryBudgetPercent),
+	}
+}
+
+func (p *Provider) getHedging(rootPath string) *types.Hedging {
+	if len(p.list(rootPath, pathBackendHedging)) == 0 {
+		return nil
+	}
+
+	return &types.Hedging{
+		Delay:      p.get("", rootPath, pathBackendHedgingDelay),
+		Percentile: p.getInt(0, rootPath, pathBackendHedgingPercentile),
+	}
+}
+
+func (p *Provider) getAdaptiveConcurrency(rootPath string) *types.AdaptiveConcurrency {
+	if len(p.list(rootPath, pathBackendAdaptiveConcurrency)) == 0 {
+		return nil
+	}
+
+	return &types.AdaptiveConcurrency{
+		Algorithm:        p.get("", rootPath, pathBackendAdaptiveConcurrencyAlgorithm),
+		InitialLimit:     p.getInt(0, rootPath, pathBackendAdaptiveConcurrencyInitialLimit),
+		MinLimit:         p.getInt(0, rootPath, pathBackendAdaptiveConcurrencyMinLimit),
+		MaxLimit:         p.getInt(0, rootPath, pathBackendAdaptiveConcurrencyMaxLimit),
+		LatencyThreshold: p.get("", rootPath, pathBackendAdaptiveConcurrencyLatencyThreshold),
+	}
+}
+
+func (p *Provider) getDNSDiscovery(rootPath string) *types.DNSDiscovery {
+	if len(p.list(rootPath, pathBackendDNSDiscovery)) == 0 {
+		return nil
+	}
+
+	return &types.DNSDiscovery{
+		Name:     p.get("", rootPath, pathBackendDNSDiscoveryName),
+		Port:     p.getInt(0, rootPath, pathBackendDNSDiscoveryPort),
+		Scheme:   p.get("", rootPath, pathBackendDNSDiscoveryScheme),
+		Interval: p.get("", rootPath, pathBackendDNSDiscoveryInterval),
 	}
 }
 
@@ -567,8 +737,9 @@ func (p *Provider) getServers(rootPath string) map[string]types.Server {
 
 		serverName := p.last(serverKey)
 		servers[serverName] = types.Server{
-			URL:    serverURL,
-			Weight: p.getInt(label.DefaultWeight, serverKey, pathBackendServerWeight),
+			URL:      serverURL,
+			Weight:   p.getInt(label.DefaultWeight, serverKey, pathBackendServerWeight),
+			Priority: p.getInt(0, serverKey, pathBackendServerPriority),
 		}
 	}
 
@@ -1615,6 +1615,102 @@ func TestProviderGetErrorPages(t *testing.T) {
 	}
 }
 
+func TestProviderGetMirror(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		rootPath string
+		kvPairs  []*store.KVPair
+		expected *types.Mirror
+	}{
+		{
+			desc:     "when all keys",
+			rootPath: "traefik/frontends/foo",
+			kvPairs: filler("traefik",
+				frontend("foo",
+					withPair(pathFrontendMirrorBackend, "bar"),
+					withPair(pathFrontendMirrorPercent, "10"),
+					withPair(pathFrontendMirrorMaxBodySize, "2048"))),
+			expected: &types.Mirror{
+				Backend:     "bar",
+				Percent:     intPtr(10),
+				MaxBodySize: 2048,
+			},
+		},
+		{
+			desc:     "when only backend",
+			rootPath: "traefik/frontends/foo",
+			kvPairs: filler("traefik",
+				frontend("foo",
+					withPair(pathFrontendMirrorBackend, "bar"))),
+			expected: &types.Mirror{
+				Backend:     "bar",
+				Percent:     intPtr(label.DefaultFrontendMirrorPercent),
+				MaxBodySize: label.DefaultFrontendMirrorMaxBodySize,
+			},
+		},
+		{
+			desc:     "when no keys",
+			rootPath: "traefik/frontends/foo",
+			kvPairs:  filler("traefik", frontend("foo")),
+			expected: nil,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			p := newProviderMock(test.kvPairs)
+
+			actual := p.getMirror(test.rootPath)
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestProviderGetWeightedBackends(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		rootPath string
+		kvPairs  []*store.KVPair
+		expected []types.WeightedBackend
+	}{
+		{
+			desc:     "2 weighted backends",
+			rootPath: "traefik/frontends/foo",
+			kvPairs: filler("traefik",
+				frontend("foo",
+					withWeightedBackend("foo", "90"),
+					withWeightedBackend("bar", "10"))),
+			expected: []types.WeightedBackend{
+				{Name: "bar", Weight: 10},
+				{Name: "foo", Weight: 90},
+			},
+		},
+		{
+			desc:     "return nil when no weighted backends",
+			rootPath: "traefik/frontends/foo",
+			kvPairs:  filler("traefik", frontend("foo")),
+			expected: nil,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			p := newProviderMock(test.kvPairs)
+
+			actual := p.getWeightedBackends(test.rootPath)
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
 func TestProviderGetRateLimit(t *testing.T) {
 	testCases := []struct {
 		desc     string
@@ -1991,6 +2087,30 @@ func TestProviderGetLoadBalancer(t *testing.T) {
 				Method: "drr",
 			},
 		},
+		{
+			desc:     "when hash key is set",
+			rootPath: "traefik/backends/foo",
+			kvPairs: filler("traefik",
+				backend("foo",
+					withPair(pathBackendLoadBalancerMethod, "hash"),
+					withPair(pathBackendLoadBalancerHashKey, "cookie:session"))),
+			expected: &types.LoadBalancer{
+				Method:  "hash",
+				HashKey: "cookie:session",
+			},
+		},
+		{
+			desc:     "when slow start is set",
+			rootPath: "traefik/backends/foo",
+			kvPairs: filler("traefik",
+				backend("foo",
+					withPair(pathBackendLoadBalancerMethod, "wrr"),
+					withPair(pathBackendLoadBalancerSlowStart, "30s"))),
+			expected: &types.LoadBalancer{
+				Method:    "wrr",
+				SlowStart: "30s",
+			},
+		},
 		{
 			desc:     "when sticky is set",
 			rootPath: "traefik/backends/foo",
@@ -2130,6 +2250,23 @@ func TestProviderGetMaxConn(t *testing.T) {
 				ExtractorFunc: "request.host",
 			},
 		},
+		{
+			desc:     "when queue keys are defined",
+			rootPath: "traefik/backends/foo",
+			kvPairs: filler("traefik",
+				backend("foo",
+					withPair(pathBackendMaxConnAmount, "5"),
+					withPair(pathBackendMaxConnQueueLength, "100"),
+					withPair(pathBackendMaxConnQueueTimeout, "2s"),
+					withPair(pathBackendMaxConnPriorityHeader, "X-Priority"))),
+			expected: &types.MaxConn{
+				Amount:         5,
+				ExtractorFunc:  "request.host",
+				QueueLength:    100,
+				QueueTimeout:   "2s",
+				PriorityHeader: "X-Priority",
+			},
+		},
 	}
 
 	for _, test := range testCases {
@@ -2179,6 +2316,42 @@ func TestProviderGetHealthCheck(t *testing.T) {
 				Port:     0,
 			},
 		},
+		{
+			desc:     "when only type defined",
+			rootPath: "traefik/backends/foo",
+			kvPairs: filler("traefik",
+				backend("foo",
+					withPair(pathBackendHealthCheckType, "grpc"),
+					withPair(pathBackendHealthCheckService, "app"))),
+			expected: &types.HealthCheck{
+				Type:     "grpc",
+				Service:  "app",
+				Interval: "30s",
+			},
+		},
+		{
+			desc:     "when expectations, thresholds and timeout defined",
+			rootPath: "traefik/backends/foo",
+			kvPairs: filler("traefik",
+				backend("foo",
+					withPair(pathBackendHealthCheckPath, "/health"),
+					withPair(pathBackendHealthCheckMethod, "HEAD"),
+					withList(pathBackendHealthCheckStatusCodes, "200", "300-399"),
+					withPair(pathBackendHealthCheckBodyRegex, "^ok$"),
+					withPair(pathBackendHealthCheckTimeout, "2s"),
+					withPair(pathBackendHealthCheckRise, "3"),
+					withPair(pathBackendHealthCheckFall, "2"))),
+			expected: &types.HealthCheck{
+				Path:        "/health",
+				Interval:    "30s",
+				Method:      "HEAD",
+				StatusCodes: []string{"200", "300-399"},
+				BodyRegex:   "^ok$",
+				Timeout:     "2s",
+				Rise:        3,
+				Fall:        2,
+			},
+		},
 		{
 			desc:     "should return nil when no path",
 			rootPath: "traefik/backends/foo",
@@ -2204,6 +2377,300 @@ func TestProviderGetHealthCheck(t *testing.T) {
 	}
 }
 
+func TestProviderGetPassiveHealthCheck(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		rootPath string
+		kvPairs  []*store.KVPair
+		expected *types.PassiveHealthCheck
+	}{
+		{
+			desc:     "when all configuration keys defined",
+			rootPath: "traefik/backends/foo",
+			kvPairs: filler("traefik",
+				backend("foo",
+					withPair(pathBackendPassiveHealthCheckConsecutiveErrors, "3"),
+					withPair(pathBackendPassiveHealthCheckEjectionTime, "10s"),
+					withPair(pathBackendPassiveHealthCheckMaxEjectionPercent, "20"))),
+			expected: &types.PassiveHealthCheck{
+				ConsecutiveErrors:  3,
+				EjectionTime:       "10s",
+				MaxEjectionPercent: 20,
+			},
+		},
+		{
+			desc:     "should return nil when no configuration key defined",
+			rootPath: "traefik/backends/foo",
+			kvPairs: filler("traefik",
+				backend("foo",
+					withPair(pathBackendHealthCheckPath, "/health"))),
+			expected: nil,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			p := newProviderMock(test.kvPairs)
+
+			result := p.getPassiveHealthCheck(test.rootPath)
+
+			assert.Equal(t, test.expected, result)
+		})
+	}
+}
+
+func TestProviderGetTransport(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		rootPath string
+		kvPairs  []*store.KVPair
+		expected *types.Transport
+	}{
+		{
+			desc:     "when all configuration keys defined",
+			rootPath: "traefik/backends/foo",
+			kvPairs: filler("traefik",
+				backend("foo",
+					withPair(pathBackendTransportDialTimeout, "5s"),
+					withPair(pathBackendTransportResponseHeaderTimeout, "1m"),
+					withPair(pathBackendTransportIdleConnTimeout, "10s"),
+					withPair(pathBackendTransportMaxIdleConnsPerHost, "10"),
+					withPair(pathBackendTransportServerName, "backend.example.com"),
+					withList(pathBackendTransportRootCAs, "/etc/ca1.pem", "/etc/ca2.pem"),
+					withPair(pathBackendTransportClientCert, "/etc/client.crt"),
+					withPair(pathBackendTransportClientKey, "/etc/client.key"),
+					withPair(pathBackendTransportInsecureSkipVerify, "true"),
+					withPair(pathBackendTransportDisableHTTP2, "true"))),
+			expected: &types.Transport{
+				DialTimeout:           "5s",
+				ResponseHeaderTimeout: "1m",
+				IdleConnTimeout:       "10s",
+				MaxIdleConnsPerHost:   10,
+				ServerName:            "backend.example.com",
+				RootCAs:               tls.FilesOrContents{"/etc/ca1.pem", "/etc/ca2.pem"},
+				ClientCert:            "/etc/client.crt",
+				ClientKey:             "/etc/client.key",
+				InsecureSkipVerify:    boolPtr(true),
+				DisableHTTP2:          true,
+			},
+		},
+		{
+			desc:     "should return nil when no configuration key defined",
+			rootPath: "traefik/backends/foo",
+			kvPairs: filler("traefik",
+				backend("foo",
+					withPair(pathBackendHealthCheckPath, "/health"))),
+			expected: nil,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			p := newProviderMock(test.kvPairs)
+
+			result := p.getTransport(test.rootPath)
+
+			assert.Equal(t, test.expected, result)
+		})
+	}
+}
+
+func TestProviderGetRetry(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		rootPath string
+		kvPairs  []*store.KVPair
+		expected *types.Retry
+	}{
+		{
+			desc:     "when all configuration keys defined",
+			rootPath: "traefik/backends/foo",
+			kvPairs: filler("traefik",
+				backend("foo",
+					withPair(pathBackendRetryAttempts, "3"),
+					withList(pathBackendRetryStatusCodes, "502-504", "429"),
+					withList(pathBackendRetryMethods, "GET", "HEAD"),
+					withPair(pathBackendRetryInitialInterval, "100ms"),
+					withPair(pathBackendRetryMaxInterval, "1s"),
+					withPair(pathBackendRetryPerTryTimeout, "2s"),
+					withPair(pathBackendRetryBudgetPercent, "20"))),
+			expected: &types.Retry{
+				Attempts:        3,
+				StatusCodes:     []string{"502-504", "429"},
+				Methods:         []string{"GET", "HEAD"},
+				InitialInterval: "100ms",
+				MaxInterval:     "1s",
+				PerTryTimeout:   "2s",
+				BudgetPercent:   20,
+			},
+		},
+		{
+			desc:     "should return nil when no configuration key defined",
+			rootPath: "traefik/backends/foo",
+			kvPairs: filler("traefik",
+				backend("foo",
+					withPair(pathBackendHealthCheckPath, "/health"))),
+			expected: nil,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			p := newProviderMock(test.kvPairs)
+
+			result := p.getRetry(test.rootPath)
+
+			assert.Equal(t, test.expected, result)
+		})
+	}
+}
+
+func TestProviderGetHedging(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		rootPath string
+		kvPairs  []*store.KVPair
+		expected *types.Hedging
+	}{
+		{
+			desc:     "when all configuration keys defined",
+			rootPath: "traefik/backends/foo",
+			kvPairs: filler("traefik",
+				backend("foo",
+					withPair(pathBackendHedgingDelay, "50ms"),
+					withPair(pathBackendHedgingPercentile, "95"))),
+			expected: &types.Hedging{
+				Delay:      "50ms",
+				Percentile: 95,
+			},
+		},
+		{
+			desc:     "should return nil when no configuration key defined",
+			rootPath: "traefik/backends/foo",
+			kvPairs: filler("traefik",
+				backend("foo",
+					withPair(pathBackendHealthCheckPath, "/health"))),
+			expected: nil,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			p := newProviderMock(test.kvPairs)
+
+			result := p.getHedging(test.rootPath)
+
+			assert.Equal(t, test.expected, result)
+		})
+	}
+}
+
+func TestProviderGetAdaptiveConcurrency(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		rootPath string
+		kvPairs  []*store.KVPair
+		expected *types.AdaptiveConcurrency
+	}{
+		{
+			desc:     "when all configuration keys defined",
+			rootPath: "traefik/backends/foo",
+			kvPairs: filler("traefik",
+				backend("foo",
+					withPair(pathBackendAdaptiveConcurrencyAlgorithm, "aimd"),
+					withPair(pathBackendAdaptiveConcurrencyInitialLimit, "50"),
+					withPair(pathBackendAdaptiveConcurrencyMinLimit, "10"),
+					withPair(pathBackendAdaptiveConcurrencyMaxLimit, "200"),
+					withPair(pathBackendAdaptiveConcurrencyLatencyThreshold, "200ms"))),
+			expected: &types.AdaptiveConcurrency{
+				Algorithm:        "aimd",
+				InitialLimit:     50,
+				MinLimit:         10,
+				MaxLimit:         200,
+				LatencyThreshold: "200ms",
+			},
+		},
+		{
+			desc:     "should return nil when no configuration key defined",
+			rootPath: "traefik/backends/foo",
+			kvPairs: filler("traefik",
+				backend("foo",
+					withPair(pathBackendHealthCheckPath, "/health"))),
+			expected: nil,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			p := newProviderMock(test.kvPairs)
+
+			result := p.getAdaptiveConcurrency(test.rootPath)
+
+			assert.Equal(t, test.expected, result)
+		})
+	}
+}
+
+func TestProviderGetDNSDiscovery(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		rootPath string
+		kvPairs  []*store.KVPair
+		expected *types.DNSDiscovery
+	}{
+		{
+			desc:     "when all configuration keys defined",
+			rootPath: "traefik/backends/foo",
+			kvPairs: filler("traefik",
+				backend("foo",
+					withPair(pathBackendDNSDiscoveryName, "_http._tcp.app.example.com"),
+					withPair(pathBackendDNSDiscoveryScheme, "https"),
+					withPair(pathBackendDNSDiscoveryInterval, "10s"))),
+			expected: &types.DNSDiscovery{
+				Name:     "_http._tcp.app.example.com",
+				Scheme:   "https",
+				Interval: "10s",
+			},
+		},
+		{
+			desc:     "should return nil when no configuration key defined",
+			rootPath: "traefik/backends/foo",
+			kvPairs: filler("traefik",
+				backend("foo",
+					withPair(pathBackendHealthCheckPath, "/health"))),
+			expected: nil,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			p := newProviderMock(test.kvPairs)
+
+			result := p.getDNSDiscovery(test.rootPath)
+
+			assert.Equal(t, test.expected, result)
+		})
+	}
+}
+
 func TestProviderGetBufferingReal(t *testing.T) {
 	testCases := []struct {
 		desc     string
@@ -2583,6 +3050,26 @@ func TestProviderGetServers(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc:     "should return the server priorities",
+			rootPath: "traefik/backends/foo",
+			kvPairs: filler("traefik",
+				backend("foo",
+					withPair(pathBackendServers+"server1/url", "http://172.17.0.2:80"),
+					withPair(pathBackendServers+"server2/url", "http://172.17.0.3:80"),
+					withPair(pathBackendServers+"server2/priority", "1"))),
+			expected: map[string]types.Server{
+				"server1": {
+					URL:    "http://172.17.0.2:80",
+					Weight: label.DefaultWeight,
+				},
+				"server2": {
+					URL:      "http://172.17.0.3:80",
+					Weight:   label.DefaultWeight,
+					Priority: 1,
+				},
+			},
+		},
 	}
 
 	for _, test := range testCases {
@@ -2598,3 +3085,11 @@ func TestProviderGetServers(t *testing.T) {
 		})
 	}
 }
+
+func intPtr(value int) *int {
+	return &value
+}
+
+func boolPtr(value bool) *bool {
+	return &value
+}
@@ -30,12 +30,17 @@ const (
 	DefaultBackendLoadbalancerStickinessHTTPOnly   = false
 	DefaultBackendLoadbalancerStickinessSameSite   = ""
 	DefaultBackendHealthCheckPort                  = 0
+	DefaultFrontendMirrorPercent                   = 100
+	DefaultFrontendMirrorMaxBodySize               = 1 << 20
 )
 
 var (
 	// RegexpFrontendErrorPage used to extract error pages from label
 	RegexpFrontendErrorPage = regexp.MustCompile(`^traefik\.frontend\.errors\.(?P<name>[^ .]+)\.(?P<field>[^ .]+)$`)
 
+	// RegexpFrontendBackend used to extract weighted backends from label
+	RegexpFrontendBackend = regexp.MustCompile(`^traefik\.frontend\.backends\.(?P<name>[^ .]+)\.(?P<field>[^ .]+)$`)
+
 	// RegexpFrontendRateLimit used to extract rate limits from label
 	RegexpFrontendRateLimit = regexp.MustCompile(`^traefik\.frontend\.rateLimit\.rateSet\.(?P<name>[^ .]+)\.(?P<field>[^ .]+)$`)
 )
@@ -12,15 +12,61 @@ const (
 	SuffixProtocol                                             = "protocol"
 	SuffixTags                                                 = "tags"
 	SuffixWeight                                               = "weight"
+	SuffixServerPriority                                       = "server.priority"
 	SuffixBackendID                                            = "backend.id"
 	SuffixBackendCircuitBreaker                                = "backend.circuitbreaker"
 	SuffixBackendCircuitBreakerExpression                      = "backend.circuitbreaker.expression"
+	SuffixBackendHealthCheckType                               = "backend.healthcheck.type"
+	SuffixBackendHealthCheckService                            = "backend.healthcheck.service"
 	SuffixBackendHealthCheckScheme                             = "backend.healthcheck.scheme"
 	SuffixBackendHealthCheckPath                               = "backend.healthcheck.path"
 	SuffixBackendHealthCheckPort                               = "backend.healthcheck.port"
 	SuffixBackendHealthCheckInterval                           = "backend.healthcheck.interval"
 	SuffixBackendHealthCheckHostname                           = "backend.healthcheck.hostname"
 	SuffixBackendHealthCheckHeaders                            = "backend.healthcheck.headers"
+	SuffixBackendHealthCheckMethod                             = "backend.healthcheck.method"
+	SuffixBackendHealthCheckStatusCodes                        = "backend.healthcheck.statusCodes"
+	SuffixBackendHealthCheckBodyRegex                          = "backend.healthcheck.bodyRegex"
+	SuffixBackendHealthCheckTimeout                            = "backend.healthcheck.timeout"
+	SuffixBackendHealthCheckRise                               = "backend.healthcheck.rise"
+	SuffixBackendHealthCheckFall                               = "backend.healthcheck.fall"
+	SuffixBackendPassiveHealthCheck                            = "backend.passivehealthcheck"
+	SuffixBackendPassiveHealthCheckConsecutiveErrors           = SuffixBackendPassiveHealthCheck + ".consecutiveErrors"
+	SuffixBackendPassiveHealthCheckEjectionTime                = SuffixBackendPassiveHealthCheck + ".ejectionTime"
+	SuffixBackendPassiveHealthCheckMaxEjectionPercent          = SuffixBackendPassiveHealthCheck + ".maxEjectionPercent"
+	SuffixBackendTransport                                     = "backend.transport"
+	SuffixBackendTransportDialTimeout                          = SuffixBackendTransport + ".dialTimeout"
+	SuffixBackendTransportResponseHeaderTimeout                = SuffixBackendTransport + ".responseHeaderTimeout"
+	SuffixBackendTransportIdleConnTimeout                      = SuffixBackendTransport + ".idleConnTimeout"
+	SuffixBackendTransportMaxIdleConnsPerHost                  = SuffixBackendTransport + ".maxIdleConnsPerHost"
+	SuffixBackendTransportServerName                           = SuffixBackendTransport + ".serverName"
+	SuffixBackendTransportRootCAs                              = SuffixBackendTransport + ".rootCAs"
+	SuffixBackendTransportClientCert                           = SuffixBackendTransport + ".clientCert"
+	SuffixBackendTransportClientKey                            = SuffixBackendTransport + ".clientKey"
+	SuffixBackendTransportInsecureSkipVerify                   = SuffixBackendTransport + ".insecureSkipVerify"
+	SuffixBackendTransportDisableHTTP2                         = SuffixBackendTransport + ".disableHTTP2"
+	SuffixBackendRetry                                         = "backend.retry"
+	SuffixBackendRetryAttempts                                 = SuffixBackendRetry + ".attempts"
+	SuffixBackendRetryStatusCodes                              = SuffixBackendRetry + ".statusCodes"
+	SuffixBackendRetryMethods                                  = SuffixBackendRetry + ".methods"
+	SuffixBackendRetryInitialInterval                          = SuffixBackendRetry + ".initialInterval"
+	SuffixBackendRetryMaxInterval                              = SuffixBackendRetry + ".maxInterval"
+	SuffixBackendRetryPerTryTimeout                            = SuffixBackendRetry + ".perTryTimeout"
+	SuffixBackendRetryBudgetPercent                            = SuffixBackendRetry + ".budgetPercent"
+	SuffixBackendHedging                                       = "backend.hedging"
+	SuffixBackendHedgingDelay                                  = SuffixBackendHedging + ".delay"
+	SuffixBackendHedgingPercentile                             = SuffixBackendHedging + ".percentile"
+	SuffixBackendAdaptiveConcurrency                           = "backend.adaptiveconcurrency"
+	SuffixBackendAdaptiveConcurrencyAlgorithm                  = SuffixBackendAdaptiveConcurrency + ".algorithm"
+	SuffixBackendAdaptiveConcurrencyInitialLimit               = SuffixBackendAdaptiveConcurrency + ".initialLimit"
+	SuffixBackendAdaptiveConcurrencyMinLimit                   = SuffixBackendAdaptiveConcurrency + ".minLimit"
+	SuffixBackendAdaptiveConcurrencyMaxLimit                   = SuffixBackendAdaptiveConcurrency + ".maxLimit"
+	SuffixBackendAdaptiveConcurrencyLatencyThreshold           = SuffixBackendAdaptiveConcurrency + ".latencyThreshold"
+	SuffixBackendDNSDiscovery                                  = "backend.dnsdiscovery"
+	SuffixBackendDNSDiscoveryName                              = SuffixBackendDNSDiscovery + ".name"
+	SuffixBackendDNSDiscoveryPort                              = SuffixBackendDNSDiscovery + ".port"
+	SuffixBackendDNSDiscoveryScheme                            = SuffixBackendDNSDiscovery + ".scheme"
+	SuffixBackendDNSDiscoveryInterval                          = SuffixBackendDNSDiscovery + ".interval"
 	SuffixBackendLoadBalancer                                  = "backend.loadbalancer"
 	SuffixBackendLoadBalancerMethod                            = SuffixBackendLoadBalancer + ".method"
 	SuffixBackendLoadBalancerSticky                            = SuffixBackendLoadBalancer + ".sticky"
@@ -29,8 +75,13 @@ const (
 	SuffixBackendLoadBalancerStickinessSecure                  = SuffixBackendLoadBalancer + ".stickiness.secure"
 	SuffixBackendLoadBalancerStickinessCHTTPOnly               = SuffixBackendLoadBalancer + ".stickiness.httpOnly"
 	SuffixBackendLoadBalancerStickinessSameSite                = SuffixBackendLoadBalancer + ".stickiness.sameSite"
+	SuffixBackendLoadBalancerHashKey                           = SuffixBackendLoadBalancer + ".hashKey"
+	SuffixBackendLoadBalancerSlowStart                         = SuffixBackendLoadBalancer + ".slowStart"
 	SuffixBackendMaxConnAmount                                 = "backend.maxconn.amount"
 	SuffixBackendMaxConnExtractorFunc                          = "backend.maxconn.extractorfunc"
+	SuffixBackendMaxConnQueueLength                            = "backend.maxconn.queuelength"
+	SuffixBackendMaxConnQueueTimeout                           = "backend.maxconn.queuetimeout"
+	SuffixBackendMaxConnPriorityHeader                         = "backend.maxconn.priorityheader"
 	SuffixBackendBuffering                                     = "backend.buffering"
 	SuffixBackendResponseForwardingFlushInterval               = "backend.responseForwarding.flushInterval"
 	SuffixBackendBufferingMaxRequestBodyBytes                  = SuffixBackendBuffering + ".maxRequestBodyBytes"
@@ -126,16 +177,62 @@ const (
 	TraefikProtocol                                            = Prefix + SuffixProtocol
 	TraefikTags                                                = Prefix + SuffixTags
 	TraefikWeight                                              = Prefix + SuffixWeight
+	TraefikServerPriority                                      = Prefix + SuffixServerPriority
 	TraefikBackend                                             = Prefix + SuffixBackend
 	TraefikBackendID                                           = Prefix + SuffixBackendID
 	TraefikBackendCircuitBreaker                               = Prefix + SuffixBackendCircuitBreaker
 	TraefikBackendCircuitBreakerExpression                     = Prefix + SuffixBackendCircuitBreakerExpression
+	TraefikBackendHealthCheckType                              = Prefix + SuffixBackendHealthCheckType
+	TraefikBackendHealthCheckService                           = Prefix + SuffixBackendHealthCheckService
 	TraefikBackendHealthCheckScheme                            = Prefix + SuffixBackendHealthCheckScheme
 	TraefikBackendHealthCheckPath                              = Prefix + SuffixBackendHealthCheckPath
 	TraefikBackendHealthCheckPort                              = Prefix + SuffixBackendHealthCheckPort
 	TraefikBackendHealthCheckInterval                          = Prefix + SuffixBackendHealthCheckInterval
 	TraefikBackendHealthCheckHostname                          = Prefix + SuffixBackendHealthCheckHostname
 	TraefikBackendHealthCheckHeaders                           = Prefix + SuffixBackendHealthCheckHeaders
+	TraefikBackendHealthCheckMethod                            = Prefix + SuffixBackendHealthCheckMethod
+	TraefikBackendHealthCheckStatusCodes                       = Prefix + SuffixBackendHealthCheckStatusCodes
+	TraefikBackendHealthCheckBodyRegex                         = Prefix + SuffixBackendHealthCheckBodyRegex
+	TraefikBackendHealthCheckTimeout                           = Prefix + SuffixBackendHealthCheckTimeout
+	TraefikBackendHealthCheckRise                              = Prefix + SuffixBackendHealthCheckRise
+	TraefikBackendHealthCheckFall                              = Prefix + SuffixBackendHealthCheckFall
+	TraefikBackendPassiveHealthCheck                           = Prefix + SuffixBackendPassiveHealthCheck
+	TraefikBackendPassiveHealthCheckConsecutiveErrors          = Prefix + SuffixBackendPassiveHealthCheckConsecutiveErrors
+	TraefikBackendPassiveHealthCheckEjectionTime               = Prefix + SuffixBackendPassiveHealthCheckEjectionTime
+	TraefikBackendPassiveHealthCheckMaxEjectionPercent         = Prefix + SuffixBackendPassiveHealthCheckMaxEjectionPercent
+	TraefikBackendTransport                                    = Prefix + SuffixBackendTransport
+	TraefikBackendTransportDialTimeout                         = Prefix + SuffixBackendTransportDialTimeout
+	TraefikBackendTransportResponseHeaderTimeout               = Prefix + SuffixBackendTransportResponseHeaderTimeout
+	TraefikBackendTransportIdleConnTimeout                     = Prefix + SuffixBackendTransportIdleConnTimeout
+	TraefikBackendTransportMaxIdleConnsPerHost                 = Prefix + SuffixBackendTransportMaxIdleConnsPerHost
+	TraefikBackendTransportServerName                          = Prefix + SuffixBackendTransportServerName
+	TraefikBackendTransportRootCAs                             = Prefix + SuffixBackendTransportRootCAs
+	TraefikBackendTransportClientCert                          = Prefix + SuffixBackendTransportClientCert
+	TraefikBackendTransportClientKey                           = Prefix + SuffixBackendTransportClientKey
+	TraefikBackendTransportInsecureSkipVerify                  = Prefix + SuffixBackendTransportInsecureSkipVerify
+	TraefikBackendTransportDisableHTTP2                        = Prefix + SuffixBackendTransportDisableHTTP2
+	TraefikBackendRetry                                        = Prefix + SuffixBackendRetry
+	TraefikBackendRetryAttempts                                = Prefix + SuffixBackendRetryAttempts
+	TraefikBackendRetryStatusCodes                             = Prefix + SuffixBackendRetryStatusCodes
+	TraefikBackendRetryMethods                                 = Prefix + SuffixBackendRetryMethods
+	TraefikBackendRetryInitialInterval                         = Prefix + SuffixBackendRetryInitialInterval
+	TraefikBackendRetryMaxInterval                             = Prefix + SuffixBackendRetryMaxInterval
+	TraefikBackendRetryPerTryTimeout                           = Prefix + SuffixBackendRetryPerTryTimeout
+	TraefikBackendRetryBudgetPercent                           = Prefix + SuffixBackendRetryBudgetPercent
+	TraefikBackendHedging                                      = Prefix + SuffixBackendHedging
+	TraefikBackendHedgingDelay                                 = Prefix + SuffixBackendHedgingDelay
+	TraefikBackendHedgingPercentile                            = Prefix + SuffixBackendHedgingPercentile
+	TraefikBackendAdaptiveConcurrency                          = Prefix + SuffixBackendAdaptiveConcurrency
+	TraefikBackendAdaptiveConcurrencyAlgorithm                 = Prefix + SuffixBackendAdaptiveConcurrencyAlgorithm
+	TraefikBackendAdaptiveConcurrencyInitialLimit              = Prefix + SuffixBackendAdaptiveConcurrencyInitialLimit
+	TraefikBackendAdaptiveConcurrencyMinLimit                  = Prefix + SuffixBackendAdaptiveConcurrencyMinLimit
+	TraefikBackendAdaptiveConcurrencyMaxLimit                  = Prefix + SuffixBackendAdaptiveConcurrencyMaxLimit
+	TraefikBackendAdaptiveConcurrencyLatencyThreshold          = Prefix + SuffixBackendAdaptiveConcurrencyLatencyThreshold
+	TraefikBackendDNSDiscovery                                 = Prefix + SuffixBackendDNSDiscovery
+	TraefikBackendDNSDiscoveryName                             = Prefix + SuffixBackendDNSDiscoveryName
+	TraefikBackendDNSDiscoveryPort                             = Prefix + SuffixBackendDNSDiscoveryPort
+	TraefikBackendDNSDiscoveryScheme                           = Prefix + SuffixBackendDNSDiscoveryScheme
+	TraefikBackendDNSDiscoveryInterval                         = Prefix + SuffixBackendDNSDiscoveryInterval
 	TraefikBackendLoadBalancer                                 = Prefix + SuffixBackendLoadBalancer
 	TraefikBackendLoadBalancerMethod                           = Prefix + SuffixBackendLoadBalancerMethod
 	TraefikBackendLoadBalancerSticky                           = Prefix + SuffixBackendLoadBalancerSticky
@@ -145,9 +242,14 @@ const (
 	TraefikBackendLoadBalancerStickinessSecure   = Prefix + SuffixBackendLoadBalancerStickinessSecure
 	TraefikBackendLoadBalancerStickinessHTTPOnly = Prefix + SuffixBackendLoadBalancerStickinessCHTTPOnly
 	TraefikBackendLoadBalancerStickinessSameSite = Prefix + SuffixBackendLoadBalancerStickinessSameSite
+	TraefikBackendLoadBalancerHashKey            = Prefix + SuffixBackendLoadBalancerHashKey
+	TraefikBackendLoadBalancerSlowStart          = Prefix + SuffixBackendLoadBalancerSlowStart
 
 	TraefikBackendMaxConnAmount                                 = Prefix + SuffixBackendMaxConnAmount
 	TraefikBackendMaxConnExtractorFunc                          = Prefix + SuffixBackendMaxConnExtractorFunc
+	TraefikBackendMaxConnQueueLength                            = Prefix + SuffixBackendMaxConnQueueLength
+	TraefikBackendMaxConnQueueTimeout                           = Prefix + SuffixBackendMaxConnQueueTimeout
+	TraefikBackendMaxConnPriorityHeader                         = Prefix + SuffixBackendMaxConnPriorityHeader
 	TraefikBackendBuffering                                     = Prefix + SuffixBackendBuffering
 	TraefikBackendResponseForwardingFlushInterval               = Prefix + SuffixBackendResponseForwardingFlushInterval
 	TraefikBackendBufferingMaxRequestBodyBytes                  = Prefix + SuffixBackendBufferingMaxRequestBodyBytes
@@ -241,4 +343,23 @@ const (
 	SuffixRateLimitPeriod                                       = "period"
 	SuffixRateLimitAverage                                      = "average"
 	SuffixRateLimitBurst                                        = "burst"
+	BaseFrontendBackends                                        = "frontend.backends."
+	SuffixWeightedBackendWeight                                 = "weight"
+	SuffixFrontendBackendsStickiness                            = "frontend.backendsStickiness"
+	SuffixFrontendBackendsStickinessCookieName                  = SuffixFrontendBackendsStickiness + ".cookieName"
+	SuffixFrontendBackendsStickinessSecure                      = SuffixFrontendBackendsStickiness + ".secure"
+	SuffixFrontendBackendsStickinessHTTPOnly                    = SuffixFrontendBackendsStickiness + ".httpOnly"
+	SuffixFrontendBackendsStickinessSameSite                    = SuffixFrontendBackendsStickiness + ".sameSite"
+	TraefikFrontendBackendsStickiness                           = Prefix + SuffixFrontendBackendsStickiness
+	TraefikFrontendBackendsStickinessCookieName                 = Prefix + SuffixFrontendBackendsStickinessCookieName
+	TraefikFrontendBackendsStickinessSecure                     = Prefix + SuffixFrontendBackendsStickinessSecure
+	TraefikFrontendBackendsStickinessHTTPOnly                   = Prefix + SuffixFrontendBackendsStickinessHTTPOnly
+	TraefikFrontendBackendsStickinessSameSite                   = Prefix + SuffixFrontendBackendsStickinessSameSite
+	SuffixFrontendMirror                                        = "frontend.mirror"
+	SuffixFrontendMirrorBackend                                 = SuffixFrontendMirror + ".backend"
+	SuffixFrontendMirrorPercent                                 = SuffixFrontendMirror + ".percent"
+	SuffixFrontendMirrorMaxBodySize                             = SuffixFrontendMirror + ".maxBodySize"
+	TraefikFrontendMirrorBackend                                = Prefix + SuffixFrontendMirrorBackend
+	TraefikFrontendMirrorPercent                                = Prefix + SuffixFrontendMirrorPercent
+	TraefikFrontendMirrorMaxBodySize                            = Prefix + SuffixFrontendMirrorMaxBodySize
 )
@@ -3,11 +3,13 @@ package label
 import (
 	"math"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/containous/flaeg"
 	"github.com/pteich/traefik/log"
+	"github.com/pteich/traefik/tls"
 	"github.com/pteich/traefik/types"
 )
 
@@ -231,6 +233,76 @@ func ParseErrorPages(labels map[string]string, labelPrefix string, labelRegex *r
 	return errorPages
 }
 
+// GetWeightedBackends Create weighted backends from labels
+func GetWeightedBackends(labels map[string]string) []types.WeightedBackend {
+	prefix := Prefix + BaseFrontendBackends
+	return ParseWeightedBackends(labels, prefix, RegexpFrontendBackend)
+}
+
+// ParseWeightedBackends parse weighted backends to create WeightedBackend structs, sorted by name
+func ParseWeightedBackends(labels map[string]string, labelPrefix string, labelRegex *regexp.Regexp) []types.WeightedBackend {
+	var backends []types.WeightedBackend
+
+	for lblName, value := range labels {
+		if strings.HasPrefix(lblName, labelPrefix) {
+			submatch := labelRegex.FindStringSubmatch(lblName)
+			if len(submatch) != 3 {
+				log.Errorf("Invalid weighted backend label: %s, sub-match: %v", lblName, submatch)
+				continue
+			}
+
+			if submatch[2] != SuffixWeightedBackendWeight {
+				log.Errorf("Invalid weighted backend label: %s", lblName)
+				continue
+			}
+
+			weight, err := strconv.Atoi(value)
+			if err != nil {
+				log.Errorf("Unable to parse %q: %q, skipping... %v", lblName, value, err)
+				continue
+			}
+
+			backends = append(backends, types.WeightedBackend{Name: submatch[1], Weight: weight})
+		}
+	}
+
+	sort.Slice(backends, func(i, j int) bool {
+		return backends[i].Name < backends[j].Name
+	})
+
+	return backends
+}
+
+// GetBackendsStickiness Create the stickiness between the backends of a frontend from labels
+func GetBackendsStickiness(labels map[string]string) *types.Stickiness {
+	if !GetBoolValue(labels, TraefikFrontendBackendsStickiness, false) {
+		return nil
+	}
+
+	return &types.Stickiness{
+		CookieName: GetStringValue(labels, TraefikFrontendBackendsStickinessCookieName, DefaultBackendLoadbalancerStickinessCookieName),
+		Secure:     GetBoolValue(labels, TraefikFrontendBackendsStickinessSecure, DefaultBackendLoadbalancerStickinessSecure),
+		HTTPOnly:   GetBoolValue(labels, TraefikFrontendBackendsStickinessHTTPOnly, DefaultBackendLoadbalancerStickinessHTTPOnly),
+		SameSite:   GetStringValue(labels, TraefikFrontendBackendsStickinessSameSite, DefaultBackendLoadbalancerStickinessSameSite),
+	}
+}
+
+// GetMirror Create mirror from labels
+func GetMirror(labels map[string]string) *types.Mirror {
+	backend := GetStringValue(labels, TraefikFrontendMirrorBackend, "")
+	if len(backend) == 0 {
+		return nil
+	}
+
+	percent := GetIntValue(labels, TraefikFrontendMirrorPercent, DefaultFrontendMirrorPercent)
+
+	return &types.Mirror{
+		Backend:     backend,
+		Percent:     &percent,
+		MaxBodySize: GetInt64Value(labels, TraefikFrontendMirrorMaxBodySize, DefaultFrontendMirrorMaxBodySize),
+	}
+}
+
 // GetRateLimit Create rate limits from labels
 func GetRateLimit(labels map[string]string) *types.RateLimit {
 	extractorFunc := GetStringValue(labels, TraefikFrontendRateLimitExtractorFunc, "")
@@ -347,31 +419,152 @@ func GetMaxConn(labels map[string]string) *types.MaxConn {
 	}
 
 	return &types.MaxConn{
-		Amount:        amount,
-		ExtractorFunc: extractorFunc,
+		Amount:         amount,
+		ExtractorFunc:  extractorFunc,
+		QueueLength:    GetIntValue(labels, TraefikBackendMaxConnQueueLength, 0),
+		QueueTimeout:   GetStringValue(labels, TraefikBackendMaxConnQueueTimeout, ""),
+		PriorityHeader: GetStringValue(labels, TraefikBackendMaxConnPriorityHeader, ""),
 	}
 }
 
 // GetHealthCheck Create health check from labels
 func GetHealthCheck(labels map[string]string) *types.HealthCheck {
 	path := GetStringValue(labels, TraefikBackendHealthCheckPath, "")
-	if len(path) == 0 {
+	healthCheckType := GetStringValue(labels, TraefikBackendHealthCheckType, "")
+	if len(path) == 0 && len(healthCheckType) == 0 {
 		return nil
 	}
 
+	service := GetStringValue(labels, TraefikBackendHealthCheckService, "")
 	scheme := GetStringValue(labels, TraefikBackendHealthCheckScheme, "")
 	port := GetIntValue(labels, TraefikBackendHealthCheckPort, DefaultBackendHealthCheckPort)
 	interval := GetStringValue(labels, TraefikBackendHealthCheckInterval, "")
 	hostname := GetStringValue(labels, TraefikBackendHealthCheckHostname, "")
 	headers := GetMapValue(labels, TraefikBackendHealthCheckHeaders)
+	method := GetStringValue(labels, TraefikBackendHealthCheckMethod, "")
+	statusCodes := GetSliceStringValue(labels, TraefikBackendHealthCheckStatusCodes)
+	bodyRegex := GetStringValue(labels, TraefikBackendHealthCheckBodyRegex, "")
+	timeout := GetStringValue(labels, TraefikBackendHealthCheckTimeout, "")
+	rise := GetIntValue(labels, TraefikBackendHealthCheckRise, 0)
+	fall := GetIntValue(labels, TraefikBackendHealthCheckFall, 0)
 
 	return &types.HealthCheck{
-		Scheme:   scheme,
-		Path:     path,
-		Port:     port,
-		Interval: interval,
-		Hostname: hostname,
-		Headers:  headers,
+		Type:        healthCheckType,
+		Service:     service,
+		Scheme:      scheme,
+		Path:        path,
+		Port:        port,
+		Interval:    interval,
+		Hostname:    hostname,
+		Headers:     headers,
+		Method:      method,
+		StatusCodes: statusCodes,
+		BodyRegex:   bodyRegex,
+		Timeout:     timeout,
+		Rise:        rise,
+		Fall:        fall,
+	}
+}
+
+// GetPassiveHealthCheck Create passive health check from labels
+func GetPassiveHealthCheck(labels map[string]string) *types.PassiveHealthCheck {
+	if !HasPrefix(labels, TraefikBackendPassiveHealthCheck) {
+		return nil
+	}
+
+	return &types.PassiveHealthCheck{
+		ConsecutiveErrors:  GetIntValue(labels, TraefikBackendPassiveHealthCheckConsecutiveErrors, 0),
+		EjectionTime:       GetStringValue(labels, TraefikBackendPassiveHealthCheckEjectionTime, ""),
+		MaxEjectionPercent: GetIntValue(labels, TraefikBackendPassiveHealthCheckMaxEjectionPercent, 0),
+	}
+}
+
+// GetTransport Create transport from labels
+func GetTransport(labels map[string]string) *types.Transport {
+	if !HasPrefix(labels, TraefikBackendTransport) {
+		return nil
+	}
+
+	var rootCAs tls.FilesOrContents
+	for _, rootCA := range GetSliceStringValue(labels, TraefikBackendTransportRootCAs) {
+		rootCAs = append(rootCAs, tls.FileOrContent(rootCA))
+	}
+
+	var insecureSkipVerify *bool
+	if Has(labels, TraefikBackendTransportInsecureSkipVerify) {
+		value := GetBoolValue(labels, TraefikBackendTransportInsecureSkipVerify, false)
+		insecureSkipVerify = &value
+	}
+
+	return &types.Transport{
+		DialTimeout:           GetStringValue(labels, TraefikBackendTransportDialTimeout, ""),
+		ResponseHeaderTimeout: GetStringValue(labels, TraefikBackendTransportResponseHeaderTimeout, ""),
+		IdleConnTimeout:       GetStringValue(labels, TraefikBackendTransportIdleConnTimeout, ""),
+		MaxIdleConnsPerHost:   GetIntValue(labels, TraefikBackendTransportMaxIdleConnsPerHost, 0),
+		ServerName:            GetStringValue(labels, TraefikBackendTransportServerName, ""),
+		RootCAs:               rootCAs,
+		ClientCert:            tls.FileOrContent(GetStringValue(labels, TraefikBackendTransportClientCert, "")),
+		ClientKey:             tls.FileOrContent(GetStringValue(labels, TraefikBackendTransportClientKey, "")),
+		InsecureSkipVerify:    insecureSkipVerify,
+		DisableHTTP2:          GetBoolValue(labels, TraefikBackendTransportDisableHTTP2, false),
+	}
+}
+
+// GetRetry Create retry from labels
+func GetRetry(labels map[string]string) *types.Retry {
+	if !HasPrefix(labels, TraefikBackendRetry) {
+		return nil
+	}
+
+	return &types.Retry{
+		Attempts:        GetIntValue(labels, TraefikBackendRetryAttempts, 0),
+		StatusCodes:     GetSliceStringValue(labels, TraefikBackendRetryStatusCodes),
+		Methods:         GetSliceStringValue(labels, TraefikBackendRetryMethods),
+		InitialInterval: GetStringValue(labels, TraefikBackendRetryInitialInterval, ""),
+		MaxInterval:     GetStringValue(labels, TraefikBackendRetryMaxInterval, ""),
+		PerTryTimeout:   GetStringValue(labels, TraefikBackendRetryPerTryTimeout, ""),
+		BudgetPercent:   GetIntValue(labels, TraefikBackendRetryBudgetPercent, 0),
+	}
+}
+
+// GetHedging Create hedging from labels
+func GetHedging(labels map[string]string) *types.Hedging {
+	if !HasPrefix(labels, TraefikBackendHedging) {
+		return nil
+	}
+
+	return &types.Hedging{
+		Delay:      GetStringValue(labels, TraefikBackendHedgingDelay, ""),
+		Percentile: GetIntValue(labels, TraefikBackendHedgingPercentile, 0),
+	}
+}
+
+// GetAdaptiveConcurrency Create adaptive concurrency from labels
+func GetAdaptiveConcurrency(labels map[string]string) *types.AdaptiveConcurrency {
+	if !HasPrefix(labels, TraefikBackendAdaptiveConcurrency) {
+		return nil
+	}
+
+	return &types.AdaptiveConcurrency{
+		Algorithm:        GetStringValue(labels, TraefikBackendAdaptiveConcurrencyAlgorithm, ""),
+		InitialLimit:     GetIntValue(labels, TraefikBackendAdaptiveConcurrencyInitialLimit, 0),
+		MinLimit:         GetIntValue(labels, TraefikBackendAdaptiveConcurrencyMinLimit, 0),
+		MaxLimit:         GetIntValue(labels, TraefikBackendAdaptiveConcurrencyMaxLimit, 0),
+		LatencyThreshold: GetStringValue(labels, TraefikBackendAdaptiveConcurrencyLatencyThreshold, ""),
+	}
+}
+
+// GetDNSDiscovery Create DNS discovery from labels
+func GetDNSDiscovery(labels map[string]string) *types.DNSDiscovery {
+	if !HasPrefix(labels, TraefikBackendDNSDiscovery) {
+		return nil
+	}
+
+	return &types.DNSDiscovery{
+		Name:     GetStringValue(labels, TraefikBackendDNSDiscoveryName, ""),
+		Port:     GetIntValue(labels, TraefikBackendDNSDiscoveryPort, 0),
+		Scheme:   GetStringValue(labels, TraefikBackendDNSDiscoveryScheme, ""),
+		Interval: GetStringValue(labels, TraefikBackendDNSDiscoveryInterval, ""),
 	}
 }
 
@@ -421,8 +614,10 @@ func GetLoadBalancer(labels map[string]string) *types.LoadBalancer {
 	method := GetStringValue(labels, TraefikBackendLoadBalancerMethod, DefaultBackendLoadBalancerMethod)
 
 	lb := &types.LoadBalancer{
-		Method: method,
-		Sticky: getSticky(labels),
+		Method:    method,
+		Sticky:    getSticky(labels),
+		HashKey:   GetStringValue(labels, TraefikBackendLoadBalancerHashKey, ""),
+		SlowStart: GetStringValue(labels, TraefikBackendLoadBalancerSlowStart, ""),
 	}
 
 	if GetBoolValue(labels, TraefikBackendLoadBalancerStickiness, false) {
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/containous/flaeg"
+	"github.com/pteich/traefik/tls"
 	"github.com/pteich/traefik/types"
 	"github.com/stretchr/testify/assert"
 )
@@ -75,6 +76,153 @@ func TestParseErrorPages(t *testing.T) {
 	}
 }
 
+func TestParseWeightedBackends(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		labels   map[string]string
+		expected []types.WeightedBackend
+	}{
+		{
+			desc: "2 weighted backends",
+			labels: map[string]string{
+				Prefix + BaseFrontendBackends + "foo." + SuffixWeightedBackendWeight: "90",
+				Prefix + BaseFrontendBackends + "bar." + SuffixWeightedBackendWeight: "10",
+			},
+			expected: []types.WeightedBackend{
+				{Name: "bar", Weight: 10},
+				{Name: "foo", Weight: 90},
+			},
+		},
+		{
+			desc: "invalid weight",
+			labels: map[string]string{
+				Prefix + BaseFrontendBackends + "foo." + SuffixWeightedBackendWeight: "courgette",
+				Prefix + BaseFrontendBackends + "bar." + SuffixWeightedBackendWeight: "10",
+			},
+			expected: []types.WeightedBackend{
+				{Name: "bar", Weight: 10},
+			},
+		},
+		{
+			desc: "invalid field",
+			labels: map[string]string{
+				Prefix + BaseFrontendBackends + "foo." + "courgette": "10",
+			},
+			expected: nil,
+		},
+		{
+			desc:     "no weighted backends labels",
+			labels:   map[string]string{},
+			expected: nil,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			backends := ParseWeightedBackends(test.labels, Prefix+BaseFrontendBackends, RegexpFrontendBackend)
+
+			assert.EqualValues(t, test.expected, backends)
+		})
+	}
+}
+
+func TestGetBackendsStickiness(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		labels   map[string]string
+		expected *types.Stickiness
+	}{
+		{
+			desc: "should return nil when no stickiness label",
+			labels: map[string]string{
+				TraefikFrontendBackendsStickinessCookieName: "foo",
+			},
+			expected: nil,
+		},
+		{
+			desc: "should return a struct when all labels are set",
+			labels: map[string]string{
+				TraefikFrontendBackendsStickiness:           "true",
+				TraefikFrontendBackendsStickinessCookieName: "foo",
+				TraefikFrontendBackendsStickinessSecure:     "true",
+				TraefikFrontendBackendsStickinessHTTPOnly:   "true",
+				TraefikFrontendBackendsStickinessSameSite:   "lax",
+			},
+			expected: &types.Stickiness{
+				CookieName: "foo",
+				Secure:     true,
+				HTTPOnly:   true,
+				SameSite:   "lax",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			actual := GetBackendsStickiness(test.labels)
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestGetMirror(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		labels   map[string]string
+		expected *types.Mirror
+	}{
+		{
+			desc: "should return nil when no mirror backend label",
+			labels: map[string]string{
+				TraefikFrontendMirrorPercent: "10",
+			},
+			expected: nil,
+		},
+		{
+			desc: "should return a struct with default values when only backend label",
+			labels: map[string]string{
+				TraefikFrontendMirrorBackend: "foo",
+			},
+			expected: &types.Mirror{
+				Backend:     "foo",
+				Percent:     intPtr(DefaultFrontendMirrorPercent),
+				MaxBodySize: DefaultFrontendMirrorMaxBodySize,
+			},
+		},
+		{
+			desc: "should return a struct when all labels are set",
+			labels: map[string]string{
+				TraefikFrontendMirrorBackend:     "foo",
+				TraefikFrontendMirrorPercent:     "10",
+				TraefikFrontendMirrorMaxBodySize: "2048",
+			},
+			expected: &types.Mirror{
+				Backend:     "foo",
+				Percent:     intPtr(10),
+				MaxBodySize: 2048,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			actual := GetMirror(test.labels)
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
 func TestParseRateSets(t *testing.T) {
 	testCases := []struct {
 		desc     string
@@ -285,6 +433,28 @@ func TestGetLoadBalancer(t *testing.T) {
 				Stickiness: nil,
 			},
 		},
+		{
+			desc: "should return the hash key when set",
+			labels: map[string]string{
+				TraefikBackendLoadBalancerMethod:  "hash",
+				TraefikBackendLoadBalancerHashKey: "header:X-User",
+			},
+			expected: &types.LoadBalancer{
+				Method:  "hash",
+				HashKey: "header:X-User",
+			},
+		},
+		{
+			desc: "should return the slow start window when set",
+			labels: map[string]string{
+				TraefikBackendLoadBalancerMethod:    "drr",
+				TraefikBackendLoadBalancerSlowStart: "1m",
+			},
+			expected: &types.LoadBalancer{
+				Method:    "drr",
+				SlowStart: "1m",
+			},
+		},
 	}
 
 	for _, test := range testCases {
@@ -339,6 +509,22 @@ func TestGetMaxConn(t *testing.T) {
 				Amount:        666,
 			},
 		},
+		{
+			desc: "should return a struct with the queue when queue labels are set",
+			labels: map[string]string{
+				TraefikBackendMaxConnAmount:         "666",
+				TraefikBackendMaxConnQueueLength:    "100",
+				TraefikBackendMaxConnQueueTimeout:   "2s",
+				TraefikBackendMaxConnPriorityHeader: "X-Priority",
+			},
+			expected: &types.MaxConn{
+				ExtractorFunc:  "request.host",
+				Amount:         666,
+				QueueLength:    100,
+				QueueTimeout:   "2s",
+				PriorityHeader: "X-Priority",
+			},
+		},
 	}
 
 	for _, test := range testCases {
@@ -393,6 +579,40 @@ func TestGetHealthCheck(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "should return a struct when health check Type label is set",
+			labels: map[string]string{
+				TraefikBackendHealthCheckType:    "grpc",
+				TraefikBackendHealthCheckService: "app",
+				TraefikBackendHealthCheckPort:    "8080",
+			},
+			expected: &types.HealthCheck{
+				Type:    "grpc",
+				Service: "app",
+				Port:    8080,
+			},
+		},
+		{
+			desc: "should return a struct with expectations, thresholds and timeout",
+			labels: map[string]string{
+				TraefikBackendHealthCheckPath:        "/health",
+				TraefikBackendHealthCheckMethod:      "HEAD",
+				TraefikBackendHealthCheckStatusCodes: "200,300-399",
+				TraefikBackendHealthCheckBodyRegex:   "^ok$",
+				TraefikBackendHealthCheckTimeout:     "2s",
+				TraefikBackendHealthCheckRise:        "3",
+				TraefikBackendHealthCheckFall:        "2",
+			},
+			expected: &types.HealthCheck{
+				Path:        "/health",
+				Method:      "HEAD",
+				StatusCodes: []string{"200", "300-399"},
+				BodyRegex:   "^ok$",
+				Timeout:     "2s",
+				Rise:        3,
+				Fall:        2,
+			},
+		},
 	}
 
 	for _, test := range testCases {
@@ -407,6 +627,287 @@ func TestGetHealthCheck(t *testing.T) {
 	}
 }
 
+func TestGetPassiveHealthCheck(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		labels   map[string]string
+		expected *types.PassiveHealthCheck
+	}{
+		{
+			desc:     "should return nil when no passive health check labels",
+			labels:   map[string]string{},
+			expected: nil,
+		},
+		{
+			desc: "should return a struct when passive health check labels are set",
+			labels: map[string]string{
+				TraefikBackendPassiveHealthCheckConsecutiveErrors:  "3",
+				TraefikBackendPassiveHealthCheckEjectionTime:       "10s",
+				TraefikBackendPassiveHealthCheckMaxEjectionPercent: "20",
+			},
+			expected: &types.PassiveHealthCheck{
+				ConsecutiveErrors:  3,
+				EjectionTime:       "10s",
+				MaxEjectionPercent: 20,
+			},
+		},
+		{
+			desc: "should return a struct with zero values when only some labels are set",
+			labels: map[string]string{
+				TraefikBackendPassiveHealthCheckEjectionTime: "1m",
+			},
+			expected: &types.PassiveHealthCheck{
+				EjectionTime: "1m",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			actual := GetPassiveHealthCheck(test.labels)
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestGetTransport(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		labels   map[string]string
+		expected *types.Transport
+	}{
+		{
+			desc:     "should return nil when no transport labels",
+			labels:   map[string]string{},
+			expected: nil,
+		},
+		{
+			desc: "should return a struct when transport labels are set",
+			labels: map[string]string{
+				TraefikBackendTransportDialTimeout:           "5s",
+				TraefikBackendTransportResponseHeaderTimeout: "1m",
+				TraefikBackendTransportIdleConnTimeout:       "10s",
+				TraefikBackendTransportMaxIdleConnsPerHost:   "10",
+				TraefikBackendTransportServerName:            "backend.example.com",
+				TraefikBackendTransportRootCAs:               "/etc/ca1.pem,/etc/ca2.pem",
+				TraefikBackendTransportClientCert:            "/etc/client.crt",
+				TraefikBackendTransportClientKey:             "/etc/client.key",
+				TraefikBackendTransportInsecureSkipVerify:    "true",
+				TraefikBackendTransportDisableHTTP2:          "true",
+			},
+			expected: &types.Transport{
+				DialTimeout:           "5s",
+				ResponseHeaderTimeout: "1m",
+				IdleConnTimeout:       "10s",
+				MaxIdleConnsPerHost:   10,
+				ServerName:            "backend.example.com",
+				RootCAs:               tls.FilesOrContents{"/etc/ca1.pem", "/etc/ca2.pem"},
+				ClientCert:            "/etc/client.crt",
+				ClientKey:             "/etc/client.key",
+				InsecureSkipVerify:    boolPtr(true),
+				DisableHTTP2:          true,
+			},
+		},
+		{
+			desc: "should return a struct with zero values when only some labels are set",
+			labels: map[string]string{
+				TraefikBackendTransportDisableHTTP2: "true",
+			},
+			expected: &types.Transport{
+				DisableHTTP2: true,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			actual := GetTransport(test.labels)
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestGetRetry(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		labels   map[string]string
+		expected *types.Retry
+	}{
+		{
+			desc:     "should return nil when no retry labels",
+			labels:   map[string]string{},
+			expected: nil,
+		},
+		{
+			desc: "should return a struct when retry labels are set",
+			labels: map[string]string{
+				TraefikBackendRetryAttempts:        "3",
+				TraefikBackendRetryStatusCodes:     "502-504,429",
+				TraefikBackendRetryMethods:         "GET,HEAD",
+				TraefikBackendRetryInitialInterval: "100ms",
+				TraefikBackendRetryMaxInterval:     "1s",
+				TraefikBackendRetryPerTryTimeout:   "2s",
+				TraefikBackendRetryBudgetPercent:   "20",
+			},
+			expected: &types.Retry{
+				Attempts:        3,
+				StatusCodes:     []string{"502-504", "429"},
+				Methods:         []string{"GET", "HEAD"},
+				InitialInterval: "100ms",
+				MaxInterval:     "1s",
+				PerTryTimeout:   "2s",
+				BudgetPercent:   20,
+			},
+		},
+		{
+			desc: "should return a struct with zero values when only some labels are set",
+			labels: map[string]string{
+				TraefikBackendRetryAttempts: "2",
+			},
+			expected: &types.Retry{
+				Attempts: 2,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			actual := GetRetry(test.labels)
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestGetHedging(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		labels   map[string]string
+		expected *types.Hedging
+	}{
+		{
+			desc:     "should return nil when no hedging labels",
+			labels:   map[string]string{},
+			expected: nil,
+		},
+		{
+			desc: "should return a struct when hedging labels are set",
+			labels: map[string]string{
+				TraefikBackendHedgingDelay:      "50ms",
+				TraefikBackendHedgingPercentile: "95",
+			},
+			expected: &types.Hedging{
+				Delay:      "50ms",
+				Percentile: 95,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			actual := GetHedging(test.labels)
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestGetAdaptiveConcurrency(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		labels   map[string]string
+		expected *types.AdaptiveConcurrency
+	}{
+		{
+			desc:     "should return nil when no adaptive concurrency labels",
+			labels:   map[string]string{},
+			expected: nil,
+		},
+		{
+			desc: "should return a struct when adaptive concurrency labels are set",
+			labels: map[string]string{
+				TraefikBackendAdaptiveConcurrencyAlgorithm:        "aimd",
+				TraefikBackendAdaptiveConcurrencyInitialLimit:     "50",
+				TraefikBackendAdaptiveConcurrencyMinLimit:         "10",
+				TraefikBackendAdaptiveConcurrencyMaxLimit:         "200",
+				TraefikBackendAdaptiveConcurrencyLatencyThreshold: "200ms",
+			},
+			expected: &types.AdaptiveConcurrency{
+				Algorithm:        "aimd",
+				InitialLimit:     50,
+				MinLimit:         10,
+				MaxLimit:         200,
+				LatencyThreshold: "200ms",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			actual := GetAdaptiveConcurrency(test.labels)
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestGetDNSDiscovery(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		labels   map[string]string
+		expected *types.DNSDiscovery
+	}{
+		{
+			desc:     "should return nil when no DNS discovery labels",
+			labels:   map[string]string{},
+			expected: nil,
+		},
+		{
+			desc: "should return a struct when DNS discovery labels are set",
+			labels: map[string]string{
+				TraefikBackendDNSDiscoveryName:     "app.example.com",
+				TraefikBackendDNSDiscoveryPort:     "8080",
+				TraefikBackendDNSDiscoveryScheme:   "https",
+				TraefikBackendDNSDiscoveryInterval: "10s",
+			},
+			expected: &types.DNSDiscovery{
+				Name:     "app.example.com",
+				Port:     8080,
+				Scheme:   "https",
+				Interval: "10s",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			actual := GetDNSDiscovery(test.labels)
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
 func TestGetBuffering(t *testing.T) {
 	testCases := []struct {
 		desc     string
@@ -1012,3 +1513,11 @@ func TestGetPassTLSClientCert(t *testing.T) {
 		})
 	}
 }
+
+func intPtr(value int) *int {
+	return &value
+}
+
+func boolPtr(value bool) *bool {
+	return &value
+}
@@ -32,31 +32,40 @@ func (p *Provider) buildConfigurationV2(applications *marathon.Applications) *ty
 		"getBackendName": p.getBackendName,
 
 		// Backend functions
-		"getPort":               getPort,
-		"getCircuitBreaker":     label.GetCircuitBreaker,
-		"getLoadBalancer":       label.GetLoadBalancer,
-		"getMaxConn":            label.GetMaxConn,
-		"getHealthCheck":        label.GetHealthCheck,
-		"getBuffering":          label.GetBuffering,
-		"getResponseForwarding": label.GetResponseForwarding,
-		"getServers":            p.getServers,
+		"getPort":                getPort,
+		"getCircuitBreaker":      label.GetCircuitBreaker,
+		"getLoadBalancer":        label.GetLoadBalancer,
+		"getMaxConn":             label.GetMaxConn,
+		"getHealthCheck":         label.GetHealthCheck,
+		"getPassiveHealthCheck":  label.GetPassiveHealthCheck,
+		"getBuffering":           label.GetBuffering,
+		"getTransport":           label.GetTransport,
+		"getRetry":               label.GetRetry,
+		"getHedging":             label.GetHedging,
+		"getAdaptiveConcurrency": label.GetAdaptiveConcurrency,
+		"getDNSDiscovery":        label.GetDNSDiscovery,
+		"getResponseForwarding":  label.GetResponseForwarding,
+		"getServers":             p.getServers,
 
 		// Frontend functions
-		"getSegmentNameSuffix": getSegmentNameSuffix,
-		"getFrontendRule":      p.getFrontendRule,
-		"getFrontendName":      p.getFrontendName,
-		"getPassHostHeader":    label.GetFuncBool(label.TraefikFrontendPassHostHeader, label.DefaultPassHostHeader),
-		"getPassTLSCert":       label.GetFuncBool(label.TraefikFrontendPassTLSCert, label.DefaultPassTLSCert),
-		"getPassTLSClientCert": label.GetTLSClientCert,
-		"getPriority":          label.GetFuncInt(label.TraefikFrontendPriority, label.DefaultFrontendPriority),
-		"getEntryPoints":       label.GetFuncSliceString(label.TraefikFrontendEntryPoints),
-		"getBasicAuth":         label.GetFuncSliceString(label.TraefikFrontendAuthBasic), // Deprecated
-		"getAuth":              label.GetAuth,
-		"getRedirect":          label.GetRedirect,
-		"getErrorPages":        label.GetErrorPages,
-		"getRateLimit":         label.GetRateLimit,
-		"getHeaders":           label.GetHeaders,
-		"getWhiteList":         label.GetWhiteList,
+		"getSegmentNameSuffix":  getSegmentNameSuffix,
+		"getFrontendRule":       p.getFrontendRule,
+		"getFrontendName":       p.getFrontendName,
+		"getPassHostHeader":     label.GetFuncBool(label.TraefikFrontendPassHostHeader, label.DefaultPassHostHeader),
+		"getPassTLSCert":        label.GetFuncBool(label.TraefikFrontendPassTLSCert, label.DefaultPassTLSCert),
+		"getPassTLSClientCert":  label.GetTLSClientCert,
+		"getPriority":           label.GetFuncInt(label.TraefikFrontendPriority, label.DefaultFrontendPriority),
+		"getEntryPoints":        label.GetFuncSliceString(label.TraefikFrontendEntryPoints),
+		"getBasicAuth":          label.GetFuncSliceString(label.TraefikFrontendAuthBasic), // Deprecated
+		"getAuth":               label.GetAuth,
+		"getRedirect":           label.GetRedirect,
+		"getErrorPages":         label.GetErrorPages,
+		"getWeightedBackends":   label.GetWeightedBackends,
+		"getBackendsStickiness": label.GetBackendsStickiness,
+		"getMirror":             label.GetMirror,
+		"getRateLimit":          label.GetRateLimit,
+		"getHeaders":            label.GetHeaders,
+		"getWhiteList":          label.GetWhiteList,
 	}
 
 	apps := make(map[string]*appData)
@@ -347,8 +356,9 @@ func (p *Provider) getServer(app appData, task marathon.Task) (string, *types.Se
 	serverName := provider.Normalize("server-" + app.ID + "-" + task.ID + getSegmentNameSuffix(app.SegmentName))
 
 	return serverName, &types.Server{
-		URL:    fmt.Sprintf("%s://%s", protocol, net.JoinHostPort(host, port)),
-		Weight: label.GetIntValue(app.SegmentLabels, label.TraefikWeight, label.DefaultWeight),
+		URL:      fmt.Sprintf("%s://%s", protocol, net.JoinHostPort(host, port)),
+		Weight:   label.GetIntValue(app.SegmentLabels, label.TraefikWeight, label.DefaultWeight),
+		Priority: label.GetIntValue(app.SegmentLabels, label.TraefikServerPriority, 0),
 	}, nil
 }
 
@@ -29,33 +29,42 @@ func (p *Provider) buildConfigurationV2(tasks []state.Task) *types.Configuration
 		"getID":               getID,
 
 		// Backend functions
-		"getBackendName":        getBackendName,
-		"getCircuitBreaker":     label.GetCircuitBreaker,
-		"getLoadBalancer":       label.GetLoadBalancer,
-		"getMaxConn":            label.GetMaxConn,
-		"getHealthCheck":        label.GetHealthCheck,
-		"getBuffering":          label.GetBuffering,
-		"getResponseForwarding": label.GetResponseForwarding,
-		"getServers":            p.getServers,
-		"getHost":               p.getHost,
-		"getServerPort":         p.getServerPort,
+		"getBackendName":         getBackendName,
+		"getCircuitBreaker":      label.GetCircuitBreaker,
+		"getLoadBalancer":        label.GetLoadBalancer,
+		"getMaxConn":             label.GetMaxConn,
+		"getHealthCheck":         label.GetHealthCheck,
+		"getPassiveHealthCheck":  label.GetPassiveHealthCheck,
+		"getBuffering":           label.GetBuffering,
+		"getTransport":           label.GetTransport,
+		"getRetry":               label.GetRetry,
+		"getHedging":             label.GetHedging,
+		"getAdaptiveConcurrency": label.GetAdaptiveConcurrency,
+		"getDNSDiscovery":        label.GetDNSDiscovery,
+		"getResponseForwarding":  label.GetResponseForwarding,
+		"getServers":             p.getServers,
+		"getHost":                p.getHost,
+		"getServerPort":          p.getServerPort,
 
 		// Frontend functions
-		"getSegmentNameSuffix": getSegmentNameSuffix,
-		"getFrontEndName":      getFrontendName,
-		"getEntryPoints":       label.GetFuncSliceString(label.TraefikFrontendEntryPoints),
-		"getBasicAuth":         label.GetFuncSliceString(label.TraefikFrontendAuthBasic), // Deprecated
-		"getAuth":              label.GetAuth,
-		"getPriority":          label.GetFuncInt(label.TraefikFrontendPriority, label.DefaultFrontendPriority),
-		"getPassHostHeader":    label.GetFuncBool(label.TraefikFrontendPassHostHeader, label.DefaultPassHostHeader),
-		"getPassTLSCert":       label.GetFuncBool(label.TraefikFrontendPassTLSCert, label.DefaultPassTLSCert),
-		"getPassTLSClientCert": label.GetTLSClientCert,
-		"getFrontendRule":      p.getFrontendRule,
-		"getRedirect":          label.GetRedirect,
-		"getErrorPages":        label.GetErrorPages,
-		"getRateLimit":         label.GetRateLimit,
-		"getHeaders":           label.GetHeaders,
-		"getWhiteList":         label.GetWhiteList,
+		"getSegmentNameSuffix":  getSegmentNameSuffix,
+		"getFrontEndName":       getFrontendName,
+		"getEntryPoints":        label.GetFuncSliceString(label.TraefikFrontendEntryPoints),
+		"getBasicAuth":          label.GetFuncSliceString(label.TraefikFrontendAuthBasic), // Deprecated
+		"getAuth":               label.GetAuth,
+		"getPriority":           label.GetFuncInt(label.TraefikFrontendPriority, label.DefaultFrontendPriority),
+		"getPassHostHeader":     label.GetFuncBool(label.TraefikFrontendPassHostHeader, label.DefaultPassHostHeader),
+		"getPassTLSCert":        label.GetFuncBool(label.TraefikFrontendPassTLSCert, label.DefaultPassTLSCert),
+		"getPassTLSClientCert":  label.GetTLSClientCert,
+		"getFrontendRule":       p.getFrontendRule,
+		"getRedirect":           label.GetRedirect,
+		"getErrorPages":         label.GetErrorPages,
+		"getWeightedBackends":   label.GetWeightedBackends,
+		"getBackendsStickiness": label.GetBackendsStickiness,
+		"getMirror":             label.GetMirror,
+		"getRateLimit":          label.GetRateLimit,
+		"getHeaders":            label.GetHeaders,
+		"getWhiteList":          label.GetWhiteList,
 	}
 
 	appsTasks := p.filterTasks(tasks)
@@ -244,8 +253,9 @@ func (p *Provider) getServers(tasks []taskData) map[string]types.Server {
 
 		serverName := "server-" + getID(task)
 		servers[serverName] = types.Server{
-			URL:    fmt.Sprintf("%s://%s", protocol, net.JoinHostPort(host, port)),
-			Weight: getIntValue(task.TraefikLabels, label.TraefikWeight, label.DefaultWeight, math.MaxInt32),
+			URL:      fmt.Sprintf("%s://%s", protocol, net.JoinHostPort(host, port)),
+			Weight:   getIntValue(task.TraefikLabels, label.TraefikWeight, label.DefaultWeight, math.MaxInt32),
+			Priority: label.GetIntValue(task.TraefikLabels, label.TraefikServerPriority, 0),
 		}
 	}
 
@@ -20,29 +20,38 @@ func (p *Provider) buildConfigurationV2(services []rancherData) *types.Configura
 		"getDomain":     label.GetFuncString(label.TraefikDomain, p.Domain),
 
 		// Backend functions
-		"getCircuitBreaker":     label.GetCircuitBreaker,
-		"getLoadBalancer":       label.GetLoadBalancer,
-		"getMaxConn":            label.GetMaxConn,
-		"getHealthCheck":        label.GetHealthCheck,
-		"getBuffering":          label.GetBuffering,
-		"getResponseForwarding": label.GetResponseForwarding,
-		"getServers":            getServers,
+		"getCircuitBreaker":      label.GetCircuitBreaker,
+		"getLoadBalancer":        label.GetLoadBalancer,
+		"getMaxConn":             label.GetMaxConn,
+		"getHealthCheck":         label.GetHealthCheck,
+		"getPassiveHealthCheck":  label.GetPassiveHealthCheck,
+		"getBuffering":           label.GetBuffering,
+		"getTransport":           label.GetTransport,
+		"getRetry":               label.GetRetry,
+		"getHedging":             label.GetHedging,
+		"getAdaptiveConcurrency": label.GetAdaptiveConcurrency,
+		"getDNSDiscovery":        label.GetDNSDiscovery,
+		"getResponseForwarding":  label.GetResponseForwarding,
+		"getServers":             getServers,
 
 		// Frontend functions
-		"getBackendName":       getBackendName,
-		"getFrontendRule":      p.getFrontendRule,
-		"getPriority":          label.GetFuncInt(label.TraefikFrontendPriority, label.DefaultFrontendPriority),
-		"getPassHostHeader":    label.GetFuncBool(label.TraefikFrontendPassHostHeader, label.DefaultPassHostHeader),
-		"getPassTLSCert":       label.GetFuncBool(label.TraefikFrontendPassTLSCert, label.DefaultPassTLSCert),
-		"getPassTLSClientCert": label.GetTLSClientCert,
-		"getEntryPoints":       label.GetFuncSliceString(label.TraefikFrontendEntryPoints),
-		"getBasicAuth":         label.GetFuncSliceString(label.TraefikFrontendAuthBasic), // Deprecated
-		"getAuth":              label.GetAuth,
-		"getErrorPages":        label.GetErrorPages,
-		"getRateLimit":         label.GetRateLimit,
-		"getRedirect":          label.GetRedirect,
-		"getHeaders":           label.GetHeaders,
-		"getWhiteList":         label.GetWhiteList,
+		"getBackendName":        getBackendName,
+		"getFrontendRule":       p.getFrontendRule,
+		"getPriority":           label.GetFuncInt(label.TraefikFrontendPriority, label.DefaultFrontendPriority),
+		"getPassHostHeader":     label.GetFuncBool(label.TraefikFrontendPassHostHeader, label.DefaultPassHostHeader),
+		"getPassTLSCert":        label.GetFuncBool(label.TraefikFrontendPassTLSCert, label.DefaultPassTLSCert),
+		"getPassTLSClientCert":  label.GetTLSClientCert,
+		"getEntryPoints":        label.GetFuncSliceString(label.TraefikFrontendEntryPoints),
+		"getBasicAuth":          label.GetFuncSliceString(label.TraefikFrontendAuthBasic), // Deprecated
+		"getAuth":               label.GetAuth,
+		"getErrorPages":         label.GetErrorPages,
+		"getWeightedBackends":   label.GetWeightedBackends,
+		"getBackendsStickiness": label.GetBackendsStickiness,
+		"getMirror":             label.GetMirror,
+		"getRateLimit":          label.GetRateLimit,
+		"getRedirect":           label.GetRedirect,
+		"getHeaders":            label.GetHeaders,
+		"getWhiteList":          label.GetWhiteList,
 	}
 
 	// filter services
@@ -189,8 +198,9 @@ func getServers(service rancherData) map[string]types.Server {
 
 		serverName := "server-" + strconv.Itoa(index)
 		servers[serverName] = types.Server{
-			URL:    fmt.Sprintf("%s://%s", protocol, net.JoinHostPort(ip, port)),
-			Weight: weight,
+			URL:      fmt.Sprintf("%s://%s", protocol, net.JoinHostPort(ip, port)),
+			Weight:   weight,
+			Priority: label.GetIntValue(service.SegmentLabels, label.TraefikServerPriority, 0),
 		}
 	}
 
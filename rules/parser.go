@@ -0,0 +1,264 @@
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type nodeKind int
+
+const (
+	nodeFunction nodeKind = iota
+	nodeAnd
+	nodeOr
+	nodeNot
+)
+
+// ruleTree is the parsed form of a rule expression.
+type ruleTree struct {
+	kind     nodeKind
+	rule     string
+	name     string
+	args     []string
+	children []*ruleTree
+}
+
+// ruleParser parses rule expressions using the following grammar:
+//
+//	sequence := or (';' or)*
+//	or       := and ('||' and)*
+//	and      := unary ('&&' unary)*
+//	unary    := '!' unary | '(' sequence ')' | function
+//	function := name ':' arguments
+//
+// Rules separated by ';' are and-ed, as in the legacy syntax.
+type ruleParser struct {
+	input string
+	pos   int
+}
+
+func parseExpression(expression string) (*ruleTree, error) {
+	p := &ruleParser{input: expression}
+
+	tree, err := p.parseSequence()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpaces()
+	if !p.eof() {
+		return nil, fmt.Errorf("error parsing rule: unexpected '%s'", p.input[p.pos:])
+	}
+
+	return tree, nil
+}
+
+func (p *ruleParser) parseSequence() (*ruleTree, error) {
+	var children []*ruleTree
+
+	for {
+		p.skipSpaces()
+		if p.consume(";") {
+			continue
+		}
+		if p.eof() || p.hasPrefix(")") {
+			break
+		}
+
+		child, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, child)
+
+		p.skipSpaces()
+		if !p.consume(";") {
+			break
+		}
+	}
+
+	if len(children) == 0 {
+		return nil, errors.New("empty rule")
+	}
+
+	return newRuleNode(nodeAnd, children), nil
+}
+
+func (p *ruleParser) parseOr() (*ruleTree, error) {
+	var children []*ruleTree
+
+	for {
+		child, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, child)
+
+		p.skipSpaces()
+		if !p.consume("||") {
+			break
+		}
+	}
+
+	return newRuleNode(nodeOr, children), nil
+}
+
+func (p *ruleParser) parseAnd() (*ruleTree, error) {
+	var children []*ruleTree
+
+	for {
+		child, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, child)
+
+		p.skipSpaces()
+		if !p.consume("&&") {
+			break
+		}
+	}
+
+	return newRuleNode(nodeAnd, children), nil
+}
+
+func (p *ruleParser) parseUnary() (*ruleTree, error) {
+	p.skipSpaces()
+
+	switch {
+	case p.consume("!"):
+		child, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &ruleTree{kind: nodeNot, children: []*ruleTree{child}}, nil
+
+	case p.consume("("):
+		start := p.pos
+		child, err := p.parseSequence()
+		if err != nil {
+			return nil, err
+		}
+
+		p.skipSpaces()
+		if !p.consume(")") {
+			return nil, fmt.Errorf("error parsing rule: missing closing parenthesis for '(%s'", p.input[start:p.pos])
+		}
+		return child, nil
+
+	default:
+		return p.parseFunction()
+	}
+}
+
+func (p *ruleParser) parseFunction() (*ruleTree, error) {
+	start := p.pos
+	for !p.eof() && (unicode.IsLetter(rune(p.input[p.pos])) || unicode.IsDigit(rune(p.input[p.pos]))) {
+		p.pos++
+	}
+	name := p.input[start:p.pos]
+
+	p.skipSpaces()
+	if len(name) == 0 || !p.consume(":") {
+		return nil, fmt.Errorf("error parsing rule: '%s'", p.remaining(start))
+	}
+
+	rawArgs := p.readArguments()
+	rule := p.input[start:p.pos]
+
+	parsedArgs := strings.FieldsFunc(rawArgs, func(c rune) bool {
+		return c == ','
+	})
+	if len(parsedArgs) == 0 {
+		return nil, fmt.Errorf("error parsing args from rule: '%s'", rule)
+	}
+
+	for i := range parsedArgs {
+		parsedArgs[i] = strings.TrimSpace(parsedArgs[i])
+	}
+
+	return &ruleTree{
+		kind: nodeFunction,
+		rule: strings.TrimSpace(rule),
+		name: name,
+		args: parsedArgs,
+	}, nil
+}
+
+// readArguments reads the arguments of a function up to the next operator.
+// Parentheses and braces used inside arguments (e.g. in regular expressions) must be balanced.
+func (p *ruleParser) readArguments() string {
+	start := p.pos
+	parens, braces := 0, 0
+
+	for ; !p.eof(); p.pos++ {
+		switch p.input[p.pos] {
+		case '{':
+			braces++
+			continue
+		case '}':
+			if braces > 0 {
+				braces--
+			}
+			continue
+		}
+
+		if braces > 0 {
+			continue
+		}
+
+		switch {
+		case p.input[p.pos] == '(':
+			parens++
+		case p.input[p.pos] == ')':
+			if parens == 0 {
+				return p.input[start:p.pos]
+			}
+			parens--
+		case parens > 0:
+		case p.input[p.pos] == ';', p.hasPrefix("&&"), p.hasPrefix("||"):
+			return p.input[start:p.pos]
+		}
+	}
+
+	return p.input[start:p.pos]
+}
+
+func (p *ruleParser) remaining(start int) string {
+	end := strings.IndexAny(p.input[start:], ";&|()")
+	if end < 0 {
+		return p.input[start:]
+	}
+	return p.input[start : start+end]
+}
+
+func (p *ruleParser) skipSpaces() {
+	for !p.eof() && unicode.IsSpace(rune(p.input[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *ruleParser) hasPrefix(s string) bool {
+	return strings.HasPrefix(p.input[p.pos:], s)
+}
+
+func (p *ruleParser) consume(s string) bool {
+	if p.hasPrefix(s) {
+		p.pos += len(s)
+		return true
+	}
+	return false
+}
+
+func (p *ruleParser) eof() bool {
+	return p.pos >= len(p.input)
+}
+
+func newRuleNode(kind nodeKind, children []*ruleTree) *ruleTree {
+	if len(children) == 1 {
+		return children[0]
+	}
+	return &ruleTree{kind: kind, children: children}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"reflect"
+	"regexp"
 	"sort"
 	"strings"
 
@@ -13,6 +14,7 @@ import (
 	"github.com/pteich/traefik/log"
 	"github.com/pteich/traefik/middlewares"
 	"github.com/pteich/traefik/types"
+	"github.com/pteich/traefik/whitelist"
 )
 
 // Rules holds rule parsing and configuration
@@ -20,6 +22,9 @@ type Rules struct {
 	Route        *types.ServerRoute
 	err          error
 	HostResolver *hostresolver.Resolver
+	// TrustedForwarders holds the addresses whose X-Forwarded-For header is used by the ClientIP rule.
+	// If nil, only the remote address is used.
+	TrustedForwarders *whitelist.IP
 }
 
 func (r *Rules) host(hosts ...string) *mux.Route {
@@ -185,8 +190,8 @@ func (r *Rules) query(query ...string) *mux.Route {
 	return r.Route.Route.Queries(queries...)
 }
 
-func (r *Rules) parseRules(expression string, onRule func(functionName string, function interface{}, arguments []string) error) error {
-	functions := map[string]interface{}{
+func (r *Rules) functions() map[string]interface{} {
+	return map[string]interface{}{
 		"Host":                 r.host,
 		"HostRegexp":           r.hostRegexp,
 		"Path":                 r.path,
@@ -202,107 +207,328 @@ func (r *Rules) parseRules(expression string, onRule func(functionName string, f
 		"ReplacePath":          r.replacePath,
 		"ReplacePathRegex":     r.replacePathRegex,
 		"Query":                r.query,
+		"QueryRegexp":          r.queryRegexp,
+		"Cookie":               r.cookie,
+		"CookieRegexp":         r.cookieRegexp,
+		"ClientIP":             r.clientIP,
 	}
+}
 
-	if len(expression) == 0 {
-		return errors.New("empty rule")
+// modifiers holds the functions which change the request in addition to (or instead of) matching it.
+// They can't be used under an OR or a NOT, as the modification would apply whichever branch matched.
+var modifiers = map[string]bool{
+	"PathStrip":            true,
+	"PathStripRegex":       true,
+	"PathPrefixStrip":      true,
+	"PathPrefixStripRegex": true,
+	"AddPrefix":            true,
+	"ReplacePath":          true,
+	"ReplacePathRegex":     true,
+}
+
+func (r *Rules) queryRegexp(pairs ...string) *mux.Route {
+	patterns, err := compilePairs("QueryRegexp", pairs)
+	if err != nil {
+		r.err = err
+		return r.Route.Route
 	}
 
-	f := func(c rune) bool {
-		return c == ':'
+	return r.Route.Route.MatcherFunc(func(req *http.Request, _ *mux.RouteMatch) bool {
+		query := req.URL.Query()
+		for key, pattern := range patterns {
+			if !matchAny(query[key], pattern.MatchString) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+func (r *Rules) cookie(pairs ...string) *mux.Route {
+	if len(pairs)%2 != 0 {
+		r.err = fmt.Errorf("number of parameters of Cookie must be multiple of 2, got %v", pairs)
+		return r.Route.Route
 	}
 
-	// Allow multiple rules separated by ;
-	splitRule := func(c rune) bool {
-		return c == ';'
+	return r.Route.Route.MatcherFunc(func(req *http.Request, _ *mux.RouteMatch) bool {
+		for i := 0; i < len(pairs); i += 2 {
+			value := pairs[i+1]
+			if !matchAny(cookieValues(req, pairs[i]), func(v string) bool { return v == value }) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+func (r *Rules) cookieRegexp(pairs ...string) *mux.Route {
+	patterns, err := compilePairs("CookieRegexp", pairs)
+	if err != nil {
+		r.err = err
+		return r.Route.Route
 	}
 
-	parsedRules := strings.FieldsFunc(expression, splitRule)
+	return r.Route.Route.MatcherFunc(func(req *http.Request, _ *mux.RouteMatch) bool {
+		for name, pattern := range patterns {
+			if !matchAny(cookieValues(req, name), pattern.MatchString) {
+				return false
+			}
+		}
+		return true
+	})
+}
 
-	for _, rule := range parsedRules {
-		// get function
-		parsedFunctions := strings.FieldsFunc(rule, f)
-		if len(parsedFunctions) == 0 {
-			return fmt.Errorf("error parsing rule: '%s'", rule)
+// compilePairs compiles the patterns of a sequence of name/pattern pairs.
+func compilePairs(functionName string, pairs []string) (map[string]*regexp.Regexp, error) {
+	if len(pairs)%2 != 0 {
+		return nil, fmt.Errorf("number of parameters of %s must be multiple of 2, got %v", functionName, pairs)
+	}
+
+	patterns := make(map[string]*regexp.Regexp, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		pattern, err := regexp.Compile(pairs[i+1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s pattern for %s: %v", functionName, pairs[i], err)
 		}
+		patterns[pairs[i]] = pattern
+	}
 
-		functionName := strings.TrimSpace(parsedFunctions[0])
-		parsedFunction, ok := functions[functionName]
-		if !ok {
-			return fmt.Errorf("error parsing rule: '%s'. Unknown function: '%s'", rule, parsedFunctions[0])
+	return patterns, nil
+}
+
+func cookieValues(req *http.Request, name string) []string {
+	var values []string
+	for _, c := range req.Cookies() {
+		if c.Name == name {
+			values = append(values, c.Value)
 		}
-		parsedFunctions = append(parsedFunctions[:0], parsedFunctions[1:]...)
+	}
+	return values
+}
 
-		// get function
-		fargs := func(c rune) bool {
-			return c == ','
+func matchAny(values []string, match func(string) bool) bool {
+	for _, value := range values {
+		if match(value) {
+			return true
 		}
-		parsedArgs := strings.FieldsFunc(strings.Join(parsedFunctions, ":"), fargs)
-		if len(parsedArgs) == 0 {
-			return fmt.Errorf("error parsing args from rule: '%s'", rule)
+	}
+	return false
+}
+
+func (r *Rules) clientIP(sourceRanges ...string) *mux.Route {
+	remoteChecker, err := whitelist.NewIP(sourceRanges, false, false)
+	if err != nil {
+		r.err = fmt.Errorf("invalid ClientIP source range %s: %v", sourceRanges, err)
+		return r.Route.Route
+	}
+
+	forwardedChecker, err := whitelist.NewIP(sourceRanges, false, true)
+	if err != nil {
+		r.err = fmt.Errorf("invalid ClientIP source range %s: %v", sourceRanges, err)
+		return r.Route.Route
+	}
+
+	trustedForwarders := r.TrustedForwarders
+
+	return r.Route.Route.MatcherFunc(func(req *http.Request, _ *mux.RouteMatch) bool {
+		if trustedForwarders != nil && trustedForwarders.IsAuthorized(req) == nil {
+			return forwardedChecker.IsAuthorized(req) == nil
 		}
+		return remoteChecker.IsAuthorized(req) == nil
+	})
+}
 
-		for i := range parsedArgs {
-			parsedArgs[i] = strings.TrimSpace(parsedArgs[i])
+func (r *Rules) parseRules(expression string) (*ruleTree, error) {
+	if len(expression) == 0 {
+		return nil, errors.New("empty rule")
+	}
+
+	tree, err := parseExpression(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	functions := r.functions()
+
+	var check func(node *ruleTree) error
+	check = func(node *ruleTree) error {
+		if node.kind == nodeFunction {
+			if _, ok := functions[node.name]; !ok {
+				return fmt.Errorf("error parsing rule: '%s'. Unknown function: '%s'", node.rule, node.name)
+			}
+			return nil
 		}
 
-		err := onRule(functionName, parsedFunction, parsedArgs)
-		if err != nil {
-			return fmt.Errorf("parsing error on rule: %v", err)
+		for _, child := range node.children {
+			if err := check(child); err != nil {
+				return err
+			}
 		}
+		return nil
 	}
-	return nil
+
+	if err := check(tree); err != nil {
+		return nil, err
+	}
+
+	return tree, nil
+}
+
+// compile adds the matchers described by the tree to the given route.
+// AND-ed functions are applied to the same route, OR-ed branches are added as routes of a subrouter,
+// and negated branches are evaluated on a detached route.
+func (r *Rules) compile(node *ruleTree, route *mux.Route, nested bool) error {
+	switch node.kind {
+	case nodeAnd:
+		for _, child := range node.children {
+			if err := r.compile(child, route, nested); err != nil {
+				return err
+			}
+		}
+
+	case nodeOr:
+		router := route.Subrouter()
+		for _, child := range node.children {
+			if err := r.compile(child, router.NewRoute(), true); err != nil {
+				return err
+			}
+		}
+
+	case nodeNot:
+		negated := mux.NewRouter().NewRoute()
+		if err := r.compile(node.children[0], negated, true); err != nil {
+			return err
+		}
+
+		route.MatcherFunc(func(req *http.Request, _ *mux.RouteMatch) bool {
+			return !negated.Match(req, &mux.RouteMatch{})
+		})
+
+	case nodeFunction:
+		if nested && modifiers[node.name] {
+			return fmt.Errorf("function '%s' modifies the request and can't be used with '||' or '!'", node.name)
+		}
+
+		return r.apply(node, route)
+	}
+
+	return route.GetError()
+}
+
+func (r *Rules) apply(node *ruleTree, route *mux.Route) error {
+	// Rule functions work on the ServerRoute's route, so point it to the route being built.
+	current := r.Route.Route
+	r.Route.Route = route
+	defer func() { r.Route.Route = current }()
+
+	inputs := make([]reflect.Value, len(node.args))
+	for i := range node.args {
+		inputs[i] = reflect.ValueOf(node.args[i])
+	}
+
+	method := reflect.ValueOf(r.functions()[node.name])
+	if !method.IsValid() {
+		return fmt.Errorf("method not found: '%s'", node.name)
+	}
+
+	resultRoute := method.Call(inputs)[0].Interface().(*mux.Route)
+	if r.err != nil {
+		return r.err
+	}
+	if resultRoute == nil {
+		return fmt.Errorf("invalid expression: %s", node.rule)
+	}
+
+	return resultRoute.GetError()
 }
 
 // Parse parses rules expressions
 func (r *Rules) Parse(expression string) (*mux.Route, error) {
-	var resultRoute *mux.Route
+	tree, err := r.parseRules(expression)
+	if err == nil {
+		err = r.compile(tree, r.Route.Route, false)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error parsing rule: %v", err)
+	}
 
-	err := r.parseRules(expression, func(functionName string, function interface{}, arguments []string) error {
-		inputs := make([]reflect.Value, len(arguments))
-		for i := range arguments {
-			inputs[i] = reflect.ValueOf(arguments[i])
-		}
-		method := reflect.ValueOf(function)
-		if method.IsValid() {
-			resultRoute = method.Call(inputs)[0].Interface().(*mux.Route)
-			if r.err != nil {
-				return r.err
+	return r.Route.Route, nil
+}
+
+// ParseHostSNI parses a TCP rule expression and returns the server names it matches.
+// Only the HostSNI matcher is supported, the server names can be combined with ',' or '||'.
+func ParseHostSNI(expression string) ([]string, error) {
+	tree, err := parseExpression(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	var serverNames []string
+
+	var walk func(node *ruleTree) error
+	walk = func(node *ruleTree) error {
+		switch node.kind {
+		case nodeFunction:
+			if node.name != "HostSNI" {
+				return fmt.Errorf("error parsing rule: '%s'. Unknown TCP function: '%s'", node.rule, node.name)
 			}
-			if resultRoute == nil {
-				return fmt.Errorf("invalid expression: %s", expression)
+			for _, arg := range node.args {
+				if len(arg) > 0 {
+					serverNames = append(serverNames, strings.ToLower(arg))
+				}
 			}
-			if resultRoute.GetError() != nil {
-				return resultRoute.GetError()
+		case nodeOr:
+			for _, child := range node.children {
+				if err := walk(child); err != nil {
+					return err
+				}
 			}
-		} else {
-			return fmt.Errorf("method not found: '%s'", functionName)
+		default:
+			return fmt.Errorf("error parsing rule: '%s'. Only '||' can combine TCP matchers", expression)
 		}
 		return nil
-	})
-	if err != nil {
-		return nil, fmt.Errorf("error parsing rule: %v", err)
 	}
 
-	return resultRoute, nil
+	if err := walk(tree); err != nil {
+		return nil, err
+	}
+
+	if len(serverNames) == 0 {
+		return nil, fmt.Errorf("unable to parse the server names from %q", expression)
+	}
+
+	return serverNames, nil
 }
 
-// ParseDomains parses rules expressions and returns domains
+// ParseDomains parses rules expressions and returns domains.
+// Hosts appearing in a negated part of the expression are ignored.
 func (r *Rules) ParseDomains(expression string) ([]string, error) {
 	var domains []string
 	isHostRule := false
 
-	err := r.parseRules(expression, func(functionName string, function interface{}, arguments []string) error {
-		if functionName == "Host" {
-			isHostRule = true
-			domains = append(domains, arguments...)
-		}
-		return nil
-	})
+	tree, err := r.parseRules(expression)
 	if err != nil {
 		return nil, fmt.Errorf("error parsing domains: %v", err)
 	}
 
+	var walk func(node *ruleTree)
+	walk = func(node *ruleTree) {
+		switch node.kind {
+		case nodeNot:
+			return
+		case nodeFunction:
+			if node.name == "Host" {
+				isHostRule = true
+				domains = append(domains, node.args...)
+			}
+		default:
+			for _, child := range node.children {
+				walk(child)
+			}
+		}
+	}
+	walk(tree)
+
 	var cleanDomains []string
 	for _, domain := range domains {
 		canonicalDomain := strings.ToLower(domain)
@@ -9,6 +9,7 @@ import (
 	"github.com/pteich/traefik/middlewares"
 	"github.com/pteich/traefik/testhelpers"
 	"github.com/pteich/traefik/types"
+	"github.com/pteich/traefik/whitelist"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -92,6 +93,12 @@ func TestParseDomains(t *testing.T) {
 			domain:        []string{"foo.bar"},
 			errorExpected: false,
 		},
+		{
+			description:   "Host rules with operators",
+			expression:    "(Host:foo.bar || Host:test.bar) && !Host:admin.bar",
+			domain:        []string{"foo.bar", "test.bar"},
+			errorExpected: false,
+		},
 		{
 			description:   "Host rule with no domain",
 			expression:    "Host: ;Path:/test",
@@ -117,6 +124,200 @@ func TestParseDomains(t *testing.T) {
 	}
 }
 
+func TestParseHostSNI(t *testing.T) {
+	tests := []struct {
+		expression    string
+		serverNames   []string
+		errorExpected bool
+	}{
+		{
+			expression:  "HostSNI:foo.bar",
+			serverNames: []string{"foo.bar"},
+		},
+		{
+			expression:  "HostSNI:Foo.Bar,test.bar",
+			serverNames: []string{"foo.bar", "test.bar"},
+		},
+		{
+			expression:  "HostSNI:foo.bar || HostSNI:*",
+			serverNames: []string{"foo.bar", "*"},
+		},
+		{
+			expression:    "HostSNI:foo.bar && HostSNI:test.bar",
+			errorExpected: true,
+		},
+		{
+			expression:    "!HostSNI:foo.bar",
+			errorExpected: true,
+		},
+		{
+			expression:    "Host:foo.bar",
+			errorExpected: true,
+		},
+		{
+			expression:    "HostSNI:",
+			errorExpected: true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.expression, func(t *testing.T) {
+			t.Parallel()
+
+			serverNames, err := ParseHostSNI(test.expression)
+
+			if test.errorExpected {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.serverNames, serverNames)
+		})
+	}
+}
+
+func TestParseBooleanRules(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		expression string
+		urls       map[string]bool
+	}{
+		{
+			desc:       "or",
+			expression: "Host:foo.bar || Host:bar.foo",
+			urls: map[string]bool{
+				"http://foo.bar/":    true,
+				"http://bar.foo/":    true,
+				"http://foo.foo/":    false,
+				"http://bar.foo/baz": true,
+			},
+		},
+		{
+			desc:       "and has precedence over or",
+			expression: "Host:foo.bar || Host:bar.foo && !PathPrefix:/admin",
+			urls: map[string]bool{
+				"http://foo.bar/":      true,
+				"http://foo.bar/admin": true,
+				"http://bar.foo/":      true,
+				"http://bar.foo/admin": false,
+			},
+		},
+		{
+			desc:       "parentheses",
+			expression: "(Host:foo.bar || Host:bar.foo) && !PathPrefix:/admin",
+			urls: map[string]bool{
+				"http://foo.bar/":      true,
+				"http://foo.bar/admin": false,
+				"http://bar.foo/":      true,
+				"http://bar.foo/admin": false,
+				"http://foo.foo/":      false,
+			},
+		},
+		{
+			desc:       "legacy separator with operators",
+			expression: "Host:foo.bar,bar.foo;Path:/a || Path:/b",
+			urls: map[string]bool{
+				"http://foo.bar/a": true,
+				"http://bar.foo/b": true,
+				"http://foo.bar/c": false,
+				"http://foo.foo/a": false,
+			},
+		},
+		{
+			desc:       "double negation",
+			expression: "!!Host:foo.bar",
+			urls: map[string]bool{
+				"http://foo.bar/": true,
+				"http://bar.foo/": false,
+			},
+		},
+		{
+			desc:       "regexp with parentheses",
+			expression: "(HostRegexp:{subdomain:(foo|bar)}.baz.com || Host:qux.com) && Method:GET",
+			urls: map[string]bool{
+				"http://foo.baz.com/": true,
+				"http://bar.baz.com/": true,
+				"http://qux.baz.com/": false,
+				"http://qux.com/":     true,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			reqHostMid := &middlewares.RequestHost{}
+			rls := &Rules{
+				Route: &types.ServerRoute{
+					Route: mux.NewRouter().NewRoute(),
+				},
+			}
+
+			rt, err := rls.Parse(test.expression)
+			require.NoError(t, err)
+
+			for testURL, expected := range test.urls {
+				req := testhelpers.MustNewRequest(http.MethodGet, testURL, nil)
+				reqHostMid.ServeHTTP(nil, req, func(w http.ResponseWriter, r *http.Request) {
+					assert.Equal(t, expected, rt.Match(r, &mux.RouteMatch{}), testURL)
+				})
+			}
+		})
+	}
+}
+
+func TestParseBooleanRulesErrors(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		expression string
+	}{
+		{
+			desc:       "missing closing parenthesis",
+			expression: "(Host:foo.bar || Host:bar.foo",
+		},
+		{
+			desc:       "unexpected closing parenthesis",
+			expression: "Host:foo.bar)",
+		},
+		{
+			desc:       "missing operand",
+			expression: "Host:foo.bar ||",
+		},
+		{
+			desc:       "unknown function",
+			expression: "Host:foo.bar || Foo:bar",
+		},
+		{
+			desc:       "modifier under or",
+			expression: "PathPrefixStrip:/foo || Host:foo.bar",
+		},
+		{
+			desc:       "modifier under not",
+			expression: "!AddPrefix:/foo",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			rls := &Rules{
+				Route: &types.ServerRoute{
+					Route: mux.NewRouter().NewRoute(),
+				},
+			}
+
+			_, err := rls.Parse(test.expression)
+			assert.Error(t, err)
+		})
+	}
+}
+
 func TestPriorites(t *testing.T) {
 	router := mux.NewRouter()
 	router.StrictSlash(true)
@@ -327,6 +528,233 @@ func TestPathPrefix(t *testing.T) {
 	}
 }
 
+func TestClientIP(t *testing.T) {
+	testCases := []struct {
+		desc              string
+		sourceRanges      []string
+		trustedForwarders []string
+		remoteAddr        string
+		xForwardedFor     string
+		expected          bool
+	}{
+		{
+			desc:         "remote address in range",
+			sourceRanges: []string{"10.0.0.0/8"},
+			remoteAddr:   "10.1.2.3:1234",
+			expected:     true,
+		},
+		{
+			desc:         "remote address matches IP",
+			sourceRanges: []string{"192.168.1.1", "10.0.0.0/8"},
+			remoteAddr:   "192.168.1.1:1234",
+			expected:     true,
+		},
+		{
+			desc:         "remote address not in range",
+			sourceRanges: []string{"10.0.0.0/8"},
+			remoteAddr:   "192.168.1.1:1234",
+			expected:     false,
+		},
+		{
+			desc:          "X-Forwarded-For ignored without trusted forwarders",
+			sourceRanges:  []string{"10.0.0.0/8"},
+			remoteAddr:    "192.168.1.1:1234",
+			xForwardedFor: "10.1.2.3",
+			expected:      false,
+		},
+		{
+			desc:              "X-Forwarded-For ignored from untrusted forwarder",
+			sourceRanges:      []string{"10.0.0.0/8"},
+			trustedForwarders: []string{"172.16.0.1"},
+			remoteAddr:        "192.168.1.1:1234",
+			xForwardedFor:     "10.1.2.3",
+			expected:          false,
+		},
+		{
+			desc:              "X-Forwarded-For used from trusted forwarder",
+			sourceRanges:      []string{"10.0.0.0/8"},
+			trustedForwarders: []string{"172.16.0.0/16"},
+			remoteAddr:        "172.16.0.1:1234",
+			xForwardedFor:     "192.168.1.1, 10.1.2.3",
+			expected:          true,
+		},
+		{
+			desc:              "X-Forwarded-For not in range from trusted forwarder",
+			sourceRanges:      []string{"10.0.0.0/8"},
+			trustedForwarders: []string{"172.16.0.0/16"},
+			remoteAddr:        "172.16.0.1:1234",
+			xForwardedFor:     "192.168.1.1",
+			expected:          false,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			rls := &Rules{
+				Route: &types.ServerRoute{
+					Route: &mux.Route{},
+				},
+			}
+
+			if len(test.trustedForwarders) > 0 {
+				trustedForwarders, err := whitelist.NewIP(test.trustedForwarders, false, false)
+				require.NoError(t, err)
+				rls.TrustedForwarders = trustedForwarders
+			}
+
+			rt := rls.clientIP(test.sourceRanges...)
+			require.NoError(t, rls.err)
+
+			req := testhelpers.MustNewRequest(http.MethodGet, "http://foo.bar", nil)
+			req.RemoteAddr = test.remoteAddr
+			if len(test.xForwardedFor) > 0 {
+				req.Header.Set(whitelist.XForwardedFor, test.xForwardedFor)
+			}
+
+			assert.Equal(t, test.expected, rt.Match(req, &mux.RouteMatch{}))
+		})
+	}
+}
+
+func TestClientIPInvalidRange(t *testing.T) {
+	rls := &Rules{
+		Route: &types.ServerRoute{
+			Route: mux.NewRouter().NewRoute(),
+		},
+	}
+
+	_, err := rls.Parse("ClientIP:10.0.0.0/123")
+	assert.Error(t, err)
+}
+
+func TestCookieAndQueryRegexp(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		expression string
+		cookies    []*http.Cookie
+		url        string
+		expected   bool
+	}{
+		{
+			desc:       "cookie matches",
+			expression: "Cookie: beta, true",
+			cookies:    []*http.Cookie{{Name: "beta", Value: "true"}},
+			expected:   true,
+		},
+		{
+			desc:       "cookie with other value",
+			expression: "Cookie: beta, true",
+			cookies:    []*http.Cookie{{Name: "beta", Value: "false"}},
+			expected:   false,
+		},
+		{
+			desc:       "missing cookie",
+			expression: "Cookie: beta, true",
+			cookies:    []*http.Cookie{{Name: "session", Value: "true"}},
+			expected:   false,
+		},
+		{
+			desc:       "several cookies",
+			expression: "Cookie: beta, true, lang, fr",
+			cookies:    []*http.Cookie{{Name: "lang", Value: "fr"}, {Name: "beta", Value: "true"}},
+			expected:   true,
+		},
+		{
+			desc:       "cookie regexp matches",
+			expression: "CookieRegexp: group, ^(beta|canary)$",
+			cookies:    []*http.Cookie{{Name: "group", Value: "canary"}},
+			expected:   true,
+		},
+		{
+			desc:       "cookie regexp doesn't match",
+			expression: "CookieRegexp: group, ^(beta|canary)$",
+			cookies:    []*http.Cookie{{Name: "group", Value: "stable"}},
+			expected:   false,
+		},
+		{
+			desc:       "query regexp matches",
+			expression: "QueryRegexp: version, ^v[0-9]+$",
+			url:        "http://foo.bar/?version=v2",
+			expected:   true,
+		},
+		{
+			desc:       "query regexp matches one of the values",
+			expression: "QueryRegexp: version, ^v[0-9]+$",
+			url:        "http://foo.bar/?version=latest&version=v3",
+			expected:   true,
+		},
+		{
+			desc:       "query regexp doesn't match",
+			expression: "QueryRegexp: version, ^v[0-9]+$",
+			url:        "http://foo.bar/?version=latest",
+			expected:   false,
+		},
+		{
+			desc:       "query regexp missing key",
+			expression: "QueryRegexp: version, .*",
+			url:        "http://foo.bar/",
+			expected:   false,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			rls := &Rules{
+				Route: &types.ServerRoute{
+					Route: mux.NewRouter().NewRoute(),
+				},
+			}
+
+			rt, err := rls.Parse(test.expression)
+			require.NoError(t, err)
+
+			url := test.url
+			if len(url) == 0 {
+				url = "http://foo.bar/"
+			}
+
+			req := testhelpers.MustNewRequest(http.MethodGet, url, nil)
+			for _, c := range test.cookies {
+				req.AddCookie(c)
+			}
+
+			assert.Equal(t, test.expected, rt.Match(req, &mux.RouteMatch{}))
+		})
+	}
+}
+
+func TestCookieAndQueryRegexpInvalid(t *testing.T) {
+	expressions := []string{
+		"Cookie: beta",
+		"CookieRegexp: beta",
+		"CookieRegexp: beta, [a-",
+		"QueryRegexp: version",
+		"QueryRegexp: version, [a-",
+	}
+
+	for _, expression := range expressions {
+		expression := expression
+		t.Run(expression, func(t *testing.T) {
+			t.Parallel()
+
+			rls := &Rules{
+				Route: &types.ServerRoute{
+					Route: mux.NewRouter().NewRoute(),
+				},
+			}
+
+			_, err := rls.Parse(expression)
+			assert.Error(t, err)
+		})
+	}
+}
+
 type fakeHandler struct {
 	name string
 }
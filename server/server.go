@@ -23,10 +23,12 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/negroni"
 
+	"github.com/pteich/traefik/balancer"
 	"github.com/pteich/traefik/cluster"
 	"github.com/pteich/traefik/configuration"
 	"github.com/pteich/traefik/configuration/router"
 	"github.com/pteich/traefik/h2c"
+	"github.com/pteich/traefik/healthcheck"
 	"github.com/pteich/traefik/log"
 	"github.com/pteich/traefik/metrics"
 	"github.com/pteich/traefik/middlewares"
@@ -34,8 +36,10 @@ import (
 	"github.com/pteich/traefik/middlewares/tracing"
 	"github.com/pteich/traefik/provider"
 	"github.com/pteich/traefik/safe"
+	"github.com/pteich/traefik/tcp"
 	traefiktls "github.com/pteich/traefik/tls"
 	"github.com/pteich/traefik/types"
+	"github.com/pteich/traefik/udp"
 	"github.com/pteich/traefik/whitelist"
 )
 
@@ -116,6 +120,9 @@ type Server struct {
 	configurationListeners        []func(types.Configuration)
 	entryPoints                   map[string]EntryPoint
 	bufferPool                    httputil.BufferPool
+	serverStarts                  map[string]*balancer.ServerStarts
+	dnsDiscoveries                []*dnsDiscovery
+	dnsDiscoveriesCancel          context.CancelFunc
 }
 
 // EntryPoint entryPoint information (configuration + internalRouter)
@@ -133,6 +140,11 @@ type serverEntryPoint struct {
 	httpServer              *h2c.Server
 	listener                net.Listener
 	httpRouter              *middlewares.HandlerSwitcher
+	tcpRouter               *tcp.HandlerSwitcher
+	tcpTLSConfig            *tls.Config
+	udpForwarder            *udp.Forwarder
+	udpLoadBalancer         *udp.LoadBalancer
+	udpFrontend             string
 	certs                   *traefiktls.CertificateStore
 	onDemandListener        func(string) (*tls.Certificate, error)
 	tlsALPNGetter           func(string) (*tls.Certificate, error)
@@ -140,6 +152,19 @@ type serverEntryPoint struct {
 }
 
 func (s serverEntryPoint) Shutdown(ctx context.Context) {
+	if s.tcpRouter != nil && s.listener != nil {
+		// Stop accepting TCP connections, the ongoing ones are tracked by the connection tracker.
+		if err := s.listener.Close(); err != nil {
+			log.Errorf("Error while closing TCP listener: %v", err)
+		}
+	}
+
+	if s.udpForwarder != nil {
+		if err := s.udpForwarder.Close(); err != nil {
+			log.Errorf("Error while closing UDP listener: %v", err)
+		}
+	}
+
 	var wg sync.WaitGroup
 	if s.httpServer != nil {
 		wg.Add(1)
@@ -231,6 +256,10 @@ func NewServer(globalConfiguration configuration.GlobalConfiguration, provider p
 
 	server.metricsRegistry = registerMetricClients(globalConfiguration.Metrics)
 
+	if server.globalConfiguration.API != nil {
+		server.globalConfiguration.API.HealthCheck = healthcheck.GetHealthCheck(server.metricsRegistry)
+	}
+
 	if globalConfiguration.Cluster != nil {
 		// leadership creation if cluster mode
 		server.leadership = cluster.NewLeadership(server.routinesPool.Ctx(), globalConfiguration.Cluster)
@@ -351,6 +380,18 @@ func (s *Server) startHTTPServers() {
 	s.serverEntryPoints = s.buildServerEntryPoints()
 
 	for newServerEntryPointName, newServerEntryPoint := range s.serverEntryPoints {
+		if newServerEntryPoint.tcpRouter != nil {
+			serverEntryPoint := s.setupTCPServerEntryPoint(newServerEntryPointName, newServerEntryPoint)
+			go s.startTCPServer(serverEntryPoint)
+			continue
+		}
+
+		if s.entryPoints[newServerEntryPointName].Configuration.IsUDP() {
+			serverEntryPoint := s.setupUDPServerEntryPoint(newServerEntryPointName)
+			go s.startUDPServer(newServerEntryPointName, serverEntryPoint)
+			continue
+		}
+
 		serverEntryPoint := s.setupServerEntryPoint(newServerEntryPointName, newServerEntryPoint)
 		go s.startServer(serverEntryPoint)
 	}
@@ -592,18 +633,9 @@ func (s *Server) prepareServer(entryPointName string, entryPoint *configuration.
 		return nil, nil, fmt.Errorf("error creating TLS config: %v", err)
 	}
 
-	listener, err := net.Listen("tcp", entryPoint.Address)
+	listener, err := buildListener(entryPoint)
 	if err != nil {
-		return nil, nil, fmt.Errorf("error opening listener: %v", err)
-	}
-
-	listener = tcpKeepAliveListener{listener.(*net.TCPListener)}
-
-	if entryPoint.ProxyProtocol != nil {
-		listener, err = buildProxyProtocolListener(entryPoint, listener)
-		if err != nil {
-			return nil, nil, err
-		}
+		return nil, nil, err
 	}
 
 	return &h2c.Server{
@@ -621,6 +653,24 @@ func (s *Server) prepareServer(entryPointName string, entryPoint *configuration.
 		nil
 }
 
+func buildListener(entryPoint *configuration.EntryPoint) (net.Listener, error) {
+	listener, err := net.Listen("tcp", entryPoint.Address)
+	if err != nil {
+		return nil, fmt.Errorf("error opening listener: %v", err)
+	}
+
+	listener = tcpKeepAliveListener{listener.(*net.TCPListener)}
+
+	if entryPoint.ProxyProtocol != nil {
+		listener, err = buildProxyProtocolListener(entryPoint, listener)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return listener, nil
+}
+
 func buildProxyProtocolListener(entryPoint *configuration.EntryPoint, listener net.Listener) (net.Listener, error) {
 	IPs, err := whitelist.NewIP(entryPoint.ProxyProtocol.TrustedIPs, entryPoint.ProxyProtocol.Insecure, false)
 	if err != nil {
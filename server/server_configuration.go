@@ -22,9 +22,11 @@ import (
 	"github.com/pteich/traefik/middlewares"
 	"github.com/pteich/traefik/middlewares/pipelining"
 	"github.com/pteich/traefik/rules"
+	"github.com/pteich/traefik/tcp"
 	traefiktls "github.com/pteich/traefik/tls"
 	"github.com/pteich/traefik/tls/generate"
 	"github.com/pteich/traefik/types"
+	"github.com/pteich/traefik/whitelist"
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/negroni"
 	"github.com/vulcand/oxy/forward"
@@ -50,6 +52,16 @@ func (s *Server) loadConfiguration(configMsg types.ConfigMessage) {
 	for newServerEntryPointName, newServerEntryPoint := range newServerEntryPoints {
 		s.serverEntryPoints[newServerEntryPointName].httpRouter.UpdateHandler(newServerEntryPoint.httpRouter.GetHandler())
 
+		if newServerEntryPoint.tcpRouter != nil {
+			router := newServerEntryPoint.tcpRouter.GetHandler()
+			router.SetTLSConfig(s.serverEntryPoints[newServerEntryPointName].tcpTLSConfig)
+			s.serverEntryPoints[newServerEntryPointName].tcpRouter.UpdateHandler(router)
+		}
+
+		if forwarder := s.serverEntryPoints[newServerEntryPointName].udpForwarder; forwarder != nil {
+			forwarder.SetLoadBalancer(newServerEntryPoint.udpLoadBalancer)
+		}
+
 		if s.entryPoints[newServerEntryPointName].Configuration.TLS == nil {
 			if newServerEntryPoint.certs.ContainsCertificates() {
 				log.Debugf("Certificates not added to non-TLS entryPoint %s.", newServerEntryPointName)
@@ -58,7 +70,7 @@ func (s *Server) loadConfiguration(configMsg types.ConfigMessage) {
 			s.serverEntryPoints[newServerEntryPointName].certs.DynamicCerts.Set(newServerEntryPoint.certs.DynamicCerts.Get())
 			s.serverEntryPoints[newServerEntryPointName].certs.ResetCache()
 		}
-		log.Infof("Server configuration reloaded on %s", s.serverEntryPoints[newServerEntryPointName].httpServer.Addr)
+		log.Infof("Server configuration reloaded on %s", s.entryPoints[newServerEntryPointName].Configuration.Address)
 	}
 
 	s.currentConfigurations.Set(newConfigurations)
@@ -105,7 +117,10 @@ func (s *Server) loadConfig(configurations types.Configurations, globalConfigura
 		}
 	}
 
+	s.retainServerStarts(configurations)
+
 	healthcheck.GetHealthCheck(s.metricsRegistry).SetBackendsConfiguration(s.routinesPool.Ctx(), backendsHealthCheck)
+	s.startDNSDiscoveries(s.routinesPool.Ctx())
 
 	// Get new certificates list sorted per entrypoints
 	// Update certificates
@@ -135,9 +150,14 @@ func (s *Server) loadFrontendConfig(
 		return nil, fmt.Errorf("no entrypoint defined for frontend %s", frontendName)
 	}
 
-	backend := config.Backends[frontend.Backend]
-	if backend == nil {
-		return nil, fmt.Errorf("undefined backend '%s' for frontend %s", frontend.Backend, frontendName)
+	for _, weightedBackend := range frontend.GetBackends() {
+		if config.Backends[weightedBackend.Name] == nil {
+			return nil, fmt.Errorf("undefined backend '%s' for frontend %s", weightedBackend.Name, frontendName)
+		}
+	}
+
+	if frontend.Mirror != nil && config.Backends[frontend.Mirror.Backend] == nil {
+		return nil, fmt.Errorf("undefined mirror backend '%s' for frontend %s", frontend.Mirror.Backend, frontendName)
 	}
 
 	frontendHash, err := frontend.Hash()
@@ -152,9 +172,21 @@ func (s *Server) loadFrontendConfig(
 
 		entryPoint := s.entryPoints[entryPointName].Configuration
 
-		if backendsHandlers[entryPointName+providerName+frontendHash] == nil {
-			log.Debugf("Creating backend %s", frontend.Backend)
+		if entryPoint.IsTCP() {
+			if err := s.loadTCPFrontendConfig(frontendName, frontend, config.Backends, serverEntryPoints[entryPointName]); err != nil {
+				return nil, err
+			}
+			continue
+		}
 
+		if entryPoint.IsUDP() {
+			if err := s.loadUDPFrontendConfig(frontendName, frontend, config.Backends, serverEntryPoints[entryPointName]); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if backendsHandlers[entryPointName+providerName+frontendHash] == nil {
 			handlers, responseModifier, postConfig, err := s.buildMiddlewares(frontendName, frontend, config.Backends, entryPointName, entryPoint, providerName)
 			if err != nil {
 				return nil, err
@@ -164,23 +196,92 @@ func (s *Server) loadFrontendConfig(
 				postConfigs = append(postConfigs, postConfig)
 			}
 
-			fwd, err := s.buildForwarder(entryPointName, entryPoint, frontendName, frontend, responseModifier, backend)
-			if err != nil {
-				return nil, fmt.Errorf("failed to create the forwarder for frontend %s: %v", frontendName, err)
+			var splitter *middlewares.BackendSplitter
+			if len(frontend.Backends) > 0 {
+				splitter = middlewares.NewBackendSplitter(buildBackendsStickyCookie(frontendName, frontend.BackendsStickiness))
 			}
 
-			lb, healthCheckConfig, err := s.buildBalancerMiddlewares(frontendName, frontend, backend, fwd)
-			if err != nil {
-				return nil, err
+			var lb http.Handler
+			for _, weightedBackend := range frontend.GetBackends() {
+				backendName := weightedBackend.Name
+				backend := config.Backends[backendName]
+
+				log.Debugf("Creating backend %s", backendName)
+
+				fwd, roundTripper, err := s.buildForwarder(entryPointName, entryPoint, frontendName, frontend, responseModifier, backendName, backend)
+				if err != nil {
+					return nil, fmt.Errorf("failed to create the forwarder for frontend %s: %v", frontendName, err)
+				}
+
+				backendHandler, healthCheckConfig, err := s.buildBalancerMiddlewares(frontendName, frontend, backendName, backend, fwd, roundTripper, false)
+				if err != nil {
+					return nil, err
+				}
+
+				// Handler used by error pages
+				if backendsHandlers[entryPointName+providerName+backendName] == nil {
+					backendsHandlers[entryPointName+providerName+backendName] = backendHandler
+				}
+
+				if healthCheckConfig != nil {
+					backendsHealthCheck[entryPointName+providerName+frontendHash+backendName] = healthCheckConfig
+				}
+
+				if splitter == nil {
+					lb = backendHandler
+					continue
+				}
+
+				if s.metricsRegistry.IsEnabled() {
+					backendHandler = negroni.New(middlewares.NewBackendMetricsMiddleware(s.metricsRegistry, backendName), negroni.Wrap(backendHandler))
+				}
+
+				log.Debugf("Adding backend %s to frontend %s with weight %d", backendName, frontendName, weightedBackend.Weight)
+				splitter.AddBackend(backendName, weightedBackend.Weight, backendHandler)
 			}
 
-			// Handler used by error pages
-			if backendsHandlers[entryPointName+providerName+frontend.Backend] == nil {
-				backendsHandlers[entryPointName+providerName+frontend.Backend] = lb
+			if splitter != nil {
+				lb = splitter
 			}
 
-			if healthCheckConfig != nil {
-				backendsHealthCheck[entryPointName+providerName+frontendHash] = healthCheckConfig
+			if frontend.Mirror != nil && frontend.Mirror.Percent != nil && *frontend.Mirror.Percent == 0 {
+				log.Debugf("Mirroring disabled for frontend %s", frontendName)
+			} else if frontend.Mirror != nil {
+				mirrorName := frontend.Mirror.Backend
+
+				log.Debugf("Creating mirror backend %s", mirrorName)
+
+				mirrorHandler, healthCheckConfig, err := s.buildMirrorHandler(entryPointName, entryPoint, frontendName, frontend, responseModifier, mirrorName, config.Backends[mirrorName])
+				if err != nil {
+					return nil, err
+				}
+
+				if healthCheckConfig != nil {
+					backendsHealthCheck[entryPointName+providerName+frontendHash+"mirror"+mirrorName] = healthCheckConfig
+				}
+
+				percent := 100
+				if frontend.Mirror.Percent != nil {
+					percent = *frontend.Mirror.Percent
+				}
+
+				lb, err = middlewares.NewMirroring(lb, mirrorHandler, percent, frontend.Mirror.MaxBodySize, mirrorName, s.metricsRegistry)
+				if err != nil {
+					return nil, fmt.Errorf("failed to create the mirror of frontend %s: %v", frontendName, err)
+				}
+			}
+
+			// Rate Limit, once for all the backends of the frontend
+			if frontend.RateLimit != nil && len(frontend.RateLimit.RateSet) > 0 {
+				handler, err := buildRateLimiter(lb, frontend.RateLimit)
+				if err != nil {
+					return nil, fmt.Errorf("error creating rate limiter: %v", err)
+				}
+
+				lb = s.wrapHTTPHandlerWithAccessLog(
+					s.tracingMiddleware.NewHTTPHandlerWrapper("Rate limit", handler, false),
+					fmt.Sprintf("rate limit for %s", frontendName),
+				)
 			}
 
 			n := negroni.New()
@@ -197,7 +298,12 @@ func (s *Server) loadFrontendConfig(
 				frontend.Backend, entryPointName, providerName, frontendName, frontendHash)
 		}
 
-		serverRoute, err := buildServerRoute(serverEntryPoints[entryPointName], frontendName, frontend, hostResolver)
+		trustedForwarders, err := buildTrustedForwarders(entryPoint.ForwardedHeaders)
+		if err != nil {
+			return nil, fmt.Errorf("error creating trusted forwarders for frontend %s: %v", frontendName, err)
+		}
+
+		serverRoute, err := buildServerRoute(serverEntryPoints[entryPointName], frontendName, frontend, hostResolver, trustedForwarders)
 		if err != nil {
 			return nil, err
 		}
@@ -215,25 +321,47 @@ func (s *Server) loadFrontendConfig(
 	return postConfigs, nil
 }
 
+func (s *Server) buildMirrorHandler(entryPointName string, entryPoint *configuration.EntryPoint,
+	frontendName string, frontend *types.Frontend, responseModifier modifyResponse,
+	mirrorName string, mirror *types.Backend) (http.Handler, *healthcheck.BackendConfig, error) {
+
+	fwd, roundTripper, err := s.buildForwarder(entryPointName, entryPoint, frontendName, frontend, responseModifier, mirrorName, mirror)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create the mirror forwarder for frontend %s: %v", frontendName, err)
+	}
+
+	lb, healthCheckConfig, err := s.buildBalancerMiddlewares(frontendName, frontend, mirrorName, mirror, fwd, roundTripper, true)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if s.metricsRegistry.IsEnabled() {
+		lb = negroni.New(middlewares.NewBackendMirrorMetricsMiddleware(s.metricsRegistry, mirrorName), negroni.Wrap(lb))
+	}
+
+	return lb, healthCheckConfig, nil
+}
+
+// buildForwarder returns the forwarder of the backend, and the round tripper it uses to reach the servers.
 func (s *Server) buildForwarder(entryPointName string, entryPoint *configuration.EntryPoint,
 	frontendName string, frontend *types.Frontend,
-	responseModifier modifyResponse, backend *types.Backend) (http.Handler, error) {
+	responseModifier modifyResponse, backendName string, backend *types.Backend) (http.Handler, http.RoundTripper, error) {
 
-	roundTripper, err := s.getRoundTripper(entryPointName, frontend.PassTLSCert, entryPoint.TLS)
+	roundTripper, err := s.getRoundTripper(entryPointName, frontend.PassTLSCert, entryPoint.TLS, backend.Transport)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create RoundTripper for frontend %s: %v", frontendName, err)
+		return nil, nil, fmt.Errorf("failed to create RoundTripper for frontend %s: %v", frontendName, err)
 	}
 
 	rewriter, err := NewHeaderRewriter(entryPoint.ForwardedHeaders.TrustedIPs, entryPoint.ForwardedHeaders.Insecure)
 	if err != nil {
-		return nil, fmt.Errorf("error creating rewriter for frontend %s: %v", frontendName, err)
+		return nil, nil, fmt.Errorf("error creating rewriter for frontend %s: %v", frontendName, err)
 	}
 
 	var flushInterval parse.Duration
 	if backend.ResponseForwarding != nil {
 		err := flushInterval.Set(backend.ResponseForwarding.FlushInterval)
 		if err != nil {
-			return nil, fmt.Errorf("error creating flush interval for frontend %s: %v", frontendName, err)
+			return nil, nil, fmt.Errorf("error creating flush interval for frontend %s: %v", frontendName, err)
 		}
 	}
 
@@ -263,11 +391,13 @@ func (s *Server) buildForwarder(entryPointName string, entryPoint *configuration
 		}),
 	)
 	if err != nil {
-		return nil, fmt.Errorf("error creating forwarder for frontend %s: %v", frontendName, err)
+		return nil, nil, fmt.Errorf("error creating forwarder for frontend %s: %v", frontendName, err)
 	}
 
+	fwd = newUnixSocketHandler(fwd)
+
 	if s.tracingMiddleware.IsEnabled() {
-		tm := s.tracingMiddleware.NewForwarderMiddleware(frontendName, frontend.Backend)
+		tm := s.tracingMiddleware.NewForwarderMiddleware(frontendName, backendName)
 
 		next := fwd
 		fwd = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -277,15 +407,15 @@ func (s *Server) buildForwarder(entryPointName string, entryPoint *configuration
 
 	fwd = pipelining.NewPipelining(fwd)
 
-	return fwd, nil
+	return fwd, roundTripper, nil
 }
 
-func buildServerRoute(serverEntryPoint *serverEntryPoint, frontendName string, frontend *types.Frontend, hostResolver *hostresolver.Resolver) (*types.ServerRoute, error) {
+func buildServerRoute(serverEntryPoint *serverEntryPoint, frontendName string, frontend *types.Frontend, hostResolver *hostresolver.Resolver, trustedForwarders *whitelist.IP) (*types.ServerRoute, error) {
 	serverRoute := &types.ServerRoute{Route: serverEntryPoint.httpRouter.GetHandler().NewRoute().Name(frontendName)}
 
 	priority := 0
 	for routeName, route := range frontend.Routes {
-		rls := rules.Rules{Route: serverRoute, HostResolver: hostResolver}
+		rls := rules.Rules{Route: serverRoute, HostResolver: hostResolver, TrustedForwarders: trustedForwarders}
 		newRoute, err := rls.Parse(route.Rule)
 		if err != nil {
 			return nil, fmt.Errorf("error creating route for frontend %s: %v", frontendName, err)
@@ -306,6 +436,16 @@ func buildServerRoute(serverEntryPoint *serverEntryPoint, frontendName string, f
 	return serverRoute, nil
 }
 
+// buildTrustedForwarders returns the addresses whose X-Forwarded-For header is trusted by the ClientIP rule.
+// Only explicitly trusted IPs are taken into account, the insecure mode is ignored.
+func buildTrustedForwarders(forwardedHeaders *configuration.ForwardedHeaders) (*whitelist.IP, error) {
+	if forwardedHeaders == nil || len(forwardedHeaders.TrustedIPs) == 0 {
+		return nil, nil
+	}
+
+	return whitelist.NewIP(forwardedHeaders.TrustedIPs, false, false)
+}
+
 func (s *Server) preLoadConfiguration(configMsg types.ConfigMessage) {
 	providersThrottleDuration := time.Duration(s.globalConfiguration.ProvidersThrottleDuration)
 	s.defaultConfigurationValues(configMsg.Configuration)
@@ -579,6 +719,10 @@ func (s *Server) buildServerEntryPoints() map[string]*serverEntryPoint {
 			tlsALPNGetter:    entryPoint.TLSALPNGetter,
 		}
 
+		if entryPoint.Configuration.IsTCP() {
+			serverEntryPoints[entryPointName].tcpRouter = tcp.NewHandlerSwitcher(tcp.NewRouter())
+		}
+
 		if entryPoint.CertificateStore != nil {
 			serverEntryPoints[entryPointName].certs = entryPoint.CertificateStore
 		} else {
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"regexp"
 	"testing"
 	"time"
 
@@ -86,7 +87,7 @@ func TestServerLoadConfigHealthCheckOptions(t *testing.T) {
 		},
 	}
 
-	for _, lbMethod := range []string{"Wrr", "Drr"} {
+	for _, lbMethod := range []string{"Wrr", "Drr", "LeastConn", "Hash", "PeakEwma"} {
 		for _, healthCheck := range healthChecks {
 			t.Run(fmt.Sprintf("%s/hc=%t", lbMethod, healthCheck != nil), func(t *testing.T) {
 				globalConfig := configuration.GlobalConfiguration{
@@ -137,16 +138,64 @@ func TestServerLoadConfigHealthCheckOptions(t *testing.T) {
 
 				_ = srv.loadConfig(dynamicConfigs, globalConfig)
 
-				expectedNumHealthCheckBackends := 0
-				if healthCheck != nil {
-					expectedNumHealthCheckBackends = 1
+				// The backends without health check are registered for the status of their servers, but not checked.
+				backends := healthcheck.GetHealthCheck(th.NewCollectingHealthCheckMetrics()).Backends
+				require.Len(t, backends, 1, "health check backends")
+				for _, backend := range backends {
+					assert.Equal(t, healthCheck != nil, backend.Interval > 0, "checked backend")
 				}
-				assert.Len(t, healthcheck.GetHealthCheck(th.NewCollectingHealthCheckMetrics()).Backends, expectedNumHealthCheckBackends, "health check backends")
 			})
 		}
 	}
 }
 
+func TestServerLoadConfigSlowStart(t *testing.T) {
+	globalConfig := configuration.GlobalConfiguration{}
+	entryPoints := map[string]EntryPoint{
+		"http": {Configuration: &configuration.EntryPoint{ForwardedHeaders: &configuration.ForwardedHeaders{Insecure: true}}},
+	}
+
+	dynamicConfigs := func(slowStart string) types.Configurations {
+		return types.Configurations{
+			"config": &types.Configuration{
+				Frontends: map[string]*types.Frontend{
+					"frontend": {
+						EntryPoints: []string{"http"},
+						Backend:     "backend",
+					},
+				},
+				Backends: map[string]*types.Backend{
+					"backend": {
+						Servers: map[string]types.Server{
+							"server": {
+								URL:    "http://localhost",
+								Weight: 1,
+							},
+						},
+						LoadBalancer: &types.LoadBalancer{
+							Method:    "Drr",
+							SlowStart: slowStart,
+						},
+					},
+				},
+			},
+		}
+	}
+
+	srv := NewServer(globalConfig, nil, entryPoints)
+
+	_ = srv.loadConfig(dynamicConfigs("1m"), globalConfig)
+	require.Contains(t, srv.serverStarts, "backend")
+	starts := srv.serverStarts["backend"]
+
+	// The starts of the servers are kept across the reloads.
+	_ = srv.loadConfig(dynamicConfigs("1m"), globalConfig)
+	assert.Same(t, starts, srv.serverStarts["backend"])
+
+	_ = srv.loadConfig(dynamicConfigs(""), globalConfig)
+	assert.NotContains(t, srv.serverStarts, "backend")
+}
+
 func TestServerLoadConfigEmptyBasicAuth(t *testing.T) {
 	globalConfig := configuration.GlobalConfiguration{
 		EntryPoints: configuration.EntryPoints{
@@ -275,6 +324,124 @@ func TestReuseBackend(t *testing.T) {
 	assert.Equal(t, http.StatusUnauthorized, responseRecorderUnauthorized.Result().StatusCode, "status code")
 }
 
+func TestWeightedBackends(t *testing.T) {
+	testServerFoo := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer testServerFoo.Close()
+
+	testServerBar := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusTeapot)
+	}))
+	defer testServerBar.Close()
+
+	globalConfig := configuration.GlobalConfiguration{
+		DefaultEntryPoints: []string{"http"},
+	}
+
+	entryPoints := map[string]EntryPoint{
+		"http": {Configuration: &configuration.EntryPoint{
+			ForwardedHeaders: &configuration.ForwardedHeaders{Insecure: true},
+		}},
+	}
+
+	config := th.BuildConfiguration(
+		th.WithFrontends(
+			th.WithFrontend("",
+				th.WithFrontendName("frontend"),
+				th.WithEntryPoints("http"),
+				th.WithRoutes(th.WithRoute("/split", "Path: /split"))),
+		),
+		th.WithBackends(
+			th.WithBackendNew("foo",
+				th.WithLBMethod("wrr"),
+				th.WithServersNew(th.WithServerNew(testServerFoo.URL))),
+			th.WithBackendNew("bar",
+				th.WithLBMethod("wrr"),
+				th.WithServersNew(th.WithServerNew(testServerBar.URL))),
+		),
+	)
+	config.Frontends["frontend"].Backends = []types.WeightedBackend{
+		{Name: "foo", Weight: 3},
+		{Name: "bar", Weight: 1},
+	}
+
+	srv := NewServer(globalConfig, nil, entryPoints)
+
+	serverEntryPoints := srv.loadConfig(types.Configurations{"config": config}, globalConfig)
+
+	statusCodes := make(map[int]int)
+	for i := 0; i < 8; i++ {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, testServerFoo.URL+"/split", nil)
+		serverEntryPoints["http"].httpRouter.ServeHTTP(recorder, request)
+
+		statusCodes[recorder.Result().StatusCode]++
+	}
+
+	assert.Equal(t, map[int]int{http.StatusOK: 6, http.StatusTeapot: 2}, statusCodes)
+}
+
+func TestWeightedBackendsRateLimit(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer testServer.Close()
+
+	globalConfig := configuration.GlobalConfiguration{
+		DefaultEntryPoints: []string{"http"},
+	}
+
+	entryPoints := map[string]EntryPoint{
+		"http": {Configuration: &configuration.EntryPoint{
+			ForwardedHeaders: &configuration.ForwardedHeaders{Insecure: true},
+		}},
+	}
+
+	config := th.BuildConfiguration(
+		th.WithFrontends(
+			th.WithFrontend("",
+				th.WithFrontendName("frontend"),
+				th.WithEntryPoints("http"),
+				th.WithRoutes(th.WithRoute("/split", "Path: /split"))),
+		),
+		th.WithBackends(
+			th.WithBackendNew("foo",
+				th.WithLBMethod("wrr"),
+				th.WithServersNew(th.WithServerNew(testServer.URL))),
+			th.WithBackendNew("bar",
+				th.WithLBMethod("wrr"),
+				th.WithServersNew(th.WithServerNew(testServer.URL))),
+		),
+	)
+	config.Frontends["frontend"].Backends = []types.WeightedBackend{
+		{Name: "foo", Weight: 1},
+		{Name: "bar", Weight: 1},
+	}
+	config.Frontends["frontend"].RateLimit = &types.RateLimit{
+		ExtractorFunc: "client.ip",
+		RateSet: map[string]*types.Rate{
+			"rate": {Period: flaeg.Duration(time.Minute), Average: 2, Burst: 2},
+		},
+	}
+
+	srv := NewServer(globalConfig, nil, entryPoints)
+
+	serverEntryPoints := srv.loadConfig(types.Configurations{"config": config}, globalConfig)
+
+	statusCodes := make(map[int]int)
+	for i := 0; i < 4; i++ {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, testServer.URL+"/split", nil)
+		serverEntryPoints["http"].httpRouter.ServeHTTP(recorder, request)
+
+		statusCodes[recorder.Result().StatusCode]++
+	}
+
+	// The rate of the frontend is shared by its backends.
+	assert.Equal(t, map[int]int{http.StatusOK: 2, http.StatusTooManyRequests: 2}, statusCodes)
+}
+
 func TestThrottleProviderConfigReload(t *testing.T) {
 	throttleDuration := 30 * time.Millisecond
 	publishConfig := make(chan types.ConfigMessage)
@@ -472,6 +639,77 @@ func TestServerBuildHealthCheckOptions(t *testing.T) {
 				LB:       lb,
 			},
 		},
+		{
+			desc: "tcp type without path",
+			hc: &types.HealthCheck{
+				Type: "TCP",
+				Port: 8080,
+			},
+			expectedOpts: &healthcheck.Options{
+				Type:     healthcheck.TypeTCP,
+				Port:     8080,
+				Interval: globalInterval,
+				LB:       lb,
+			},
+		},
+		{
+			desc: "grpc type with service",
+			hc: &types.HealthCheck{
+				Type:    "grpc",
+				Service: "app",
+			},
+			expectedOpts: &healthcheck.Options{
+				Type:     healthcheck.TypeGRPC,
+				Service:  "app",
+				Interval: globalInterval,
+				LB:       lb,
+			},
+		},
+		{
+			desc: "unknown type",
+			hc: &types.HealthCheck{
+				Type: "icmp",
+				Path: "/path",
+			},
+			expectedOpts: nil,
+		},
+		{
+			desc: "expectations, thresholds and timeout",
+			hc: &types.HealthCheck{
+				Path:        "/path",
+				Method:      "head",
+				StatusCodes: []string{"200", "300-399"},
+				BodyRegex:   "^ok$",
+				Timeout:     "2s",
+				Rise:        3,
+				Fall:        2,
+			},
+			expectedOpts: &healthcheck.Options{
+				Path:        "/path",
+				Interval:    globalInterval,
+				LB:          lb,
+				Method:      http.MethodHead,
+				StatusCodes: types.HTTPCodeRanges{{200, 200}, {300, 399}},
+				BodyRegex:   regexp.MustCompile("^ok$"),
+				Timeout:     2 * time.Second,
+				Rise:        3,
+				Fall:        2,
+			},
+		},
+		{
+			desc: "invalid expectations and timeout",
+			hc: &types.HealthCheck{
+				Path:        "/path",
+				StatusCodes: []string{"2xx"},
+				BodyRegex:   "(",
+				Timeout:     "-1s",
+			},
+			expectedOpts: &healthcheck.Options{
+				Path:     "/path",
+				Interval: globalInterval,
+				LB:       lb,
+			},
+		},
 	}
 
 	for _, test := range testCases {
@@ -484,3 +722,61 @@ func TestServerBuildHealthCheckOptions(t *testing.T) {
 		})
 	}
 }
+
+func TestServerBuildPassiveHealthCheckOptions(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		phc          *types.PassiveHealthCheck
+		expectedOpts *healthcheck.PassiveOptions
+	}{
+		{
+			desc:         "nil passive health check",
+			phc:          nil,
+			expectedOpts: nil,
+		},
+		{
+			desc: "default values",
+			phc:  &types.PassiveHealthCheck{},
+			expectedOpts: &healthcheck.PassiveOptions{
+				ConsecutiveErrors:  5,
+				EjectionTime:       30 * time.Second,
+				MaxEjectionPercent: 50,
+			},
+		},
+		{
+			desc: "unparseable ejection time and invalid percentage",
+			phc: &types.PassiveHealthCheck{
+				EjectionTime:       "unparseable",
+				MaxEjectionPercent: 200,
+			},
+			expectedOpts: &healthcheck.PassiveOptions{
+				ConsecutiveErrors:  5,
+				EjectionTime:       30 * time.Second,
+				MaxEjectionPercent: 50,
+			},
+		},
+		{
+			desc: "custom values",
+			phc: &types.PassiveHealthCheck{
+				ConsecutiveErrors:  3,
+				EjectionTime:       "1m",
+				MaxEjectionPercent: 100,
+			},
+			expectedOpts: &healthcheck.PassiveOptions{
+				ConsecutiveErrors:  3,
+				EjectionTime:       time.Minute,
+				MaxEjectionPercent: 100,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			opts := buildPassiveHealthCheckOptions("backend", test.phc)
+			assert.Equal(t, test.expectedOpts, opts, "passive health check options")
+		})
+	}
+}
@@ -0,0 +1,264 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/pteich/traefik/healthcheck"
+	"github.com/pteich/traefik/hostresolver"
+	"github.com/pteich/traefik/log"
+	"github.com/pteich/traefik/metrics"
+	"github.com/pteich/traefik/safe"
+	"github.com/pteich/traefik/types"
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+const (
+	// defaultDNSDiscoveryInterval is the default maximum interval between two resolutions of the servers of a backend.
+	defaultDNSDiscoveryInterval = 30 * time.Second
+	// minDNSDiscoveryInterval is the minimum interval between two resolutions, whatever the TTL of the records.
+	minDNSDiscoveryInterval = time.Second
+	// dnsDiscoveryInitialTimeout is the maximum time the loading of the configuration waits for the first resolution,
+	// the servers of a slower resolution are added once resolved.
+	dnsDiscoveryInitialTimeout = time.Second
+)
+
+// serverLookup resolves the addresses of the servers of a backend.
+type serverLookup interface {
+	LookupSRV(name string) ([]hostresolver.Address, time.Duration, error)
+	LookupHost(name string, port int) ([]hostresolver.Address, time.Duration, error)
+}
+
+// dnsDiscovery keeps the servers of a load balancer in sync with the DNS records of a name.
+// The servers statically defined in the backend are never removed.
+type dnsDiscovery struct {
+	backendName string
+	name        string
+	port        int
+	scheme      string
+	interval    time.Duration
+	lb          healthcheck.BalancerHandler
+	healthCheck *healthcheck.BackendConfig
+	lookup      serverLookup
+	metrics     metrics.Registry
+
+	staticServers map[string]bool
+	// discovered holds the weights of the servers of the last resolution, by URL.
+	discovered map[string]int
+	// nextRefresh is the delay before the next resolution.
+	nextRefresh time.Duration
+	// initialized is closed once the first resolution is done.
+	initialized chan struct{}
+}
+
+// buildDNSDiscovery creates the DNS discovery of a backend, and resolves its servers for the first time.
+// The servers no longer discovered are also forgotten by the health check of the backend, if any.
+func (s *Server) buildDNSDiscovery(lb healthcheck.BalancerHandler, healthCheck *healthcheck.BackendConfig, backendName string, backend *types.Backend) (*dnsDiscovery, error) {
+	config := backend.DNSDiscovery
+	if config.Name == "" {
+		return nil, errors.New("missing name")
+	}
+
+	interval := defaultDNSDiscoveryInterval
+	if config.Interval != "" {
+		var err error
+		interval, err = time.ParseDuration(config.Interval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid interval %q: %v", config.Interval, err)
+		}
+		if interval < minDNSDiscoveryInterval {
+			return nil, fmt.Errorf("invalid interval %q, must be at least %s", config.Interval, minDNSDiscoveryInterval)
+		}
+	}
+
+	scheme := config.Scheme
+	if scheme == "" {
+		scheme = "http"
+	}
+
+	resolver := buildHostResolver(s.globalConfiguration)
+	if resolver == nil {
+		resolver = &hostresolver.Resolver{}
+	}
+
+	d := &dnsDiscovery{
+		backendName:   backendName,
+		name:          config.Name,
+		port:          config.Port,
+		scheme:        scheme,
+		interval:      interval,
+		lb:            lb,
+		healthCheck:   healthCheck,
+		lookup:        resolver,
+		metrics:       s.metricsRegistry,
+		staticServers: make(map[string]bool),
+		discovered:    make(map[string]int),
+		initialized:   make(chan struct{}),
+	}
+
+	for _, srv := range backend.Servers {
+		if u, err := url.Parse(srv.URL); err == nil {
+			d.staticServers[u.String()] = true
+		}
+	}
+
+	d.initialize()
+
+	return d, nil
+}
+
+// initialize resolves the servers for the first time, waiting for the resolution at most dnsDiscoveryInitialTimeout.
+func (d *dnsDiscovery) initialize() {
+	safe.Go(func() {
+		defer close(d.initialized)
+		d.refresh()
+	})
+
+	select {
+	case <-d.initialized:
+	case <-time.After(dnsDiscoveryInitialTimeout):
+		log.Warnf("Discovering the servers of backend %s from %s takes more than %s, they are added once resolved", d.backendName, d.name, dnsDiscoveryInitialTimeout)
+	}
+}
+
+// refresh resolves the servers and updates the load balancer, a failed resolution keeps the current servers.
+func (d *dnsDiscovery) refresh() {
+	addresses, ttl, err := d.resolve()
+	if err == nil && len(addresses) == 0 {
+		err = errors.New("no records")
+	}
+	if err != nil {
+		log.Errorf("Failed to discover the servers of backend %s from %s: %v", d.backendName, d.name, err)
+		d.nextRefresh = d.interval
+		return
+	}
+
+	// The records are resolved again when they expire, but not more often than the minimum interval.
+	d.nextRefresh = d.interval
+	if ttl < d.nextRefresh {
+		d.nextRefresh = ttl
+	}
+	if d.nextRefresh < minDNSDiscoveryInterval {
+		d.nextRefresh = minDNSDiscoveryInterval
+	}
+
+	discovered := make(map[string]int)
+	for _, address := range addresses {
+		u := &url.URL{Scheme: d.scheme, Host: net.JoinHostPort(address.Host, strconv.Itoa(address.Port))}
+
+		weight := address.Weight
+		if weight <= 0 {
+			weight = 1
+		}
+		discovered[u.String()] = weight
+	}
+
+	current := make(map[string]*url.URL)
+	for _, u := range d.lb.Servers() {
+		current[u.String()] = u
+	}
+
+	for rawURL, weight := range discovered {
+		previousWeight, known := d.discovered[rawURL]
+		_, enabled := current[rawURL]
+
+		// The servers removed by the health check are left to it.
+		if known && (!enabled || previousWeight == weight) {
+			continue
+		}
+
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			log.Errorf("Invalid server URL %s discovered for backend %s: %v", rawURL, d.backendName, err)
+			continue
+		}
+
+		log.Debugf("Upserting server %s discovered for backend %s with weight %d", rawURL, d.backendName, weight)
+		if err := d.lb.UpsertServer(u, roundrobin.Weight(weight)); err != nil {
+			log.Errorf("Failed to add server %s to the load balancer of backend %s: %v", rawURL, d.backendName, err)
+			continue
+		}
+
+		if !known {
+			d.metrics.BackendServerUpGauge().With("backend", d.backendName, "url", rawURL).Set(1)
+		}
+	}
+
+	for rawURL, u := range current {
+		if _, ok := discovered[rawURL]; ok || d.staticServers[rawURL] {
+			continue
+		}
+
+		log.Debugf("Removing server %s no longer discovered for backend %s", rawURL, d.backendName)
+		if err := d.lb.RemoveServer(u); err != nil {
+			log.Errorf("Failed to remove server %s from the load balancer of backend %s: %v", rawURL, d.backendName, err)
+		}
+	}
+
+	// The servers removed by the health check must not be returned to the load balancer once healthy.
+	if d.healthCheck != nil {
+		for rawURL := range d.discovered {
+			if _, ok := discovered[rawURL]; ok || d.staticServers[rawURL] {
+				continue
+			}
+
+			if u, err := url.Parse(rawURL); err == nil {
+				d.healthCheck.ForgetServer(u)
+			}
+		}
+	}
+
+	d.discovered = discovered
+}
+
+func (d *dnsDiscovery) resolve() ([]hostresolver.Address, time.Duration, error) {
+	if d.port > 0 {
+		return d.lookup.LookupHost(d.name, d.port)
+	}
+	return d.lookup.LookupSRV(d.name)
+}
+
+func (d *dnsDiscovery) run(ctx context.Context) {
+	select {
+	case <-ctx.Done():
+		return
+	case <-d.initialized:
+	}
+
+	timer := time.NewTimer(d.nextRefresh)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Debugf("Stopping DNS discovery of backend %s", d.backendName)
+			return
+		case <-timer.C:
+			d.refresh()
+			timer.Reset(d.nextRefresh)
+		}
+	}
+}
+
+// startDNSDiscoveries starts the DNS discoveries of the last loaded configuration, and stops the previous ones.
+func (s *Server) startDNSDiscoveries(parentCtx context.Context) {
+	if s.dnsDiscoveriesCancel != nil {
+		s.dnsDiscoveriesCancel()
+	}
+
+	ctx, cancel := context.WithCancel(parentCtx)
+	s.dnsDiscoveriesCancel = cancel
+
+	for _, discovery := range s.dnsDiscoveries {
+		currentDiscovery := discovery
+		safe.Go(func() {
+			currentDiscovery.run(ctx)
+		})
+	}
+	s.dnsDiscoveries = nil
+}
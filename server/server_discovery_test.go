@@ -0,0 +1,285 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pteich/traefik/configuration"
+	"github.com/pteich/traefik/healthcheck"
+	"github.com/pteich/traefik/hostresolver"
+	"github.com/pteich/traefik/metrics"
+	"github.com/pteich/traefik/testhelpers"
+	"github.com/pteich/traefik/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+func TestDNSDiscoveryRefresh(t *testing.T) {
+	lb, err := roundrobin.New(http.NotFoundHandler())
+	require.NoError(t, err)
+
+	err = lb.UpsertServer(testhelpers.MustParseURL("http://10.0.0.100:80"))
+	require.NoError(t, err)
+
+	lookup := &fakeServerLookup{}
+	discovery := &dnsDiscovery{
+		backendName:   "backend",
+		name:          "_http._tcp.app.example.com",
+		scheme:        "http",
+		interval:      time.Minute,
+		lb:            lb,
+		lookup:        lookup,
+		metrics:       metrics.NewVoidRegistry(),
+		staticServers: map[string]bool{"http://10.0.0.100:80": true},
+		discovered:    make(map[string]int),
+	}
+
+	lookup.addresses = []hostresolver.Address{
+		{Host: "10.0.0.1", Port: 8080, Weight: 2},
+		{Host: "10.0.0.2", Port: 8080},
+	}
+	lookup.ttl = 10 * time.Second
+	discovery.refresh()
+
+	assert.Equal(t, []string{"http://10.0.0.100:80", "http://10.0.0.1:8080", "http://10.0.0.2:8080"}, lbServers(lb))
+	weight, _ := lb.ServerWeight(testhelpers.MustParseURL("http://10.0.0.1:8080"))
+	assert.Equal(t, 2, weight)
+	weight, _ = lb.ServerWeight(testhelpers.MustParseURL("http://10.0.0.2:8080"))
+	assert.Equal(t, 1, weight)
+	assert.Equal(t, 10*time.Second, discovery.nextRefresh)
+
+	// A server removed by the health check is not added back.
+	err = lb.RemoveServer(testhelpers.MustParseURL("http://10.0.0.2:8080"))
+	require.NoError(t, err)
+
+	lookup.addresses = []hostresolver.Address{
+		{Host: "10.0.0.2", Port: 8080},
+		{Host: "10.0.0.3", Port: 8080},
+	}
+	lookup.ttl = 0
+	discovery.refresh()
+
+	assert.Equal(t, []string{"http://10.0.0.100:80", "http://10.0.0.3:8080"}, lbServers(lb))
+	assert.Equal(t, minDNSDiscoveryInterval, discovery.nextRefresh)
+
+	// A failed resolution keeps the servers.
+	lookup.err = errors.New("timeout")
+	discovery.refresh()
+
+	assert.Equal(t, []string{"http://10.0.0.100:80", "http://10.0.0.3:8080"}, lbServers(lb))
+	assert.Equal(t, time.Minute, discovery.nextRefresh)
+}
+
+func TestDNSDiscoveryForgetsDroppedServers(t *testing.T) {
+	status := http.StatusServiceUnavailable
+	var mutex sync.Mutex
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		rw.WriteHeader(status)
+	}))
+	defer ts.Close()
+
+	healthy := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}))
+	defer healthy.Close()
+
+	lb, err := roundrobin.New(http.NotFoundHandler())
+	require.NoError(t, err)
+
+	healthCheck := healthcheck.NewBackendConfig(healthcheck.Options{Path: "/health", Interval: 20 * time.Millisecond, LB: lb}, "backend")
+
+	lookup := &fakeServerLookup{addresses: []hostresolver.Address{serverAddress(t, ts.URL)}, ttl: time.Minute}
+	discovery := &dnsDiscovery{
+		backendName:   "backend",
+		name:          "_http._tcp.app.example.com",
+		scheme:        "http",
+		interval:      time.Minute,
+		lb:            lb,
+		healthCheck:   healthCheck,
+		lookup:        lookup,
+		metrics:       metrics.NewVoidRegistry(),
+		staticServers: make(map[string]bool),
+		discovered:    make(map[string]int),
+	}
+	discovery.refresh()
+	require.Equal(t, []string{ts.URL}, lbServers(lb))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	healthcheck.GetHealthCheck(metrics.NewVoidRegistry()).SetBackendsConfiguration(ctx, map[string]*healthcheck.BackendConfig{"backend": healthCheck})
+
+	// The health check removes the server, which is then no longer discovered.
+	require.Eventually(t, func() bool {
+		return len(lb.Servers()) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	lookup.addresses = []hostresolver.Address{serverAddress(t, healthy.URL)}
+	discovery.refresh()
+
+	mutex.Lock()
+	status = http.StatusOK
+	mutex.Unlock()
+
+	// The health check doesn't return the server once healthy.
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, []string{healthy.URL}, lbServers(lb))
+	for _, serverStatus := range healthCheck.Statuses() {
+		assert.NotEqual(t, ts.URL, serverStatus.URL)
+	}
+}
+
+func TestDNSDiscoveryInitializeTimeout(t *testing.T) {
+	lb, err := roundrobin.New(http.NotFoundHandler())
+	require.NoError(t, err)
+
+	lookup := &fakeServerLookup{
+		addresses: []hostresolver.Address{{Host: "10.0.0.1", Port: 8080}},
+		ttl:       time.Minute,
+		block:     make(chan struct{}),
+	}
+	discovery := &dnsDiscovery{
+		backendName:   "backend",
+		name:          "app.example.com",
+		scheme:        "http",
+		interval:      time.Minute,
+		lb:            lb,
+		lookup:        lookup,
+		metrics:       metrics.NewVoidRegistry(),
+		staticServers: make(map[string]bool),
+		discovered:    make(map[string]int),
+		initialized:   make(chan struct{}),
+	}
+
+	// A slow resolution doesn't block the loading of the configuration, its servers are added once resolved.
+	start := time.Now()
+	discovery.initialize()
+	assert.True(t, time.Since(start) < 2*dnsDiscoveryInitialTimeout)
+	assert.Empty(t, lb.Servers())
+
+	close(lookup.block)
+	select {
+	case <-discovery.initialized:
+	case <-time.After(time.Second):
+		t.Fatal("first resolution not done")
+	}
+	assert.Equal(t, []string{"http://10.0.0.1:8080"}, lbServers(lb))
+}
+
+func TestBuildDNSDiscovery(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		dnsDiscovery     *types.DNSDiscovery
+		expectedInterval time.Duration
+		expectedScheme   string
+		expectedErr      bool
+	}{
+		{
+			desc:             "default values",
+			dnsDiscovery:     &types.DNSDiscovery{Name: "app.example.com", Port: 8080},
+			expectedInterval: defaultDNSDiscoveryInterval,
+			expectedScheme:   "http",
+		},
+		{
+			desc:             "all values",
+			dnsDiscovery:     &types.DNSDiscovery{Name: "_https._tcp.app.example.com", Scheme: "https", Interval: "10s"},
+			expectedInterval: 10 * time.Second,
+			expectedScheme:   "https",
+		},
+		{
+			desc:         "missing name",
+			dnsDiscovery: &types.DNSDiscovery{Port: 8080},
+			expectedErr:  true,
+		},
+		{
+			desc:         "invalid interval",
+			dnsDiscovery: &types.DNSDiscovery{Name: "app.example.com", Interval: "foo"},
+			expectedErr:  true,
+		},
+		{
+			desc:         "interval too small",
+			dnsDiscovery: &types.DNSDiscovery{Name: "app.example.com", Interval: "100ms"},
+			expectedErr:  true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// The resolver configuration file doesn't exist, the resolutions fail.
+			srv := &Server{
+				globalConfiguration: configuration.GlobalConfiguration{
+					HostResolver: &configuration.HostResolverConfig{ResolvConfig: "/etc/resolv.oops"},
+				},
+				metricsRegistry: metrics.NewVoidRegistry(),
+			}
+
+			lb, err := roundrobin.New(http.NotFoundHandler())
+			require.NoError(t, err)
+
+			discovery, err := srv.buildDNSDiscovery(lb, nil, "backend", &types.Backend{DNSDiscovery: test.dnsDiscovery})
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedInterval, discovery.interval)
+			assert.Equal(t, test.expectedScheme, discovery.scheme)
+			assert.Equal(t, test.expectedInterval, discovery.nextRefresh)
+			assert.Empty(t, lb.Servers())
+		})
+	}
+}
+
+type fakeServerLookup struct {
+	addresses []hostresolver.Address
+	ttl       time.Duration
+	err       error
+	// block, when set, blocks the resolutions until it is closed.
+	block chan struct{}
+}
+
+func (f *fakeServerLookup) LookupSRV(name string) ([]hostresolver.Address, time.Duration, error) {
+	if f.block != nil {
+		<-f.block
+	}
+	return f.addresses, f.ttl, f.err
+}
+
+func (f *fakeServerLookup) LookupHost(name string, port int) ([]hostresolver.Address, time.Duration, error) {
+	if f.block != nil {
+		<-f.block
+	}
+	return f.addresses, f.ttl, f.err
+}
+
+func serverAddress(t *testing.T, rawURL string) hostresolver.Address {
+	t.Helper()
+
+	host, rawPort, err := net.SplitHostPort(testhelpers.MustParseURL(rawURL).Host)
+	require.NoError(t, err)
+	port, err := strconv.Atoi(rawPort)
+	require.NoError(t, err)
+
+	return hostresolver.Address{Host: host, Port: port}
+}
+
+func lbServers(lb *roundrobin.RoundRobin) []string {
+	var servers []string
+	for _, u := range lb.Servers() {
+		servers = append(servers, u.String())
+	}
+	sort.Strings(servers)
+	return servers
+}
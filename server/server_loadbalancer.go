@@ -8,8 +8,13 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"regexp"
+	"strings"
 	"time"
 
+	"github.com/containous/flaeg"
+	"github.com/containous/flaeg/parse"
+	"github.com/pteich/traefik/balancer"
 	"github.com/pteich/traefik/configuration"
 	"github.com/pteich/traefik/healthcheck"
 	"github.com/pteich/traefik/log"
@@ -26,6 +31,33 @@ import (
 	"golang.org/x/net/http2"
 )
 
+// Default values of the passive health check.
+const (
+	defaultPassiveHealthCheckConsecutiveErrors  = 5
+	defaultPassiveHealthCheckEjectionTime       = 30 * time.Second
+	defaultPassiveHealthCheckMaxEjectionPercent = 50
+)
+
+// Default values of the retry policy.
+const (
+	// defaultRetryMaxIntervalFactor is the factor of the initial interval giving the default max interval of the backoff.
+	defaultRetryMaxIntervalFactor = 10
+)
+
+// defaultMaxConnQueueTimeout is the default maximum time a request waits for a connection when the maximum connections are reached.
+const defaultMaxConnQueueTimeout = time.Second
+
+// Default values of the adaptive concurrency limit.
+const (
+	defaultAdaptiveConcurrencyInitialLimit     = 20
+	defaultAdaptiveConcurrencyMinLimit         = 1
+	defaultAdaptiveConcurrencyMaxLimit         = 1000
+	defaultAdaptiveConcurrencyLatencyThreshold = time.Second
+)
+
+// defaultRetryMethods are the idempotent methods, retried by default on the status codes of the retry policy.
+var defaultRetryMethods = []string{http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete}
+
 type h2cTransportWrapper struct {
 	*http2.Transport
 }
@@ -35,51 +67,137 @@ func (t *h2cTransportWrapper) RoundTrip(req *http.Request) (*http.Response, erro
 	return t.Transport.RoundTrip(req)
 }
 
-func (s *Server) buildBalancerMiddlewares(frontendName string, frontend *types.Frontend, backend *types.Backend, fwd http.Handler) (http.Handler, *healthcheck.BackendConfig, error) {
-	balancer, err := s.buildLoadBalancer(frontendName, frontend.Backend, backend, fwd)
+// buildBalancerMiddlewares builds the load balancer of the backend and its middlewares.
+// The mirror backends are built without the access log and the retries: they only apply to the requests of the client.
+func (s *Server) buildBalancerMiddlewares(frontendName string, frontend *types.Frontend, backendName string, backend *types.Backend, fwd http.Handler, roundTripper http.RoundTripper, mirror bool) (http.Handler, *healthcheck.BackendConfig, error) {
+	wrapWithAccessLog := s.wrapHTTPHandlerWithAccessLog
+	accessLogEnabled := s.accessLoggerMiddleware != nil && !mirror
+	if !accessLogEnabled {
+		wrapWithAccessLog = func(handler http.Handler, _ string) http.Handler { return handler }
+	}
+
+	// Passive Health Check
+	var passiveHealthCheck *healthcheck.PassiveHealthCheck
+	if phcOpts := buildPassiveHealthCheckOptions(backendName, backend.PassiveHealthCheck); phcOpts != nil {
+		log.Debugf("Setting up backend passive health check %s", *phcOpts)
+
+		passiveHealthCheck = healthcheck.NewPassiveHealthCheck(fwd, *phcOpts, backendName, s.metricsRegistry)
+		fwd = passiveHealthCheck
+	}
+
+	// The retries and the hedged requests avoid the servers already tried
+	retryEnabled := (s.globalConfiguration.Retry != nil || backend.Retry != nil) && !mirror
+	avoidTriedServers := retryEnabled || backend.Hedging != nil
+	if avoidTriedServers {
+		fwd = middlewares.NewRetryServerFilter(fwd)
+	}
+
+	// The access log records the frontend and the backend of the requests forwarded by the load balancer, and of their retries
+	next := fwd
+	if accessLogEnabled {
+		saveUsername := accesslog.NewSaveUsername(fwd)
+		saveBackend := accesslog.NewSaveBackend(saveUsername, backendName)
+		next = accesslog.NewSaveFrontend(saveBackend, frontendName)
+	}
+
+	balancer, err := s.buildLoadBalancer(frontendName, backendName, backend, next)
 	if err != nil {
 		return nil, nil, err
 	}
 
+	if passiveHealthCheck != nil {
+		passiveHealthCheck.SetBalancer(balancer)
+	}
+
 	// Health Check
 	var backendHealthCheck *healthcheck.BackendConfig
-	if hcOpts := buildHealthCheckOptions(balancer, frontend.Backend, backend.HealthCheck, s.globalConfiguration.HealthCheck); hcOpts != nil {
+	if hcOpts := buildHealthCheckOptions(balancer, backendName, backend.HealthCheck, s.globalConfiguration.HealthCheck); hcOpts != nil {
 		log.Debugf("Setting up backend health check %s", *hcOpts)
 
-		hcOpts.Transport = s.defaultForwardingRoundTripper
-		backendHealthCheck = healthcheck.NewBackendConfig(*hcOpts, frontend.Backend)
+		// The servers are checked with the transport of the backend, as they are reached by the forwarder.
+		hcOpts.Transport = roundTripper
+		if smartRt, ok := roundTripper.(*smartRoundTripper); ok {
+			hcOpts.TLSConfig = smartRt.GetTLSClientConfig()
+		}
+		backendHealthCheck = healthcheck.NewBackendConfig(*hcOpts, backendName)
+	} else {
+		// The backends without active health check are registered for the status of their servers
+		backendHealthCheck = healthcheck.NewUncheckedBackendConfig(balancer, backendName)
+	}
+
+	if passiveHealthCheck != nil {
+		passiveHealthCheck.SetBackendConfig(backendHealthCheck)
+	}
+
+	// DNS Discovery
+	if backend.DNSDiscovery != nil {
+		discovery, err := s.buildDNSDiscovery(balancer, backendHealthCheck, backendName, backend)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error creating DNS discovery for frontend %s: %v", frontendName, err)
+		}
+
+		log.Debugf("Discovering the servers of backend %s from %s", backendName, backend.DNSDiscovery.Name)
+		s.dnsDiscoveries = append(s.dnsDiscoveries, discovery)
 	}
 
 	// Empty (backend with no servers)
-	var lb http.Handler = middlewares.NewEmptyBackendHandler(balancer)
+	var lb http.Handler
+	var retryServerBalancer *middlewares.RetryServerBalancer
+	if avoidTriedServers {
+		retryServerBalancer = middlewares.NewRetryServerBalancer(balancer, next)
+		lb = middlewares.NewEmptyBackendHandler(retryServerBalancer)
+	} else {
+		lb = middlewares.NewEmptyBackendHandler(balancer)
+	}
 
-	// Rate Limit
-	if frontend.RateLimit != nil && len(frontend.RateLimit.RateSet) > 0 {
-		handler, err := buildRateLimiter(lb, frontend.RateLimit)
+	// Hedging
+	if backend.Hedging != nil {
+		hedgingOpts, err := buildHedgingOptions(backend.Hedging)
 		if err != nil {
-			return nil, nil, fmt.Errorf("error creating rate limiter: %v", err)
+			return nil, nil, fmt.Errorf("error creating hedging: %v", err)
+		}
+
+		// The concurrent attempts have their own access log data
+		if accessLogEnabled {
+			hedgingOpts.AttemptContext = accesslog.WithAttemptLogData
 		}
 
-		lb = s.wrapHTTPHandlerWithAccessLog(
-			s.tracingMiddleware.NewHTTPHandlerWrapper("Rate limit", handler, false),
-			fmt.Sprintf("rate limit for %s", frontendName),
-		)
+		log.Debugf("Setting up backend hedging %s", hedgingOpts)
+		lb = middlewares.NewHedging(lb, retryServerBalancer, hedgingOpts, backendName, s.metricsRegistry)
 	}
 
 	// Max Connections
 	if backend.MaxConn != nil && backend.MaxConn.Amount != 0 {
 		log.Debugf("Creating load-balancer connection limit")
 
-		handler, err := buildMaxConn(lb, backend.MaxConn)
+		handler, err := buildMaxConn(lb, backend.MaxConn, frontend.Priority)
 		if err != nil {
 			return nil, nil, err
 		}
-		lb = s.wrapHTTPHandlerWithAccessLog(handler, fmt.Sprintf("connection limit for %s", frontendName))
+		lb = wrapWithAccessLog(handler, fmt.Sprintf("connection limit for %s", frontendName))
+	}
+
+	// Adaptive Concurrency
+	if backend.AdaptiveConcurrency != nil {
+		acOpts, err := buildAdaptiveConcurrencyOptions(backend.AdaptiveConcurrency)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error creating adaptive concurrency limit: %v", err)
+		}
+
+		log.Debugf("Setting up backend adaptive concurrency limit %s", acOpts)
+		handler, err := middlewares.NewAdaptiveConcurrencyLimiter(lb, acOpts, backendName, s.metricsRegistry)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error creating adaptive concurrency limit: %v", err)
+		}
+		lb = wrapWithAccessLog(handler, fmt.Sprintf("adaptive concurrency limit for %s", frontendName))
 	}
 
 	// Retry
-	if s.globalConfiguration.Retry != nil {
-		handler := s.buildRetryMiddleware(lb, s.globalConfiguration.Retry, len(backend.Servers), frontend.Backend)
+	if retryEnabled {
+		handler, err := s.buildRetryMiddleware(lb, s.globalConfiguration.Retry, backend.Retry, len(balancer.Servers()), backendName)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error creating retry: %v", err)
+		}
 		lb = s.tracingMiddleware.NewHTTPHandlerWrapper("Retry", handler, false)
 	}
 
@@ -110,77 +228,170 @@ func (s *Server) buildBalancerMiddlewares(frontendName string, frontend *types.F
 	return lb, backendHealthCheck, nil
 }
 
-func (s *Server) buildLoadBalancer(frontendName string, backendName string, backend *types.Backend, fwd http.Handler) (healthcheck.BalancerHandler, error) {
-	var rr *roundrobin.RoundRobin
-	var saveFrontend http.Handler
-
-	if s.accessLoggerMiddleware != nil {
-		saveUsername := accesslog.NewSaveUsername(fwd)
-		saveBackend := accesslog.NewSaveBackend(saveUsername, backendName)
-		saveFrontend = accesslog.NewSaveFrontend(saveBackend, frontendName)
-		rr, _ = roundrobin.New(saveFrontend)
-	} else {
-		rr, _ = roundrobin.New(fwd)
-	}
-
+func (s *Server) buildLoadBalancer(frontendName string, backendName string, backend *types.Backend, next http.Handler) (healthcheck.BalancerHandler, error) {
 	lbMethod, err := types.NewLoadBalancerMethod(backend.LoadBalancer)
 	if err != nil {
 		return nil, fmt.Errorf("error loading load balancer method '%+v' for frontend %s: %v", backend.LoadBalancer, frontendName, err)
 	}
 
+	slowStart := s.getSlowStart(lbMethod, backendName, backend)
+
 	var lb healthcheck.BalancerHandler
+	if hasServerPriorities(backend) {
+		log.Debug("Creating load-balancer priority groups")
+
+		lb = balancer.NewPriority(func() (balancer.Balancer, error) {
+			return newLoadBalancer(lbMethod, backendName, backend, next, slowStart)
+		})
+	} else {
+		lb, err = newLoadBalancer(lbMethod, backendName, backend, next, slowStart)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.configureLBServers(lb, backend, backendName); err != nil {
+		return nil, fmt.Errorf("error configuring load balancer for frontend %s: %v", frontendName, err)
+	}
+
+	return lb, nil
+}
+
+func newLoadBalancer(lbMethod types.LoadBalancerMethod, backendName string, backend *types.Backend, next http.Handler, slowStart *slowStart) (healthcheck.BalancerHandler, error) {
+	var stickySession *roundrobin.StickySession
+	if backend.LoadBalancer != nil && backend.LoadBalancer.Stickiness != nil {
+		cookieName := cookie.GetName(backend.LoadBalancer.Stickiness.CookieName, backendName)
+		stickySession = newStickySession(cookieName, backend.LoadBalancer.Stickiness)
+	}
 
 	switch lbMethod {
 	case types.Drr:
 		log.Debug("Creating load-balancer drr")
 
-		if backend.LoadBalancer.Stickiness != nil {
-			cookieName := cookie.GetName(backend.LoadBalancer.Stickiness.CookieName, backendName)
+		rr, err := roundrobin.New(next)
+		if err != nil {
+			return nil, err
+		}
 
-			stickySession := newStickySession(cookieName, backend.LoadBalancer.Stickiness)
+		var options []roundrobin.RebalancerOption
+		if stickySession != nil {
+			options = append(options, roundrobin.RebalancerStickySession(stickySession))
+		}
 
-			lb, err = roundrobin.NewRebalancer(rr, roundrobin.RebalancerStickySession(stickySession))
-			if err != nil {
-				return nil, err
-			}
-		} else {
-			lb, err = roundrobin.NewRebalancer(rr)
-			if err != nil {
-				return nil, err
-			}
+		if slowStart != nil {
+			// The rebalancer adjusts the weights through the slow start, so they are ramped up too.
+			return roundrobin.NewRebalancer(balancer.NewSlowStart(rr, slowStart.window, slowStart.starts), options...)
 		}
+		return roundrobin.NewRebalancer(rr, options...)
 	case types.Wrr:
 		log.Debug("Creating load-balancer wrr")
 
-		if backend.LoadBalancer.Stickiness != nil {
-			cookieName := cookie.GetName(backend.LoadBalancer.Stickiness.CookieName, backendName)
+		var options []roundrobin.LBOption
+		if stickySession != nil {
+			options = append(options, roundrobin.EnableStickySession(stickySession))
+		}
 
-			stickySession := newStickySession(cookieName, backend.LoadBalancer.Stickiness)
+		rr, err := roundrobin.New(next, options...)
+		if err != nil {
+			return nil, err
+		}
 
-			option := roundrobin.EnableStickySession(stickySession)
-			if s.accessLoggerMiddleware != nil {
-				lb, err = roundrobin.New(saveFrontend, option)
-				if err != nil {
-					return nil, err
-				}
-			} else {
-				lb, err = roundrobin.New(fwd, option)
-				if err != nil {
-					return nil, err
-				}
-			}
-		} else {
-			lb = rr
+		if slowStart != nil {
+			return balancer.NewSlowStart(rr, slowStart.window, slowStart.starts), nil
 		}
+		return rr, nil
+	case types.LeastConn:
+		log.Debug("Creating load-balancer leastconn")
+
+		return balancer.NewLeastConn(next, stickySession), nil
+	case types.Hash:
+		log.Debugf("Creating load-balancer hash on %q", backend.LoadBalancer.HashKey)
+
+		return balancer.NewHash(next, backend.LoadBalancer.HashKey)
+	case types.PeakEwma:
+		log.Debug("Creating load-balancer peakewma")
+
+		return balancer.NewPeakEWMA(next, stickySession), nil
 	default:
 		return nil, fmt.Errorf("invalid load-balancing method %q", lbMethod)
 	}
+}
 
-	if err := s.configureLBServers(lb, backend, backendName); err != nil {
-		return nil, fmt.Errorf("error configuring load balancer for frontend %s: %v", frontendName, err)
+// slowStart holds the slow start configuration of a backend.
+type slowStart struct {
+	window time.Duration
+	starts *balancer.ServerStarts
+}
+
+// getSlowStart returns the slow start configuration of the backend, nil if it is disabled.
+func (s *Server) getSlowStart(lbMethod types.LoadBalancerMethod, backendName string, backend *types.Backend) *slowStart {
+	if backend.LoadBalancer == nil || len(backend.LoadBalancer.SlowStart) == 0 {
+		return nil
 	}
 
-	return lb, nil
+	window, err := time.ParseDuration(backend.LoadBalancer.SlowStart)
+	if err != nil {
+		log.Errorf("Illegal slow start window for backend '%s': %v", backendName, err)
+		return nil
+	}
+
+	if window <= 0 {
+		return nil
+	}
+
+	if lbMethod != types.Wrr && lbMethod != types.Drr {
+		log.Warnf("Slow start is not supported by the load-balancing method %s of backend '%s'", lbMethod, backendName)
+		return nil
+	}
+
+	if s.serverStarts == nil {
+		s.serverStarts = make(map[string]*balancer.ServerStarts)
+	}
+
+	starts, ok := s.serverStarts[backendName]
+	if !ok {
+		starts = balancer.NewServerStarts()
+		s.serverStarts[backendName] = starts
+	}
+
+	log.Debugf("Creating load-balancer slow start of %s", window)
+
+	return &slowStart{window: window, starts: starts}
+}
+
+// retainServerStarts forgets the servers which are no longer in the configurations, so they are ramped up again if they come back.
+func (s *Server) retainServerStarts(configurations types.Configurations) {
+	for backendName, starts := range s.serverStarts {
+		var urls []*url.URL
+		for _, config := range configurations {
+			backend, ok := config.Backends[backendName]
+			if !ok || backend.LoadBalancer == nil || len(backend.LoadBalancer.SlowStart) == 0 {
+				continue
+			}
+
+			for _, srv := range backend.Servers {
+				if u, err := url.Parse(srv.URL); err == nil {
+					urls = append(urls, u)
+				}
+			}
+		}
+
+		if len(urls) == 0 {
+			delete(s.serverStarts, backendName)
+			continue
+		}
+
+		starts.Retain(urls)
+	}
+}
+
+func hasServerPriorities(backend *types.Backend) bool {
+	for _, srv := range backend.Servers {
+		if srv.Priority != 0 {
+			return true
+		}
+	}
+	return false
 }
 
 func newStickySession(cookieName string, stickiness *types.Stickiness) *roundrobin.StickySession {
@@ -195,6 +406,23 @@ func newStickySession(cookieName string, stickiness *types.Stickiness) *roundrob
 	return roundrobin.NewStickySessionWithOptions(cookieName, opts)
 }
 
+func buildBackendsStickyCookie(frontendName string, stickiness *types.Stickiness) *http.Cookie {
+	if stickiness == nil {
+		return nil
+	}
+
+	cookieName := cookie.GetName(stickiness.CookieName, frontendName)
+	log.Debugf("Sticky backends with cookie %v", cookieName)
+
+	return &http.Cookie{
+		Name:     cookieName,
+		Path:     "/",
+		Secure:   stickiness.Secure,
+		HttpOnly: stickiness.HTTPOnly,
+		SameSite: convertSameSite(stickiness.SameSite),
+	}
+}
+
 func convertSameSite(sameSite string) http.SameSite {
 	switch sameSite {
 	case "none":
@@ -216,6 +444,10 @@ func (s *Server) configureLBServers(lb healthcheck.BalancerHandler, backend *typ
 
 		log.Debugf("Creating server %s at %s with weight %d", name, u, srv.Weight)
 
+		if priorityLB, ok := lb.(*balancer.Priority); ok {
+			priorityLB.SetServerPriority(u, srv.Priority)
+		}
+
 		if err := lb.UpsertServer(u, roundrobin.Weight(srv.Weight)); err != nil {
 			return fmt.Errorf("error adding server %s to load balancer: %v", srv.URL, err)
 		}
@@ -226,24 +458,40 @@ func (s *Server) configureLBServers(lb healthcheck.BalancerHandler, backend *typ
 }
 
 // getRoundTripper will either use server.defaultForwardingRoundTripper or create a new one
-// given a custom TLS configuration is passed and the passTLSCert option is set to true.
-func (s *Server) getRoundTripper(entryPointName string, passTLSCert bool, tls *traefiktls.TLS) (http.RoundTripper, error) {
-	if !passTLSCert {
+// given a custom TLS configuration is passed and the passTLSCert option is set to true,
+// or given the backend has its own transport configuration.
+func (s *Server) getRoundTripper(entryPointName string, passTLSCert bool, tls *traefiktls.TLS, backendTransport *types.Transport) (http.RoundTripper, error) {
+	if !passTLSCert && backendTransport == nil {
 		return s.defaultForwardingRoundTripper, nil
 	}
 
-	tlsConfig, err := createClientTLSConfig(entryPointName, tls)
+	transport, err := createBackendHTTPTransport(s.globalConfiguration, backendTransport)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create TLSClientConfig: %v", err)
+		return nil, fmt.Errorf("failed to create HTTP transport: %v", err)
 	}
-	tlsConfig.InsecureSkipVerify = s.globalConfiguration.InsecureSkipVerify
 
-	transport, err := createHTTPTransport(s.globalConfiguration)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create HTTP transport: %v", err)
+	if passTLSCert {
+		tlsConfig, err := createClientTLSConfig(entryPointName, tls)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create TLSClientConfig: %v", err)
+		}
+		tlsConfig.InsecureSkipVerify = s.globalConfiguration.InsecureSkipVerify
+
+		if transport.TLSClientConfig != nil {
+			tlsConfig.InsecureSkipVerify = transport.TLSClientConfig.InsecureSkipVerify
+			tlsConfig.ServerName = transport.TLSClientConfig.ServerName
+			tlsConfig.GetClientCertificate = transport.TLSClientConfig.GetClientCertificate
+			if transport.TLSClientConfig.RootCAs != nil {
+				tlsConfig.RootCAs = transport.TLSClientConfig.RootCAs
+			}
+		}
+
+		transport.TLSClientConfig = tlsConfig
 	}
 
-	transport.TLSClientConfig = tlsConfig
+	if backendTransport != nil && backendTransport.DisableHTTP2 {
+		return newHTTP1RoundTripper(transport), nil
+	}
 
 	smartTransport, err := newSmartRoundTripper(transport)
 	if err != nil {
@@ -270,8 +518,8 @@ func createHTTPTransport(globalConfiguration configuration.GlobalConfiguration)
 	}
 
 	transport := &http.Transport{
-		Proxy:                 http.ProxyFromEnvironment,
-		DialContext:           dialer.DialContext,
+		Proxy:                 proxyFromEnvironment,
+		DialContext:           dialUnixSocket(dialer.DialContext),
 		MaxIdleConnsPerHost:   globalConfiguration.MaxIdleConnsPerHost,
 		IdleConnTimeout:       90 * time.Second,
 		TLSHandshakeTimeout:   10 * time.Second,
@@ -287,6 +535,8 @@ func createHTTPTransport(globalConfiguration configuration.GlobalConfiguration)
 		},
 	})
 
+	transport.RegisterProtocol(unixScheme, &unixSocketTransportWrapper{Transport: transport})
+
 	if globalConfiguration.ForwardingTimeouts != nil {
 		transport.ResponseHeaderTimeout = time.Duration(globalConfiguration.ForwardingTimeouts.ResponseHeaderTimeout)
 	}
@@ -301,6 +551,87 @@ func createHTTPTransport(globalConfiguration configuration.GlobalConfiguration)
 	return transport, nil
 }
 
+// createBackendHTTPTransport creates an http.Transport configured with the GlobalConfiguration settings,
+// overridden by the transport configuration of the backend.
+func createBackendHTTPTransport(globalConfiguration configuration.GlobalConfiguration, backendTransport *types.Transport) (*http.Transport, error) {
+	if backendTransport == nil {
+		return createHTTPTransport(globalConfiguration)
+	}
+
+	forwardingTimeouts := &configuration.ForwardingTimeouts{}
+	if globalConfiguration.ForwardingTimeouts != nil {
+		*forwardingTimeouts = *globalConfiguration.ForwardingTimeouts
+	} else {
+		forwardingTimeouts.DialTimeout = flaeg.Duration(configuration.DefaultDialTimeout)
+	}
+
+	if err := parseOptionalDuration(backendTransport.DialTimeout, (*parse.Duration)(&forwardingTimeouts.DialTimeout)); err != nil {
+		return nil, fmt.Errorf("invalid dial timeout: %v", err)
+	}
+
+	if err := parseOptionalDuration(backendTransport.ResponseHeaderTimeout, (*parse.Duration)(&forwardingTimeouts.ResponseHeaderTimeout)); err != nil {
+		return nil, fmt.Errorf("invalid response header timeout: %v", err)
+	}
+
+	var idleConnTimeout parse.Duration
+	if err := parseOptionalDuration(backendTransport.IdleConnTimeout, &idleConnTimeout); err != nil {
+		return nil, fmt.Errorf("invalid idle connection timeout: %v", err)
+	}
+
+	// The global configuration is a copy, it can be overridden.
+	globalConfiguration.ForwardingTimeouts = forwardingTimeouts
+
+	if backendTransport.MaxIdleConnsPerHost != 0 {
+		globalConfiguration.MaxIdleConnsPerHost = backendTransport.MaxIdleConnsPerHost
+	}
+
+	if backendTransport.InsecureSkipVerify != nil {
+		globalConfiguration.InsecureSkipVerify = *backendTransport.InsecureSkipVerify
+	}
+
+	if len(backendTransport.RootCAs) > 0 {
+		globalConfiguration.RootCAs = backendTransport.RootCAs
+	}
+
+	transport, err := createHTTPTransport(globalConfiguration)
+	if err != nil {
+		return nil, err
+	}
+
+	if idleConnTimeout != 0 {
+		transport.IdleConnTimeout = time.Duration(idleConnTimeout)
+	}
+
+	if len(backendTransport.ServerName) > 0 {
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{}
+		}
+		transport.TLSClientConfig.ServerName = backendTransport.ServerName
+	}
+
+	if len(backendTransport.ClientCert) > 0 || len(backendTransport.ClientKey) > 0 {
+		clientCertificate, err := traefiktls.NewClientCertificate(backendTransport.ClientCert, backendTransport.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate: %v", err)
+		}
+
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{}
+		}
+		transport.TLSClientConfig.GetClientCertificate = clientCertificate.GetClientCertificate
+	}
+
+	return transport, nil
+}
+
+// parseOptionalDuration parses the value into the duration, if it is set.
+func parseOptionalDuration(value string, duration *parse.Duration) error {
+	if len(value) == 0 {
+		return nil
+	}
+	return duration.Set(value)
+}
+
 func createRootCACertPool(rootCAs traefiktls.FilesOrContents) *x509.CertPool {
 	if len(rootCAs) == 0 {
 		return nil
@@ -356,7 +687,7 @@ func createClientTLSConfig(entryPointName string, tlsOption *traefiktls.TLS) (*t
 	return config, nil
 }
 
-func (s *Server) buildRetryMiddleware(handler http.Handler, retry *configuration.Retry, countServers int, backendName string) http.Handler {
+func (s *Server) buildRetryMiddleware(handler http.Handler, retry *configuration.Retry, backendRetry *types.Retry, countServers int, backendName string) (http.Handler, error) {
 	retryListeners := middlewares.RetryListeners{}
 	if s.metricsRegistry.IsEnabled() {
 		retryListeners = append(retryListeners, middlewares.NewMetricsRetryListener(s.metricsRegistry, backendName))
@@ -366,13 +697,64 @@ func (s *Server) buildRetryMiddleware(handler http.Handler, retry *configuration
 	}
 
 	retryAttempts := countServers
-	if retry.Attempts > 0 {
+	if backendRetry != nil && backendRetry.Attempts > 0 {
+		retryAttempts = backendRetry.Attempts
+	} else if retry != nil && retry.Attempts > 0 {
 		retryAttempts = retry.Attempts
 	}
 
+	policy, err := buildRetryPolicy(backendRetry)
+	if err != nil {
+		return nil, err
+	}
+
 	log.Debugf("Creating retries max attempts %d", retryAttempts)
 
-	return middlewares.NewRetry(retryAttempts, handler, retryListeners)
+	return middlewares.NewRetryWithPolicy(retryAttempts, policy, handler, retryListeners), nil
+}
+
+// buildRetryPolicy creates the retry policy of a backend, with the default values for the settings not set.
+func buildRetryPolicy(backendRetry *types.Retry) (middlewares.RetryPolicy, error) {
+	policy := middlewares.RetryPolicy{}
+	if backendRetry == nil {
+		return policy, nil
+	}
+
+	statusCodes, err := types.NewHTTPCodeRanges(backendRetry.StatusCodes)
+	if err != nil {
+		return policy, fmt.Errorf("invalid status codes: %v", err)
+	}
+	policy.StatusCodes = statusCodes
+
+	policy.Methods = backendRetry.Methods
+	if len(policy.StatusCodes) > 0 && len(policy.Methods) == 0 {
+		policy.Methods = defaultRetryMethods
+	}
+
+	for _, duration := range []struct {
+		name  string
+		value string
+		dest  *time.Duration
+	}{
+		{name: "initial interval", value: backendRetry.InitialInterval, dest: &policy.InitialInterval},
+		{name: "max interval", value: backendRetry.MaxInterval, dest: &policy.MaxInterval},
+		{name: "per try timeout", value: backendRetry.PerTryTimeout, dest: &policy.PerTryTimeout},
+	} {
+		if err := parseOptionalDuration(duration.value, (*parse.Duration)(duration.dest)); err != nil {
+			return policy, fmt.Errorf("invalid %s: %v", duration.name, err)
+		}
+	}
+
+	if policy.InitialInterval > 0 && policy.MaxInterval == 0 {
+		policy.MaxInterval = defaultRetryMaxIntervalFactor * policy.InitialInterval
+	}
+
+	if backendRetry.BudgetPercent < 0 || backendRetry.BudgetPercent > 100 {
+		return policy, fmt.Errorf("invalid budget percent: %d", backendRetry.BudgetPercent)
+	}
+	policy.BudgetPercent = backendRetry.BudgetPercent
+
+	return policy, nil
 }
 
 func buildRateLimiter(handler http.Handler, rlConfig *types.RateLimit) (http.Handler, error) {
@@ -408,12 +790,33 @@ func buildBufferingMiddleware(handler http.Handler, config *types.Buffering) (ht
 	)
 }
 
-func buildMaxConn(lb http.Handler, maxConns *types.MaxConn) (http.Handler, error) {
+func buildMaxConn(lb http.Handler, maxConns *types.MaxConn, priority int) (http.Handler, error) {
 	extractFunc, err := utils.NewExtractor(maxConns.ExtractorFunc)
 	if err != nil {
 		return nil, fmt.Errorf("error creating connection limit: %v", err)
 	}
 
+	if maxConns.QueueLength > 0 {
+		options := middlewares.MaxConnQueueOptions{
+			Length:         maxConns.QueueLength,
+			Timeout:        defaultMaxConnQueueTimeout,
+			PriorityHeader: maxConns.PriorityHeader,
+			Priority:       priority,
+		}
+
+		if maxConns.QueueTimeout != "" {
+			timeout, err := time.ParseDuration(maxConns.QueueTimeout)
+			if err != nil {
+				return nil, fmt.Errorf("error creating connection limit: invalid queue timeout %q: %v", maxConns.QueueTimeout, err)
+			}
+			options.Timeout = timeout
+		}
+
+		log.Debugf("Creating load-balancer connection limit with queue %s", options)
+
+		return middlewares.NewMaxConnQueue(lb, extractFunc, maxConns.Amount, options), nil
+	}
+
 	log.Debugf("Creating load-balancer connection limit")
 
 	handler, err := connlimit.New(lb, extractFunc, maxConns.Amount)
@@ -425,7 +828,19 @@ func buildMaxConn(lb http.Handler, maxConns *types.MaxConn) (http.Handler, error
 }
 
 func buildHealthCheckOptions(lb healthcheck.BalancerHandler, backend string, hc *types.HealthCheck, hcConfig *configuration.HealthCheckConfig) *healthcheck.Options {
-	if hc == nil || hc.Path == "" || hcConfig == nil {
+	if hc == nil || hcConfig == nil {
+		return nil
+	}
+
+	hcType := strings.ToLower(hc.Type)
+	switch hcType {
+	case "", healthcheck.TypeHTTP:
+		if hc.Path == "" {
+			return nil
+		}
+	case healthcheck.TypeTCP, healthcheck.TypeGRPC:
+	default:
+		log.Errorf("Unknown health check type %q for backend '%s'", hc.Type, backend)
 		return nil
 	}
 
@@ -441,13 +856,147 @@ func buildHealthCheckOptions(lb healthcheck.BalancerHandler, backend string, hc
 		}
 	}
 
+	var timeout time.Duration
+	if hc.Timeout != "" {
+		timeoutOverride, err := time.ParseDuration(hc.Timeout)
+		if err != nil {
+			log.Errorf("Illegal health check timeout for backend '%s': %s", backend, err)
+		} else if timeoutOverride <= 0 {
+			log.Errorf("Health check timeout smaller than zero for backend '%s'", backend)
+		} else {
+			timeout = timeoutOverride
+		}
+	}
+
+	var statusCodes types.HTTPCodeRanges
+	if len(hc.StatusCodes) > 0 {
+		var err error
+		statusCodes, err = types.NewHTTPCodeRanges(hc.StatusCodes)
+		if err != nil {
+			log.Errorf("Illegal health check status codes for backend '%s': %s", backend, err)
+		}
+	}
+
+	var bodyRegex *regexp.Regexp
+	if hc.BodyRegex != "" {
+		var err error
+		bodyRegex, err = regexp.Compile(hc.BodyRegex)
+		if err != nil {
+			log.Errorf("Illegal health check body regex for backend '%s': %s", backend, err)
+		}
+	}
+
 	return &healthcheck.Options{
-		Scheme:   hc.Scheme,
-		Path:     hc.Path,
-		Port:     hc.Port,
-		Interval: interval,
-		LB:       lb,
-		Hostname: hc.Hostname,
-		Headers:  hc.Headers,
+		Type:        hcType,
+		Service:     hc.Service,
+		Scheme:      hc.Scheme,
+		Path:        hc.Path,
+		Port:        hc.Port,
+		Interval:    interval,
+		LB:          lb,
+		Hostname:    hc.Hostname,
+		Headers:     hc.Headers,
+		Method:      strings.ToUpper(hc.Method),
+		StatusCodes: statusCodes,
+		BodyRegex:   bodyRegex,
+		Timeout:     timeout,
+		Rise:        hc.Rise,
+		Fall:        hc.Fall,
 	}
 }
+
+func buildHedgingOptions(hedging *types.Hedging) (middlewares.HedgingOptions, error) {
+	var options middlewares.HedgingOptions
+
+	if hedging.Delay != "" {
+		delay, err := time.ParseDuration(hedging.Delay)
+		if err != nil {
+			return options, fmt.Errorf("invalid delay %q: %v", hedging.Delay, err)
+		}
+		options.Delay = delay
+	}
+
+	if hedging.Percentile < 0 || hedging.Percentile >= 100 {
+		return options, fmt.Errorf("invalid percentile %d, must be between 0 and 99", hedging.Percentile)
+	}
+	options.Percentile = float64(hedging.Percentile)
+
+	if options.Delay <= 0 && options.Percentile == 0 {
+		return options, errors.New("a delay or a percentile is required")
+	}
+
+	return options, nil
+}
+
+func buildAdaptiveConcurrencyOptions(ac *types.AdaptiveConcurrency) (middlewares.AdaptiveConcurrencyOptions, error) {
+	options := middlewares.AdaptiveConcurrencyOptions{
+		Algorithm:        middlewares.ConcurrencyGradient,
+		InitialLimit:     defaultAdaptiveConcurrencyInitialLimit,
+		MinLimit:         defaultAdaptiveConcurrencyMinLimit,
+		MaxLimit:         defaultAdaptiveConcurrencyMaxLimit,
+		LatencyThreshold: defaultAdaptiveConcurrencyLatencyThreshold,
+	}
+
+	if ac.Algorithm != "" {
+		options.Algorithm = strings.ToLower(ac.Algorithm)
+	}
+
+	if ac.MinLimit > 0 {
+		options.MinLimit = ac.MinLimit
+	}
+
+	if ac.MaxLimit > 0 {
+		options.MaxLimit = ac.MaxLimit
+	}
+
+	if ac.InitialLimit > 0 {
+		options.InitialLimit = ac.InitialLimit
+	}
+
+	if options.MaxLimit < options.MinLimit {
+		return options, fmt.Errorf("max limit %d smaller than min limit %d", options.MaxLimit, options.MinLimit)
+	}
+
+	if ac.LatencyThreshold != "" {
+		threshold, err := time.ParseDuration(ac.LatencyThreshold)
+		if err != nil {
+			return options, fmt.Errorf("invalid latency threshold %q: %v", ac.LatencyThreshold, err)
+		}
+		options.LatencyThreshold = threshold
+	}
+
+	return options, nil
+}
+
+func buildPassiveHealthCheckOptions(backend string, phc *types.PassiveHealthCheck) *healthcheck.PassiveOptions {
+	if phc == nil {
+		return nil
+	}
+
+	options := &healthcheck.PassiveOptions{
+		ConsecutiveErrors:  defaultPassiveHealthCheckConsecutiveErrors,
+		EjectionTime:       defaultPassiveHealthCheckEjectionTime,
+		MaxEjectionPercent: defaultPassiveHealthCheckMaxEjectionPercent,
+	}
+
+	if phc.ConsecutiveErrors > 0 {
+		options.ConsecutiveErrors = phc.ConsecutiveErrors
+	}
+
+	if phc.EjectionTime != "" {
+		ejectionTime, err := time.ParseDuration(phc.EjectionTime)
+		if err != nil {
+			log.Errorf("Illegal passive health check ejection time for backend '%s': %s", backend, err)
+		} else if ejectionTime <= 0 {
+			log.Errorf("Passive health check ejection time smaller than zero for backend '%s'", backend)
+		} else {
+			options.EjectionTime = ejectionTime
+		}
+	}
+
+	if phc.MaxEjectionPercent > 0 && phc.MaxEjectionPercent <= 100 {
+		options.MaxEjectionPercent = phc.MaxEjectionPercent
+	}
+
+	return options
+}
@@ -1,10 +1,30 @@
 package server
 
 import (
+	"context"
+	"crypto/tls"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/containous/flaeg"
+	"github.com/pteich/traefik/configuration"
+	"github.com/pteich/traefik/healthcheck"
+	"github.com/pteich/traefik/middlewares"
+	"github.com/pteich/traefik/middlewares/accesslog"
+	"github.com/pteich/traefik/testhelpers"
+	traefiktls "github.com/pteich/traefik/tls"
 	"github.com/pteich/traefik/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vulcand/oxy/connlimit"
+	"github.com/vulcand/oxy/forward"
+	"github.com/vulcand/oxy/roundrobin"
 )
 
 func TestConfigureBackends(t *testing.T) {
@@ -79,3 +99,652 @@ func TestConfigureBackends(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateBackendHTTPTransport(t *testing.T) {
+	globalConfig := configuration.GlobalConfiguration{
+		MaxIdleConnsPerHost: 200,
+		ForwardingTimeouts: &configuration.ForwardingTimeouts{
+			DialTimeout:           flaeg.Duration(30 * time.Second),
+			ResponseHeaderTimeout: flaeg.Duration(10 * time.Second),
+		},
+	}
+
+	testCases := []struct {
+		desc                          string
+		transport                     *types.Transport
+		expectedResponseHeaderTimeout time.Duration
+		expectedIdleConnTimeout       time.Duration
+		expectedMaxIdleConnsPerHost   int
+		expectedServerName            string
+		expectedInsecureSkipVerify    bool
+		expectedClientCertificate     bool
+		expectedErr                   bool
+	}{
+		{
+			desc:                          "without backend transport",
+			expectedResponseHeaderTimeout: 10 * time.Second,
+			expectedIdleConnTimeout:       90 * time.Second,
+			expectedMaxIdleConnsPerHost:   200,
+		},
+		{
+			desc:                          "empty backend transport",
+			transport:                     &types.Transport{},
+			expectedResponseHeaderTimeout: 10 * time.Second,
+			expectedIdleConnTimeout:       90 * time.Second,
+			expectedMaxIdleConnsPerHost:   200,
+		},
+		{
+			desc: "backend transport overriding the global configuration",
+			transport: &types.Transport{
+				DialTimeout:           "5s",
+				ResponseHeaderTimeout: "1m",
+				IdleConnTimeout:       "10s",
+				MaxIdleConnsPerHost:   10,
+				ServerName:            "backend.example.com",
+				InsecureSkipVerify:    boolPtr(true),
+			},
+			expectedResponseHeaderTimeout: time.Minute,
+			expectedIdleConnTimeout:       10 * time.Second,
+			expectedMaxIdleConnsPerHost:   10,
+			expectedServerName:            "backend.example.com",
+			expectedInsecureSkipVerify:    true,
+		},
+		{
+			desc: "backend transport with a client certificate",
+			transport: &types.Transport{
+				ClientCert: localhostCert,
+				ClientKey:  localhostKey,
+			},
+			expectedResponseHeaderTimeout: 10 * time.Second,
+			expectedIdleConnTimeout:       90 * time.Second,
+			expectedMaxIdleConnsPerHost:   200,
+			expectedClientCertificate:     true,
+		},
+		{
+			desc: "invalid client certificate",
+			transport: &types.Transport{
+				ClientCert: localhostCert,
+				ClientKey:  "invalid",
+			},
+			expectedErr: true,
+		},
+		{
+			desc: "unparseable timeout",
+			transport: &types.Transport{
+				DialTimeout: "unparseable",
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			transport, err := createBackendHTTPTransport(globalConfig, test.transport)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedResponseHeaderTimeout, transport.ResponseHeaderTimeout)
+			assert.Equal(t, test.expectedIdleConnTimeout, transport.IdleConnTimeout)
+			assert.Equal(t, test.expectedMaxIdleConnsPerHost, transport.MaxIdleConnsPerHost)
+
+			if len(test.expectedServerName) == 0 && !test.expectedInsecureSkipVerify && !test.expectedClientCertificate {
+				assert.Nil(t, transport.TLSClientConfig)
+				return
+			}
+			require.NotNil(t, transport.TLSClientConfig)
+			assert.Equal(t, test.expectedServerName, transport.TLSClientConfig.ServerName)
+			assert.Equal(t, test.expectedInsecureSkipVerify, transport.TLSClientConfig.InsecureSkipVerify)
+
+			if !test.expectedClientCertificate {
+				assert.Nil(t, transport.TLSClientConfig.GetClientCertificate)
+				return
+			}
+			require.NotNil(t, transport.TLSClientConfig.GetClientCertificate)
+			certificate, err := transport.TLSClientConfig.GetClientCertificate(nil)
+			require.NoError(t, err)
+			assert.NotEmpty(t, certificate.Certificate)
+		})
+	}
+
+	// The global configuration is not altered.
+	assert.Equal(t, flaeg.Duration(30*time.Second), globalConfig.ForwardingTimeouts.DialTimeout)
+	assert.Equal(t, 200, globalConfig.MaxIdleConnsPerHost)
+}
+
+func TestCreateBackendHTTPTransportInsecureSkipVerify(t *testing.T) {
+	globalConfig := configuration.GlobalConfiguration{InsecureSkipVerify: true}
+
+	transport, err := createBackendHTTPTransport(globalConfig, &types.Transport{})
+	require.NoError(t, err)
+	require.NotNil(t, transport.TLSClientConfig)
+	assert.True(t, transport.TLSClientConfig.InsecureSkipVerify)
+
+	// The backend checks the certificates of its servers, even if the global configuration doesn't.
+	transport, err = createBackendHTTPTransport(globalConfig, &types.Transport{InsecureSkipVerify: boolPtr(false)})
+	require.NoError(t, err)
+	if transport.TLSClientConfig != nil {
+		assert.False(t, transport.TLSClientConfig.InsecureSkipVerify)
+	}
+}
+
+func TestUnixSocketBackend(t *testing.T) {
+	dir, err := ioutil.TempDir("", "traefik-unix-socket")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	socketPath := filepath.Join(dir, "app.sock")
+	listener, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+
+	backend := &http.Server{Handler: http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("X-Host", req.Host)
+		rw.Write([]byte(req.URL.RequestURI()))
+	})}
+	go backend.Serve(listener)
+	defer backend.Close()
+
+	transport, err := createHTTPTransport(configuration.GlobalConfiguration{})
+	require.NoError(t, err)
+
+	roundTripper, err := newSmartRoundTripper(transport)
+	require.NoError(t, err)
+
+	fwd, err := forward.New(forward.RoundTripper(roundTripper))
+	require.NoError(t, err)
+
+	lb, err := roundrobin.New(newUnixSocketHandler(fwd))
+	require.NoError(t, err)
+
+	err = lb.UpsertServer(testhelpers.MustParseURL("unix://" + socketPath))
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	lb.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://frontend/foo?bar=baz", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "/foo?bar=baz", recorder.Body.String())
+	assert.Equal(t, "localhost", recorder.Header().Get("X-Host"))
+}
+
+func TestGetRoundTripperBackendTransport(t *testing.T) {
+	server := NewServer(configuration.GlobalConfiguration{}, nil, nil)
+
+	roundTripper, err := server.getRoundTripper("http", false, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, server.defaultForwardingRoundTripper, roundTripper)
+
+	roundTripper, err = server.getRoundTripper("http", false, nil, &types.Transport{DisableHTTP2: true})
+	require.NoError(t, err)
+
+	smartTransport, ok := roundTripper.(*smartRoundTripper)
+	require.True(t, ok)
+	assert.Same(t, smartTransport.http, smartTransport.http2)
+	assert.NotNil(t, smartTransport.http.TLSNextProto)
+	assert.Empty(t, smartTransport.http.TLSNextProto)
+
+	roundTripper, err = server.getRoundTripper("http", false, nil, &types.Transport{})
+	require.NoError(t, err)
+
+	smartTransport, ok = roundTripper.(*smartRoundTripper)
+	require.True(t, ok)
+	assert.NotSame(t, smartTransport.http, smartTransport.http2)
+	assert.NotEmpty(t, smartTransport.http2.TLSNextProto)
+}
+
+func TestGetRoundTripperClientCertificate(t *testing.T) {
+	backend := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if len(req.TLS.PeerCertificates) == 0 {
+			rw.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	cert, err := tls.X509KeyPair([]byte(localhostCert), []byte(localhostKey))
+	require.NoError(t, err)
+	backend.TLS = &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   tls.RequireAnyClientCert,
+	}
+	backend.StartTLS()
+	defer backend.Close()
+
+	server := NewServer(configuration.GlobalConfiguration{}, nil, nil)
+
+	roundTripper, err := server.getRoundTripper("http", false, nil, &types.Transport{
+		RootCAs:    traefiktls.FilesOrContents{localhostCert},
+		ClientCert: localhostCert,
+		ClientKey:  localhostKey,
+	})
+	require.NoError(t, err)
+
+	resp, err := roundTripper.RoundTrip(httptest.NewRequest(http.MethodGet, backend.URL, nil))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// Without client certificate, the TLS handshake is rejected by the backend.
+	roundTripper, err = server.getRoundTripper("http", false, nil, &types.Transport{
+		RootCAs: traefiktls.FilesOrContents{localhostCert},
+	})
+	require.NoError(t, err)
+
+	_, err = roundTripper.RoundTrip(httptest.NewRequest(http.MethodGet, backend.URL, nil))
+	assert.Error(t, err)
+}
+
+func TestBuildBalancerMiddlewaresHealthCheckClientCertificate(t *testing.T) {
+	backendServer := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if len(req.TLS.PeerCertificates) == 0 {
+			rw.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	cert, err := tls.X509KeyPair([]byte(localhostCert), []byte(localhostKey))
+	require.NoError(t, err)
+	backendServer.TLS = &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   tls.RequireAnyClientCert,
+	}
+	backendServer.StartTLS()
+	defer backendServer.Close()
+
+	server := NewServer(configuration.GlobalConfiguration{
+		HealthCheck: &configuration.HealthCheckConfig{Interval: flaeg.Duration(time.Second)},
+	}, nil, nil)
+
+	backend := &types.Backend{
+		Servers: map[string]types.Server{
+			"server": {URL: backendServer.URL, Weight: 1},
+		},
+		LoadBalancer: &types.LoadBalancer{Method: "wrr"},
+		HealthCheck: &types.HealthCheck{
+			Path:     "/health",
+			Interval: "50ms",
+		},
+		Transport: &types.Transport{
+			RootCAs:    traefiktls.FilesOrContents{localhostCert},
+			ClientCert: localhostCert,
+			ClientKey:  localhostKey,
+		},
+	}
+
+	roundTripper, err := server.getRoundTripper("http", false, nil, backend.Transport)
+	require.NoError(t, err)
+
+	_, healthCheck, err := server.buildBalancerMiddlewares("frontend", &types.Frontend{}, "backend", backend, http.NotFoundHandler(), roundTripper, false)
+	require.NoError(t, err)
+	require.NotNil(t, healthCheck)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	healthcheck.GetHealthCheck(server.metricsRegistry).SetBackendsConfiguration(ctx, map[string]*healthcheck.BackendConfig{"backend": healthCheck})
+
+	// The health check presents the client certificate of the backend, the server stays up.
+	require.Eventually(t, func() bool {
+		return len(healthCheck.Statuses()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	status := healthCheck.Statuses()[0]
+	assert.Equal(t, healthcheck.StateUp, status.State)
+	assert.Empty(t, status.LastError)
+}
+
+func TestBuildBalancerMiddlewaresMirror(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		mirror         bool
+		expectedLogged bool
+	}{
+		{
+			desc:           "backend",
+			expectedLogged: true,
+		},
+		{
+			desc:   "mirror without access log",
+			mirror: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			server := NewServer(configuration.GlobalConfiguration{
+				AccessLog: &types.AccessLog{FilePath: filepath.Join(t.TempDir(), "access.log"), Format: accesslog.CommonFormat},
+				Retry:     &configuration.Retry{},
+			}, nil, nil)
+
+			backend := &types.Backend{
+				Servers: map[string]types.Server{
+					"server": {URL: "http://127.0.0.1:8080"},
+				},
+				LoadBalancer: &types.LoadBalancer{Method: "wrr"},
+			}
+
+			fwd := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+			handler, _, err := server.buildBalancerMiddlewares("frontend", &types.Frontend{}, "backend", backend, fwd, http.DefaultTransport, test.mirror)
+			require.NoError(t, err)
+
+			table := &accesslog.LogData{Core: accesslog.CoreLogData{}}
+			req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+			handler.ServeHTTP(httptest.NewRecorder(), req.WithContext(context.WithValue(req.Context(), accesslog.DataTableKey, table)))
+
+			assert.Equal(t, test.expectedLogged, len(table.Core) > 0)
+		})
+	}
+}
+
+func TestBuildBalancerMiddlewaresHealthCheckTransport(t *testing.T) {
+	backendServer := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}))
+	cert, err := tls.X509KeyPair([]byte(localhostCert), []byte(localhostKey))
+	require.NoError(t, err)
+	backendServer.TLS = &tls.Config{Certificates: []tls.Certificate{cert}}
+	backendServer.StartTLS()
+	defer backendServer.Close()
+
+	testCases := []struct {
+		desc   string
+		hcType string
+	}{
+		{
+			desc:   "http",
+			hcType: healthcheck.TypeHTTP,
+		},
+		{
+			desc:   "tcp",
+			hcType: healthcheck.TypeTCP,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			server := NewServer(configuration.GlobalConfiguration{
+				HealthCheck: &configuration.HealthCheckConfig{Interval: flaeg.Duration(time.Second)},
+			}, nil, nil)
+
+			backend := &types.Backend{
+				Servers: map[string]types.Server{
+					"server": {URL: backendServer.URL, Weight: 1},
+				},
+				LoadBalancer: &types.LoadBalancer{Method: "wrr"},
+				HealthCheck: &types.HealthCheck{
+					Type:     test.hcType,
+					Path:     "/health",
+					Interval: "50ms",
+				},
+				Transport: &types.Transport{
+					ServerName:   "example.com",
+					RootCAs:      traefiktls.FilesOrContents{localhostCert},
+					DisableHTTP2: true,
+				},
+			}
+
+			roundTripper, err := server.getRoundTripper("http", false, nil, backend.Transport)
+			require.NoError(t, err)
+
+			_, healthCheck, err := server.buildBalancerMiddlewares("frontend", &types.Frontend{}, "backend", backend, http.NotFoundHandler(), roundTripper, false)
+			require.NoError(t, err)
+			require.NotNil(t, healthCheck)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			healthcheck.GetHealthCheck(server.metricsRegistry).SetBackendsConfiguration(ctx, map[string]*healthcheck.BackendConfig{"backend": healthCheck})
+
+			// The server certificate is checked with the server name and the root CAs of the backend transport.
+			require.Eventually(t, func() bool {
+				return len(healthCheck.Statuses()) == 1
+			}, 5*time.Second, 10*time.Millisecond)
+
+			status := healthCheck.Statuses()[0]
+			assert.Equal(t, healthcheck.StateUp, status.State)
+			assert.Empty(t, status.LastError)
+		})
+	}
+}
+
+func TestBuildRetryPolicy(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		retry          *types.Retry
+		expectedPolicy middlewares.RetryPolicy
+		expectedErr    bool
+	}{
+		{
+			desc:           "without backend retry",
+			expectedPolicy: middlewares.RetryPolicy{},
+		},
+		{
+			desc: "default methods and max interval",
+			retry: &types.Retry{
+				StatusCodes:     []string{"502-504"},
+				InitialInterval: "100ms",
+			},
+			expectedPolicy: middlewares.RetryPolicy{
+				StatusCodes:     types.HTTPCodeRanges{{502, 504}},
+				Methods:         []string{"GET", "HEAD", "OPTIONS", "TRACE", "PUT", "DELETE"},
+				InitialInterval: 100 * time.Millisecond,
+				MaxInterval:     time.Second,
+			},
+		},
+		{
+			desc: "custom values",
+			retry: &types.Retry{
+				StatusCodes:     []string{"503"},
+				Methods:         []string{"GET", "POST"},
+				InitialInterval: "10ms",
+				MaxInterval:     "50ms",
+				PerTryTimeout:   "2s",
+				BudgetPercent:   20,
+			},
+			expectedPolicy: middlewares.RetryPolicy{
+				StatusCodes:     types.HTTPCodeRanges{{503, 503}},
+				Methods:         []string{"GET", "POST"},
+				InitialInterval: 10 * time.Millisecond,
+				MaxInterval:     50 * time.Millisecond,
+				PerTryTimeout:   2 * time.Second,
+				BudgetPercent:   20,
+			},
+		},
+		{
+			desc:        "invalid status codes",
+			retry:       &types.Retry{StatusCodes: []string{"5xx"}},
+			expectedErr: true,
+		},
+		{
+			desc:        "unparseable per try timeout",
+			retry:       &types.Retry{PerTryTimeout: "unparseable"},
+			expectedErr: true,
+		},
+		{
+			desc:        "invalid budget percent",
+			retry:       &types.Retry{BudgetPercent: 120},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			policy, err := buildRetryPolicy(test.retry)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedPolicy, policy)
+		})
+	}
+}
+
+func TestBuildHedgingOptions(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		hedging         *types.Hedging
+		expectedOptions middlewares.HedgingOptions
+		expectedErr     bool
+	}{
+		{
+			desc:            "delay",
+			hedging:         &types.Hedging{Delay: "50ms"},
+			expectedOptions: middlewares.HedgingOptions{Delay: 50 * time.Millisecond},
+		},
+		{
+			desc:            "delay and percentile",
+			hedging:         &types.Hedging{Delay: "50ms", Percentile: 95},
+			expectedOptions: middlewares.HedgingOptions{Delay: 50 * time.Millisecond, Percentile: 95},
+		},
+		{
+			desc:            "percentile",
+			hedging:         &types.Hedging{Percentile: 90},
+			expectedOptions: middlewares.HedgingOptions{Percentile: 90},
+		},
+		{
+			desc:        "neither delay nor percentile",
+			hedging:     &types.Hedging{},
+			expectedErr: true,
+		},
+		{
+			desc:        "unparseable delay",
+			hedging:     &types.Hedging{Delay: "unparseable"},
+			expectedErr: true,
+		},
+		{
+			desc:        "invalid percentile",
+			hedging:     &types.Hedging{Percentile: 100},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			options, err := buildHedgingOptions(test.hedging)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedOptions, options)
+		})
+	}
+}
+
+func TestBuildAdaptiveConcurrencyOptions(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		ac              *types.AdaptiveConcurrency
+		expectedOptions middlewares.AdaptiveConcurrencyOptions
+		expectedErr     bool
+	}{
+		{
+			desc: "default values",
+			ac:   &types.AdaptiveConcurrency{},
+			expectedOptions: middlewares.AdaptiveConcurrencyOptions{
+				Algorithm:        middlewares.ConcurrencyGradient,
+				InitialLimit:     20,
+				MinLimit:         1,
+				MaxLimit:         1000,
+				LatencyThreshold: time.Second,
+			},
+		},
+		{
+			desc: "custom values",
+			ac: &types.AdaptiveConcurrency{
+				Algorithm:        "AIMD",
+				InitialLimit:     50,
+				MinLimit:         10,
+				MaxLimit:         200,
+				LatencyThreshold: "200ms",
+			},
+			expectedOptions: middlewares.AdaptiveConcurrencyOptions{
+				Algorithm:        middlewares.ConcurrencyAIMD,
+				InitialLimit:     50,
+				MinLimit:         10,
+				MaxLimit:         200,
+				LatencyThreshold: 200 * time.Millisecond,
+			},
+		},
+		{
+			desc:        "max limit smaller than min limit",
+			ac:          &types.AdaptiveConcurrency{MinLimit: 10, MaxLimit: 5},
+			expectedErr: true,
+		},
+		{
+			desc:        "unparseable latency threshold",
+			ac:          &types.AdaptiveConcurrency{LatencyThreshold: "unparseable"},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			options, err := buildAdaptiveConcurrencyOptions(test.ac)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedOptions, options)
+		})
+	}
+}
+
+func TestBuildMaxConnQueue(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		maxConn      *types.MaxConn
+		expectedType interface{}
+		expectedErr  bool
+	}{
+		{
+			desc:         "without queue",
+			maxConn:      &types.MaxConn{Amount: 10, ExtractorFunc: "request.host"},
+			expectedType: &connlimit.ConnLimiter{},
+		},
+		{
+			desc:         "with queue",
+			maxConn:      &types.MaxConn{Amount: 10, ExtractorFunc: "request.host", QueueLength: 100, QueueTimeout: "2s"},
+			expectedType: &middlewares.MaxConnQueue{},
+		},
+		{
+			desc:        "unparseable queue timeout",
+			maxConn:     &types.MaxConn{Amount: 10, ExtractorFunc: "request.host", QueueLength: 100, QueueTimeout: "unparseable"},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			handler, err := buildMaxConn(http.NotFoundHandler(), test.maxConn, 10)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.IsType(t, test.expectedType, handler)
+		})
+	}
+}
+
+func boolPtr(value bool) *bool {
+	return &value
+}
@@ -44,7 +44,8 @@ func (s *Server) buildMiddlewares(frontendName string, frontend *types.Frontend,
 	}
 
 	// Metrics
-	if s.metricsRegistry.IsEnabled() {
+	// When the traffic is split between several backends, metrics are collected per backend.
+	if s.metricsRegistry.IsEnabled() && len(frontend.Backends) == 0 {
 		handler := middlewares.NewBackendMetricsMiddleware(s.metricsRegistry, frontend.Backend)
 		middle = append(middle, handler)
 	}
@@ -222,7 +223,7 @@ func buildErrorPagesMiddleware(frontendName string, frontend *types.Frontend, ba
 	var errorPageHandlers []*errorpages.Handler
 
 	for errorPageName, errorPage := range frontend.Errors {
-		if frontend.Backend == errorPage.Backend {
+		if frontend.HasBackend(errorPage.Backend) {
 			log.Errorf("Error when creating error page %q for frontend %q: error pages backend %q is the same as backend for the frontend (infinite call risk).",
 				errorPageName, frontendName, errorPage.Backend)
 		} else if backends[errorPage.Backend] == nil {
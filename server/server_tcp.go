@@ -0,0 +1,158 @@
+package server
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/pteich/traefik/log"
+	"github.com/pteich/traefik/rules"
+	"github.com/pteich/traefik/safe"
+	"github.com/pteich/traefik/tcp"
+	"github.com/pteich/traefik/types"
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+func (s *Server) setupTCPServerEntryPoint(entryPointName string, newServerEntryPoint *serverEntryPoint) *serverEntryPoint {
+	entryPoint := s.entryPoints[entryPointName].Configuration
+	log.Infof("Preparing TCP server %s %+v", entryPointName, entryPoint)
+
+	tlsConfig, err := s.createTLSConfig(entryPointName, entryPoint.TLS, newServerEntryPoint.httpRouter)
+	if err != nil {
+		log.Fatalf("Error preparing TCP server: error creating TLS config: %v", err)
+	}
+
+	if tlsConfig != nil {
+		// The terminated connections are forwarded as is, no application protocol is negotiated.
+		tlsConfig.NextProtos = nil
+	}
+
+	listener, err := buildListener(entryPoint)
+	if err != nil {
+		log.Fatal("Error preparing TCP server: ", err)
+	}
+
+	serverEntryPoint := s.serverEntryPoints[entryPointName]
+	serverEntryPoint.listener = listener
+	serverEntryPoint.tcpTLSConfig = tlsConfig
+	serverEntryPoint.tcpRouter.GetHandler().SetTLSConfig(tlsConfig)
+	serverEntryPoint.hijackConnectionTracker = newHijackConnectionTracker()
+
+	return serverEntryPoint
+}
+
+func (s *Server) startTCPServer(serverEntryPoint *serverEntryPoint) {
+	log.Infof("Starting TCP server on %s", serverEntryPoint.listener.Addr())
+
+	for {
+		conn, err := serverEntryPoint.listener.Accept()
+		if err != nil {
+			if netErr, ok := err.(net.Error); ok && netErr.Temporary() {
+				log.Debugf("Temporary error while accepting TCP connection: %v", err)
+				continue
+			}
+
+			if !strings.Contains(err.Error(), "use of closed network connection") {
+				log.Error("Error accepting TCP connection: ", err)
+			}
+			return
+		}
+
+		writeCloser := tcpWriteCloser(conn)
+
+		serverEntryPoint.hijackConnectionTracker.AddHijackedConnection(conn)
+		safe.Go(func() {
+			defer serverEntryPoint.hijackConnectionTracker.RemoveHijackedConnection(conn)
+			serverEntryPoint.tcpRouter.ServeTCP(writeCloser)
+		})
+	}
+}
+
+// tcpWriteCloser returns the connection as a tcp.WriteCloser.
+// The connections hiding the raw one (e.g. proxy protocol) can't be half-closed, so they are fully closed instead.
+func tcpWriteCloser(conn net.Conn) tcp.WriteCloser {
+	if writeCloser, ok := conn.(tcp.WriteCloser); ok {
+		return writeCloser
+	}
+	return &closeWriteConn{Conn: conn}
+}
+
+type closeWriteConn struct {
+	net.Conn
+}
+
+func (c *closeWriteConn) CloseWrite() error {
+	return c.Close()
+}
+
+func (s *Server) loadTCPFrontendConfig(frontendName string, frontend *types.Frontend, backends map[string]*types.Backend, serverEntryPoint *serverEntryPoint) error {
+	if len(frontend.Backends) > 1 {
+		return fmt.Errorf("weighted backends are not supported by the TCP frontend %s", frontendName)
+	}
+
+	if len(frontend.Routes) == 0 {
+		return fmt.Errorf("no route defined for the TCP frontend %s", frontendName)
+	}
+
+	var serverNames []string
+	for routeName, route := range frontend.Routes {
+		names, err := rules.ParseHostSNI(route.Rule)
+		if err != nil {
+			return fmt.Errorf("error parsing route %s of the TCP frontend %s: %v", routeName, frontendName, err)
+		}
+		serverNames = append(serverNames, names...)
+	}
+
+	backendName := frontend.GetBackends()[0].Name
+
+	lb, err := s.buildTCPLoadBalancer(backendName, backends[backendName])
+	if err != nil {
+		return fmt.Errorf("failed to create the TCP load balancer for frontend %s: %v", frontendName, err)
+	}
+
+	router := serverEntryPoint.tcpRouter.GetHandler()
+	addRoute := router.AddRoute
+	if frontend.TLSPassthrough {
+		addRoute = router.AddPassthroughRoute
+	}
+
+	for _, serverName := range serverNames {
+		log.Debugf("Adding TCP route %s for frontend %s", serverName, frontendName)
+		if err := addRoute(serverName, lb); err != nil {
+			return fmt.Errorf("error adding TCP route for frontend %s: %v", frontendName, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) buildTCPLoadBalancer(backendName string, backend *types.Backend) (*tcp.LoadBalancer, error) {
+	log.Debugf("Creating TCP backend %s", backendName)
+
+	lb, err := tcp.NewLoadBalancer()
+	if err != nil {
+		return nil, err
+	}
+
+	for name, srv := range backend.Servers {
+		u, err := url.Parse(srv.URL)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing server URL %s: %v", srv.URL, err)
+		}
+
+		if len(u.Host) == 0 {
+			return nil, fmt.Errorf("missing address in server URL %s", srv.URL)
+		}
+
+		log.Debugf("Creating TCP server %s at %s with weight %d", name, u.Host, srv.Weight)
+
+		if err := lb.UpsertServer(u, roundrobin.Weight(srv.Weight)); err != nil {
+			return nil, fmt.Errorf("error adding server %s to load balancer: %v", srv.URL, err)
+		}
+
+		s.metricsRegistry.BackendServerUpGauge().With("backend", backendName, "url", srv.URL).Set(1)
+	}
+
+	return lb, nil
+}
@@ -283,6 +283,21 @@ func TestServerResponseEmptyBackend(t *testing.T) {
 			},
 			expectedStatusCode: http.StatusServiceUnavailable,
 		},
+		{
+			desc: "Empty Backend LB-LeastConn",
+			config: func(testServerURL string) *types.Configuration {
+				return th.BuildConfiguration(
+					th.WithFrontends(th.WithFrontend("backend",
+						th.WithEntryPoints("http"),
+						th.WithRoutes(th.WithRoute(requestPath, routeRule))),
+					),
+					th.WithBackends(th.WithBackendNew("backend",
+						th.WithLBMethod("leastconn")),
+					),
+				)
+			},
+			expectedStatusCode: http.StatusServiceUnavailable,
+		},
 		{
 			desc: "Empty Backend LB-Wrr",
 			config: func(testServerURL string) *types.Configuration {
@@ -0,0 +1,108 @@
+package server
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pteich/traefik/log"
+	"github.com/pteich/traefik/types"
+	"github.com/pteich/traefik/udp"
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+func (s *Server) setupUDPServerEntryPoint(entryPointName string) *serverEntryPoint {
+	entryPoint := s.entryPoints[entryPointName].Configuration
+	log.Infof("Preparing UDP server %s %+v", entryPointName, entryPoint)
+
+	conn, err := net.ListenPacket("udp", entryPoint.Address)
+	if err != nil {
+		log.Fatalf("Error preparing UDP server: error opening listener: %v", err)
+	}
+
+	var sessionTimeout time.Duration
+	if entryPoint.UDP != nil {
+		sessionTimeout = time.Duration(entryPoint.UDP.SessionTimeout)
+	}
+
+	serverEntryPoint := s.serverEntryPoints[entryPointName]
+	serverEntryPoint.udpForwarder = udp.NewForwarder(conn, sessionTimeout)
+	serverEntryPoint.udpForwarder.SetLoadBalancer(serverEntryPoint.udpLoadBalancer)
+
+	return serverEntryPoint
+}
+
+func (s *Server) startUDPServer(entryPointName string, serverEntryPoint *serverEntryPoint) {
+	log.Infof("Starting UDP server on %s", s.entryPoints[entryPointName].Configuration.Address)
+
+	err := serverEntryPoint.udpForwarder.Serve()
+	if err != nil && !strings.Contains(err.Error(), "use of closed network connection") {
+		log.Error("Error serving UDP: ", err)
+	}
+}
+
+func (s *Server) loadUDPFrontendConfig(frontendName string, frontend *types.Frontend, backends map[string]*types.Backend, serverEntryPoint *serverEntryPoint) error {
+	if len(frontend.Backends) > 1 {
+		return fmt.Errorf("weighted backends are not supported by the UDP frontend %s", frontendName)
+	}
+
+	if len(serverEntryPoint.udpFrontend) > 0 {
+		// The frontend kept would depend on the order of the configurations, so none of them is.
+		serverEntryPoint.udpLoadBalancer = nil
+		return fmt.Errorf("the frontends %s and %s are attached to the same UDP entry point, which forwards the datagrams of a single frontend", serverEntryPoint.udpFrontend, frontendName)
+	}
+	serverEntryPoint.udpFrontend = frontendName
+
+	if len(frontend.Routes) > 0 {
+		log.Debugf("Ignoring the routes of the UDP frontend %s", frontendName)
+	}
+
+	backendName := frontend.GetBackends()[0].Name
+
+	lb, err := s.buildUDPLoadBalancer(backendName, backends[backendName])
+	if err != nil {
+		return fmt.Errorf("failed to create the UDP load balancer for frontend %s: %v", frontendName, err)
+	}
+
+	serverEntryPoint.udpLoadBalancer = lb
+	return nil
+}
+
+func (s *Server) buildUDPLoadBalancer(backendName string, backend *types.Backend) (*udp.LoadBalancer, error) {
+	log.Debugf("Creating UDP backend %s", backendName)
+
+	lb, err := udp.NewLoadBalancer()
+	if err != nil {
+		return nil, err
+	}
+
+	for name, srv := range backend.Servers {
+		u, err := url.Parse(srv.URL)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing server URL %s: %v", srv.URL, err)
+		}
+
+		if len(u.Host) == 0 {
+			return nil, fmt.Errorf("missing address in server URL %s", srv.URL)
+		}
+
+		// The address is resolved once, the datagrams of the new sessions must not wait for a DNS resolution.
+		addr, err := net.ResolveUDPAddr("udp", u.Host)
+		if err != nil {
+			return nil, fmt.Errorf("error resolving server address %s: %v", u.Host, err)
+		}
+		u.Host = addr.String()
+
+		log.Debugf("Creating UDP server %s at %s with weight %d", name, u.Host, srv.Weight)
+
+		if err := lb.UpsertServer(u, roundrobin.Weight(srv.Weight)); err != nil {
+			return nil, fmt.Errorf("error adding server %s to load balancer: %v", srv.URL, err)
+		}
+
+		s.metricsRegistry.BackendServerUpGauge().With("backend", backendName, "url", srv.URL).Set(1)
+	}
+
+	return lb, nil
+}
@@ -0,0 +1,39 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/pteich/traefik/configuration"
+	"github.com/pteich/traefik/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadUDPFrontendConfig(t *testing.T) {
+	server := NewServer(configuration.GlobalConfiguration{}, nil, nil)
+
+	backends := map[string]*types.Backend{
+		"backend": {
+			Servers: map[string]types.Server{
+				"server": {URL: "udp://localhost:53", Weight: 1},
+			},
+		},
+	}
+	frontend := &types.Frontend{Backend: "backend"}
+
+	serverEntryPoint := &serverEntryPoint{}
+	require.NoError(t, server.loadUDPFrontendConfig("frontend1", frontend, backends, serverEntryPoint))
+	require.NotNil(t, serverEntryPoint.udpLoadBalancer)
+
+	// The server address is resolved when the load balancer is built.
+	address, err := serverEntryPoint.udpLoadBalancer.NextAddress()
+	require.NoError(t, err)
+	assert.Contains(t, []string{"127.0.0.1:53", "[::1]:53"}, address)
+
+	// Several frontends on the same entry point are rejected, whatever their order.
+	assert.Error(t, server.loadUDPFrontendConfig("frontend2", frontend, backends, serverEntryPoint))
+	assert.Nil(t, serverEntryPoint.udpLoadBalancer)
+
+	assert.Error(t, server.loadUDPFrontendConfig("frontend3", frontend, backends, serverEntryPoint))
+	assert.Nil(t, serverEntryPoint.udpLoadBalancer)
+}
@@ -22,6 +22,17 @@ func newSmartRoundTripper(transport *http.Transport) (http.RoundTripper, error)
 	}, nil
 }
 
+// newHTTP1RoundTripper creates a smartRoundTripper never using HTTP/2.
+func newHTTP1RoundTripper(transport *http.Transport) http.RoundTripper {
+	// A non-nil empty map disables HTTP/2.
+	transport.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
+
+	return &smartRoundTripper{
+		http2: transport,
+		http:  transport,
+	}
+}
+
 // smartRoundTripper implements RoundTrip while making sure that HTTP/2 is not used
 // with protocols that start with a Connection Upgrade, such as SPDY or Websocket.
 type smartRoundTripper struct {
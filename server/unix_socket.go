@@ -0,0 +1,76 @@
+package server
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// unixScheme is the scheme of the servers listening on a unix domain socket, e.g. unix:///run/app.sock.
+const unixScheme = "unix"
+
+// newUnixSocketHandler rewrites the URL of the requests to the unix domain socket servers before forwarding them:
+// the socket path, given as URL path by the server, becomes the URL host, as expected by the transport.
+func newUnixSocketHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Scheme == unixScheme {
+			// The URL is shared by all the requests to the server, it is replaced instead of modified.
+			req.URL = &url.URL{Scheme: unixScheme, Host: req.URL.Path}
+		}
+
+		next.ServeHTTP(rw, req)
+	})
+}
+
+// unixSocketTransportWrapper sends the requests to the unix domain socket servers as plain HTTP,
+// the transport dialing the socket given as host.
+type unixSocketTransportWrapper struct {
+	*http.Transport
+}
+
+func (t *unixSocketTransportWrapper) RoundTrip(req *http.Request) (*http.Response, error) {
+	outReq := req.WithContext(req.Context())
+
+	u := *req.URL
+	u.Scheme = "http"
+	outReq.URL = &u
+
+	// The socket path is not a valid Host header.
+	if outReq.Host == "" || outReq.Host == req.URL.Host {
+		outReq.Host = "localhost"
+	}
+
+	return t.Transport.RoundTrip(outReq)
+}
+
+// isUnixSocketAddress returns whether the host (with or without port) of a request is a unix domain socket path.
+func isUnixSocketAddress(addr string) bool {
+	return strings.HasPrefix(addr, "/")
+}
+
+// dialUnixSocket returns a dial function dialing the unix domain sockets,
+// and the other addresses with the given dial function.
+func dialUnixSocket(dial func(ctx context.Context, network, addr string) (net.Conn, error)) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if isUnixSocketAddress(addr) {
+			// The transport always adds a port to the address.
+			if i := strings.LastIndex(addr, ":"); i > 0 {
+				addr = addr[:i]
+			}
+			return dial(ctx, unixScheme, addr)
+		}
+
+		return dial(ctx, network, addr)
+	}
+}
+
+// proxyFromEnvironment is http.ProxyFromEnvironment, never proxying the requests to the unix domain sockets.
+func proxyFromEnvironment(req *http.Request) (*url.URL, error) {
+	if isUnixSocketAddress(req.URL.Host) {
+		return nil, nil
+	}
+
+	return http.ProxyFromEnvironment(req)
+}
@@ -0,0 +1,25 @@
+package tcp
+
+import (
+	"net"
+)
+
+// Handler is the TCP counterpart of http.Handler.
+type Handler interface {
+	ServeTCP(conn WriteCloser)
+}
+
+// HandlerFunc is an adapter allowing the use of ordinary functions as TCP handlers.
+type HandlerFunc func(conn WriteCloser)
+
+// ServeTCP calls f(conn).
+func (f HandlerFunc) ServeTCP(conn WriteCloser) {
+	f(conn)
+}
+
+// WriteCloser is a connection whose write side can be closed independently.
+type WriteCloser interface {
+	net.Conn
+	// CloseWrite closes the write side of the connection.
+	CloseWrite() error
+}
@@ -0,0 +1,37 @@
+package tcp
+
+import (
+	"net/http"
+
+	"github.com/pteich/traefik/log"
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+// LoadBalancer balances TCP connections between servers, using the weighted round robin of oxy.
+// The servers are identified by URLs whose host holds the address to dial (e.g. tcp://10.0.0.1:5432),
+// so the load balancer can be managed like the HTTP ones (e.g. by the health checks).
+type LoadBalancer struct {
+	*roundrobin.RoundRobin
+}
+
+// NewLoadBalancer creates a new LoadBalancer.
+func NewLoadBalancer() (*LoadBalancer, error) {
+	rr, err := roundrobin.New(http.NotFoundHandler())
+	if err != nil {
+		return nil, err
+	}
+
+	return &LoadBalancer{RoundRobin: rr}, nil
+}
+
+// ServeTCP forwards the connection to the next server.
+func (b *LoadBalancer) ServeTCP(conn WriteCloser) {
+	server, err := b.NextServer()
+	if err != nil {
+		log.Errorf("Error while getting the next TCP server: %v", err)
+		conn.Close()
+		return
+	}
+
+	NewProxy(server.Host).ServeTCP(conn)
+}
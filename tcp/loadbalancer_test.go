@@ -0,0 +1,81 @@
+package tcp
+
+import (
+	"io/ioutil"
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+func TestLoadBalancer(t *testing.T) {
+	lb, err := NewLoadBalancer()
+	require.NoError(t, err)
+
+	require.NoError(t, lb.UpsertServer(testServerURL(t, "foo"), roundrobin.Weight(1)))
+	require.NoError(t, lb.UpsertServer(testServerURL(t, "bar"), roundrobin.Weight(2)))
+
+	router := NewRouter()
+	require.NoError(t, router.AddRoute(CatchAllServerName, lb))
+
+	addr := serveRouter(t, router)
+
+	responses := map[string]int{}
+	for i := 0; i < 6; i++ {
+		conn, err := net.Dial("tcp", addr)
+		require.NoError(t, err)
+
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+		data, err := ioutil.ReadAll(conn)
+		require.NoError(t, err)
+		conn.Close()
+
+		responses[string(data)]++
+	}
+
+	assert.Equal(t, map[string]int{"foo": 2, "bar": 4}, responses)
+}
+
+func TestLoadBalancerNoServer(t *testing.T) {
+	lb, err := NewLoadBalancer()
+	require.NoError(t, err)
+
+	router := NewRouter()
+	require.NoError(t, router.AddRoute(CatchAllServerName, lb))
+
+	conn, err := net.Dial("tcp", serveRouter(t, router))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	data, err := ioutil.ReadAll(conn)
+	require.NoError(t, err)
+
+	assert.Empty(t, data)
+}
+
+// testServerURL starts a TCP server writing the response to every connection.
+func testServerURL(t *testing.T, response string) *url.URL {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Write([]byte(response))
+			conn.Close()
+		}
+	}()
+
+	return &url.URL{Scheme: "tcp", Host: listener.Addr().String()}
+}
@@ -0,0 +1,57 @@
+package tcp
+
+import (
+	"io"
+	"net"
+	"time"
+
+	"github.com/pteich/traefik/log"
+)
+
+const defaultDialTimeout = 30 * time.Second
+
+// Proxy forwards a TCP connection to a target address.
+type Proxy struct {
+	target      string
+	dialTimeout time.Duration
+}
+
+// NewProxy creates a new Proxy.
+func NewProxy(address string) *Proxy {
+	return &Proxy{target: address, dialTimeout: defaultDialTimeout}
+}
+
+// ServeTCP forwards the connection to the target and copies the data in both directions.
+func (p *Proxy) ServeTCP(conn WriteCloser) {
+	log.Debugf("Handling TCP connection from %s to %s", conn.RemoteAddr(), p.target)
+
+	defer conn.Close()
+
+	backendConn, err := net.DialTimeout("tcp", p.target, p.dialTimeout)
+	if err != nil {
+		log.Errorf("Error while connecting to TCP backend %s: %v", p.target, err)
+		return
+	}
+
+	defer backendConn.Close()
+
+	errChan := make(chan error)
+	go connCopy(conn, backendConn.(WriteCloser), errChan)
+	go connCopy(backendConn.(WriteCloser), conn, errChan)
+
+	err = <-errChan
+	if err != nil {
+		log.Debugf("Error during TCP connection to %s: %v", p.target, err)
+	}
+
+	<-errChan
+}
+
+func connCopy(dst, src WriteCloser, errCh chan error) {
+	_, err := io.Copy(dst, src)
+	errCh <- err
+
+	if errClose := dst.CloseWrite(); errClose != nil {
+		log.Debugf("Error while terminating TCP connection: %v", errClose)
+	}
+}
@@ -0,0 +1,224 @@
+package tcp
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/pteich/traefik/log"
+	"github.com/pteich/traefik/types"
+)
+
+// CatchAllServerName is the server name of the route matching all the connections, including the non-TLS ones.
+const CatchAllServerName = "*"
+
+const (
+	recordTypeHandshake = 0x16
+	recordHeaderLen     = 5
+	maxPlaintext        = 16384
+	// sniffTimeout is the time given to the client to speak first,
+	// the connection is handed to the catch-all route otherwise (e.g. the protocols where the server speaks first).
+	sniffTimeout       = time.Second
+	clientHelloTimeout = 10 * time.Second
+)
+
+var errSNISniffed = errors.New("server name sniffed")
+
+// Router routes the TCP connections, according to the server name (SNI) sent in the TLS ClientHello.
+// The ClientHello is only peeked: the TLS connections of the passthrough routes, or of all the routes without TLS configuration,
+// are passed through as is, otherwise the TLS connection is terminated before being handed to the route handler.
+type Router struct {
+	routes    map[string]*route
+	catchAll  *route
+	tlsConfig *tls.Config
+}
+
+type route struct {
+	handler     Handler
+	passthrough bool
+}
+
+// NewRouter creates a new Router.
+func NewRouter() *Router {
+	return &Router{routes: make(map[string]*route)}
+}
+
+// AddRoute adds a handler for the given server name, terminating the TLS connections if the router has a TLS configuration.
+func (r *Router) AddRoute(serverName string, handler Handler) error {
+	return r.addRoute(serverName, &route{handler: handler})
+}
+
+// AddPassthroughRoute adds a handler for the given server name, the TLS connections are passed through as is.
+func (r *Router) AddPassthroughRoute(serverName string, handler Handler) error {
+	return r.addRoute(serverName, &route{handler: handler, passthrough: true})
+}
+
+func (r *Router) addRoute(serverName string, rt *route) error {
+	if serverName == CatchAllServerName {
+		if r.catchAll != nil {
+			return fmt.Errorf("a route already matches %q", serverName)
+		}
+		r.catchAll = rt
+		return nil
+	}
+
+	serverName = types.CanonicalDomain(serverName)
+	if _, ok := r.routes[serverName]; ok {
+		return fmt.Errorf("a route already matches %q", serverName)
+	}
+
+	r.routes[serverName] = rt
+	return nil
+}
+
+// SetTLSConfig sets the TLS configuration used to terminate the TLS connections.
+func (r *Router) SetTLSConfig(config *tls.Config) {
+	r.tlsConfig = config
+}
+
+// ServeTCP routes the connection to the handler matching its server name.
+func (r *Router) ServeTCP(conn WriteCloser) {
+	// Some protocols expect the server to speak first, so the connection is only peeked if needed.
+	if len(r.routes) == 0 && !r.terminates(r.catchAll) {
+		if r.catchAll == nil {
+			conn.Close()
+			return
+		}
+
+		r.catchAll.handler.ServeTCP(conn)
+		return
+	}
+
+	if err := conn.SetReadDeadline(time.Now().Add(sniffTimeout)); err != nil {
+		log.Errorf("Error while setting read deadline: %v", err)
+	}
+
+	br := bufio.NewReaderSize(conn, recordHeaderLen+maxPlaintext)
+
+	var serverName string
+	isTLS, err := startsWithHandshake(br)
+	if err != nil {
+		log.Debugf("Error while reading from %s: %v", conn.RemoteAddr(), err)
+		conn.Close()
+		return
+	}
+
+	if isTLS {
+		if err := conn.SetReadDeadline(time.Now().Add(clientHelloTimeout)); err != nil {
+			log.Errorf("Error while setting read deadline: %v", err)
+		}
+
+		serverName, err = clientHelloServerName(br)
+		if err != nil {
+			log.Debugf("Error while reading the TLS ClientHello from %s: %v", conn.RemoteAddr(), err)
+			conn.Close()
+			return
+		}
+	}
+
+	// Reset the read deadline, the handler is responsible for its own timeouts.
+	if err := conn.SetReadDeadline(time.Time{}); err != nil {
+		log.Errorf("Error while resetting read deadline: %v", err)
+	}
+
+	peeked := &peekedConn{WriteCloser: conn, reader: br}
+
+	rt := r.match(serverName, isTLS)
+	if rt == nil {
+		log.Debugf("No TCP route for server name %q from %s", serverName, conn.RemoteAddr())
+		conn.Close()
+		return
+	}
+
+	if isTLS && r.terminates(rt) {
+		rt.handler.ServeTCP(tls.Server(peeked, r.tlsConfig))
+		return
+	}
+
+	rt.handler.ServeTCP(peeked)
+}
+
+// terminates returns whether the TLS connections of the route are terminated by the router.
+func (r *Router) terminates(rt *route) bool {
+	return rt != nil && !rt.passthrough && r.tlsConfig != nil
+}
+
+func (r *Router) match(serverName string, isTLS bool) *route {
+	if isTLS && len(serverName) > 0 {
+		if rt, ok := r.routes[types.CanonicalDomain(serverName)]; ok {
+			return rt
+		}
+	}
+	return r.catchAll
+}
+
+// startsWithHandshake peeks the first byte of the connection, and returns whether it starts a TLS handshake.
+// A client not speaking first within the read deadline is not a TLS client.
+func startsWithHandshake(br *bufio.Reader) (bool, error) {
+	hdr, err := br.Peek(1)
+	if err != nil {
+		if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return hdr[0] == recordTypeHandshake, nil
+}
+
+// clientHelloServerName peeks the TLS ClientHello of the connection, and returns the server name it holds.
+func clientHelloServerName(br *bufio.Reader) (string, error) {
+	hdr, err := br.Peek(recordHeaderLen)
+	if err != nil {
+		return "", err
+	}
+
+	recordLen := int(hdr[3])<<8 | int(hdr[4])
+	helloBytes, err := br.Peek(recordHeaderLen + recordLen)
+	if err != nil {
+		return "", err
+	}
+
+	var serverName string
+	err = tls.Server(sniSniffConn{r: bytes.NewReader(helloBytes)}, &tls.Config{
+		GetConfigForClient: func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+			serverName = hello.ServerName
+			return nil, errSNISniffed
+		},
+	}).Handshake()
+
+	if err != nil && err != errSNISniffed {
+		return "", err
+	}
+
+	return serverName, nil
+}
+
+// peekedConn is a connection whose first bytes have already been read in a buffer.
+type peekedConn struct {
+	WriteCloser
+	reader io.Reader
+}
+
+func (c *peekedConn) Read(p []byte) (int, error) {
+	return c.reader.Read(p)
+}
+
+// sniSniffConn is a read-only connection used to parse a ClientHello.
+type sniSniffConn struct {
+	r        io.Reader
+	net.Conn // nil, the methods must not be called
+}
+
+func (c sniSniffConn) Read(p []byte) (int, error) {
+	return c.r.Read(p)
+}
+
+func (sniSniffConn) Write(p []byte) (int, error) {
+	return 0, io.EOF
+}
@@ -0,0 +1,241 @@
+package tcp
+
+import (
+	"crypto/tls"
+	"io"
+	"io/ioutil"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/pteich/traefik/tls/generate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouterPassthrough(t *testing.T) {
+	cert, err := generate.DefaultCertificate()
+	require.NoError(t, err)
+
+	router := NewRouter()
+
+	// The TLS connection is terminated by the route handler itself.
+	err = router.AddRoute("foo.bar", HandlerFunc(func(conn WriteCloser) {
+		defer conn.Close()
+		tlsConn := tls.Server(conn, &tls.Config{Certificates: []tls.Certificate{*cert}})
+		tlsConn.Write([]byte("foo"))
+		tlsConn.Close()
+	}))
+	require.NoError(t, err)
+
+	err = router.AddRoute(CatchAllServerName, HandlerFunc(func(conn WriteCloser) {
+		conn.Close()
+	}))
+	require.NoError(t, err)
+
+	addr := serveRouter(t, router)
+
+	assert.Equal(t, "foo", dialTLS(t, addr, "Foo.Bar"))
+	assert.Equal(t, "", dialTLS(t, addr, "other.bar"))
+}
+
+func TestRouterTermination(t *testing.T) {
+	cert, err := generate.DefaultCertificate()
+	require.NoError(t, err)
+
+	router := NewRouter()
+	router.SetTLSConfig(&tls.Config{Certificates: []tls.Certificate{*cert}})
+
+	for _, serverName := range []string{"foo.bar", "test.bar"} {
+		response := []byte(serverName)
+		err = router.AddRoute(serverName, HandlerFunc(func(conn WriteCloser) {
+			defer conn.Close()
+			_, isTLS := conn.(*tls.Conn)
+			assert.True(t, isTLS)
+			conn.Write(response)
+		}))
+		require.NoError(t, err)
+	}
+
+	addr := serveRouter(t, router)
+
+	assert.Equal(t, "foo.bar", dialTLS(t, addr, "foo.bar"))
+	assert.Equal(t, "test.bar", dialTLS(t, addr, "test.bar"))
+	assert.Equal(t, "", dialTLS(t, addr, "other.bar"))
+}
+
+func TestRouterCatchAllWithoutPeeking(t *testing.T) {
+	router := NewRouter()
+
+	// The server speaks first, the router must not wait for the client.
+	err := router.AddRoute(CatchAllServerName, HandlerFunc(func(conn WriteCloser) {
+		defer conn.Close()
+		conn.Write([]byte("hello"))
+	}))
+	require.NoError(t, err)
+
+	addr := serveRouter(t, router)
+
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	data, err := ioutil.ReadAll(conn)
+	require.NoError(t, err)
+
+	assert.Equal(t, "hello", string(data))
+}
+
+func TestRouterPassthroughAndTermination(t *testing.T) {
+	cert, err := generate.DefaultCertificate()
+	require.NoError(t, err)
+
+	router := NewRouter()
+	router.SetTLSConfig(&tls.Config{Certificates: []tls.Certificate{*cert}})
+
+	err = router.AddRoute("term.bar", HandlerFunc(func(conn WriteCloser) {
+		defer conn.Close()
+		_, isTLS := conn.(*tls.Conn)
+		assert.True(t, isTLS)
+		conn.Write([]byte("term"))
+	}))
+	require.NoError(t, err)
+
+	// The TLS connection is terminated by the route handler itself.
+	err = router.AddPassthroughRoute("pass.bar", HandlerFunc(func(conn WriteCloser) {
+		defer conn.Close()
+		_, isTLS := conn.(*tls.Conn)
+		assert.False(t, isTLS)
+		tlsConn := tls.Server(conn, &tls.Config{Certificates: []tls.Certificate{*cert}})
+		tlsConn.Write([]byte("pass"))
+		tlsConn.Close()
+	}))
+	require.NoError(t, err)
+
+	addr := serveRouter(t, router)
+
+	assert.Equal(t, "term", dialTLS(t, addr, "term.bar"))
+	assert.Equal(t, "pass", dialTLS(t, addr, "pass.bar"))
+}
+
+func TestRouterCatchAllServerFirst(t *testing.T) {
+	router := NewRouter()
+
+	err := router.AddRoute("foo.bar", HandlerFunc(func(conn WriteCloser) {
+		conn.Close()
+	}))
+	require.NoError(t, err)
+
+	// The server speaks first, the router hands the connection to the catch-all when the client doesn't.
+	err = router.AddRoute(CatchAllServerName, HandlerFunc(func(conn WriteCloser) {
+		defer conn.Close()
+		conn.Write([]byte("hello"))
+	}))
+	require.NoError(t, err)
+
+	addr := serveRouter(t, router)
+
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	data, err := ioutil.ReadAll(conn)
+	require.NoError(t, err)
+
+	assert.Equal(t, "hello", string(data))
+}
+
+func TestRouterCatchAllNonTLS(t *testing.T) {
+	cert, err := generate.DefaultCertificate()
+	require.NoError(t, err)
+
+	router := NewRouter()
+	router.SetTLSConfig(&tls.Config{Certificates: []tls.Certificate{*cert}})
+
+	err = router.AddRoute("foo.bar", HandlerFunc(func(conn WriteCloser) {
+		conn.Close()
+	}))
+	require.NoError(t, err)
+
+	// The peeked bytes are still read by the catch-all.
+	err = router.AddRoute(CatchAllServerName, HandlerFunc(func(conn WriteCloser) {
+		defer conn.Close()
+		_, isTLS := conn.(*tls.Conn)
+		assert.False(t, isTLS)
+
+		data := make([]byte, 4)
+		_, err := io.ReadFull(conn, data)
+		assert.NoError(t, err)
+		conn.Write(data)
+	}))
+	require.NoError(t, err)
+
+	addr := serveRouter(t, router)
+
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.Write([]byte("ping"))
+	require.NoError(t, err)
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	data, err := ioutil.ReadAll(conn)
+	require.NoError(t, err)
+
+	assert.Equal(t, "ping", string(data))
+}
+
+func TestRouterAddRouteConflict(t *testing.T) {
+	router := NewRouter()
+	handler := HandlerFunc(func(conn WriteCloser) {})
+
+	require.NoError(t, router.AddRoute("foo.bar", handler))
+	assert.Error(t, router.AddRoute("Foo.Bar", handler))
+
+	assert.Error(t, router.AddPassthroughRoute("foo.bar", handler))
+
+	require.NoError(t, router.AddRoute(CatchAllServerName, handler))
+	assert.Error(t, router.AddRoute(CatchAllServerName, handler))
+}
+
+func serveRouter(t *testing.T, router *Router) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go router.ServeTCP(conn.(WriteCloser))
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func dialTLS(t *testing.T, addr string, serverName string) string {
+	t.Helper()
+
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: 5 * time.Second}, "tcp", addr, &tls.Config{
+		ServerName:         serverName,
+		InsecureSkipVerify: true,
+	})
+	if err != nil {
+		// the connection has been closed by the router
+		return ""
+	}
+	defer conn.Close()
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	data, _ := ioutil.ReadAll(conn)
+
+	return string(data)
+}
@@ -0,0 +1,32 @@
+package tcp
+
+import (
+	"github.com/pteich/traefik/safe"
+)
+
+// HandlerSwitcher allows hot switching of the TCP router.
+type HandlerSwitcher struct {
+	router *safe.Safe
+}
+
+// NewHandlerSwitcher builds a new instance of HandlerSwitcher
+func NewHandlerSwitcher(router *Router) *HandlerSwitcher {
+	return &HandlerSwitcher{
+		router: safe.New(router),
+	}
+}
+
+// ServeTCP serves the connection with the current router.
+func (hs *HandlerSwitcher) ServeTCP(conn WriteCloser) {
+	hs.GetHandler().ServeTCP(conn)
+}
+
+// GetHandler returns the current router.
+func (hs *HandlerSwitcher) GetHandler() *Router {
+	return hs.router.Get().(*Router)
+}
+
+// UpdateHandler safely updates the current router with a new one.
+func (hs *HandlerSwitcher) UpdateHandler(router *Router) {
+	hs.router.Set(router)
+}
@@ -0,0 +1,97 @@
+package tls
+
+import (
+	"crypto/tls"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pteich/traefik/log"
+)
+
+// ClientCertificate is a certificate presented by Traefik as a client, e.g. to the backend servers requiring mutual TLS.
+// The certificate and the key given as files are reloaded when they change on disk.
+type ClientCertificate struct {
+	Certificate
+
+	mutex       sync.Mutex
+	certificate *tls.Certificate
+	modTimes    [2]time.Time
+}
+
+// NewClientCertificate loads a new ClientCertificate.
+func NewClientCertificate(certFile, keyFile FileOrContent) (*ClientCertificate, error) {
+	c := &ClientCertificate{
+		Certificate: Certificate{
+			CertFile: certFile,
+			KeyFile:  keyFile,
+		},
+	}
+
+	c.modTimes = c.fileModTimes()
+	certificate, err := c.load()
+	if err != nil {
+		return nil, err
+	}
+	c.certificate = certificate
+
+	return c, nil
+}
+
+// GetClientCertificate returns the certificate, reloaded first if its files changed.
+// It is meant to be used as the GetClientCertificate callback of a tls.Config.
+func (c *ClientCertificate) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	modTimes := c.fileModTimes()
+	if modTimes == c.modTimes {
+		return c.certificate, nil
+	}
+
+	// The modification times are updated even if the reload fails, so it is not retried for each handshake
+	// (e.g. a certificate written before its key is reloaded again once the key is written).
+	c.modTimes = modTimes
+
+	certificate, err := c.load()
+	if err != nil {
+		log.Errorf("Unable to reload the client certificate %s, keeping the previous one: %v", c.CertFile, err)
+		return c.certificate, nil
+	}
+
+	log.Debugf("Client certificate %s reloaded", c.CertFile)
+	c.certificate = certificate
+
+	return c.certificate, nil
+}
+
+func (c *ClientCertificate) load() (*tls.Certificate, error) {
+	certContent, err := c.CertFile.Read()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read CertFile : %v", err)
+	}
+
+	keyContent, err := c.KeyFile.Read()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read KeyFile : %v", err)
+	}
+
+	certificate, err := tls.X509KeyPair(certContent, keyContent)
+	if err != nil {
+		return nil, fmt.Errorf("unable to generate TLS certificate : %v", err)
+	}
+
+	return &certificate, nil
+}
+
+// fileModTimes returns the modification times of the certificate and key files, zero for the contents.
+func (c *ClientCertificate) fileModTimes() [2]time.Time {
+	var modTimes [2]time.Time
+	for i, f := range []FileOrContent{c.CertFile, c.KeyFile} {
+		if info, err := os.Stat(f.String()); err == nil {
+			modTimes[i] = info.ModTime()
+		}
+	}
+	return modTimes
+}
@@ -0,0 +1,74 @@
+package tls
+
+import (
+	"crypto/x509"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pteich/traefik/tls/generate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientCertificateReload(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "traefik_")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	certFile := filepath.Join(tempDir, "client.crt")
+	keyFile := filepath.Join(tempDir, "client.key")
+	writeKeyPair(t, certFile, keyFile, "first.example.com", time.Now().Add(-time.Hour))
+
+	clientCertificate, err := NewClientCertificate(FileOrContent(certFile), FileOrContent(keyFile))
+	require.NoError(t, err)
+	assertClientCertificateDomain(t, clientCertificate, "first.example.com")
+
+	writeKeyPair(t, certFile, keyFile, "second.example.com", time.Now())
+	assertClientCertificateDomain(t, clientCertificate, "second.example.com")
+
+	// An invalid certificate on disk keeps the previous one.
+	require.NoError(t, ioutil.WriteFile(keyFile, []byte("invalid"), 0600))
+	require.NoError(t, os.Chtimes(keyFile, time.Now().Add(time.Hour), time.Now().Add(time.Hour)))
+	assertClientCertificateDomain(t, clientCertificate, "second.example.com")
+}
+
+func TestClientCertificateContent(t *testing.T) {
+	certPEM, keyPEM, err := generate.KeyPair("content.example.com", time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	clientCertificate, err := NewClientCertificate(FileOrContent(certPEM), FileOrContent(keyPEM))
+	require.NoError(t, err)
+	assertClientCertificateDomain(t, clientCertificate, "content.example.com")
+
+	_, err = NewClientCertificate(FileOrContent(certPEM), "invalid")
+	assert.Error(t, err)
+}
+
+func writeKeyPair(t *testing.T, certFile, keyFile, domain string, modTime time.Time) {
+	t.Helper()
+
+	certPEM, keyPEM, err := generate.KeyPair(domain, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	require.NoError(t, ioutil.WriteFile(certFile, certPEM, 0600))
+	require.NoError(t, ioutil.WriteFile(keyFile, keyPEM, 0600))
+
+	// The modification times are set explicitly, as the file system may not be precise enough.
+	require.NoError(t, os.Chtimes(certFile, modTime, modTime))
+	require.NoError(t, os.Chtimes(keyFile, modTime, modTime))
+}
+
+func assertClientCertificateDomain(t *testing.T, clientCertificate *ClientCertificate, domain string) {
+	t.Helper()
+
+	certificate, err := clientCertificate.GetClientCertificate(nil)
+	require.NoError(t, err)
+	require.NotEmpty(t, certificate.Certificate)
+
+	leaf, err := x509.ParseCertificate(certificate.Certificate[0])
+	require.NoError(t, err)
+	assert.Equal(t, []string{domain}, leaf.DNSNames)
+}
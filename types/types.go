@@ -23,13 +23,72 @@ import (
 
 // Backend holds backend configuration.
 type Backend struct {
-	Servers            map[string]Server   `json:"servers,omitempty"`
-	CircuitBreaker     *CircuitBreaker     `json:"circuitBreaker,omitempty"`
-	LoadBalancer       *LoadBalancer       `json:"loadBalancer,omitempty"`
-	MaxConn            *MaxConn            `json:"maxConn,omitempty"`
-	HealthCheck        *HealthCheck        `json:"healthCheck,omitempty"`
-	Buffering          *Buffering          `json:"buffering,omitempty"`
-	ResponseForwarding *ResponseForwarding `json:"forwardingResponse,omitempty"`
+	Servers             map[string]Server    `json:"servers,omitempty"`
+	CircuitBreaker      *CircuitBreaker      `json:"circuitBreaker,omitempty"`
+	LoadBalancer        *LoadBalancer        `json:"loadBalancer,omitempty"`
+	MaxConn             *MaxConn             `json:"maxConn,omitempty"`
+	HealthCheck         *HealthCheck         `json:"healthCheck,omitempty"`
+	PassiveHealthCheck  *PassiveHealthCheck  `json:"passiveHealthCheck,omitempty"`
+	Buffering           *Buffering           `json:"buffering,omitempty"`
+	ResponseForwarding  *ResponseForwarding  `json:"forwardingResponse,omitempty"`
+	Transport           *Transport           `json:"transport,omitempty"`
+	Retry               *Retry               `json:"retry,omitempty"`
+	Hedging             *Hedging             `json:"hedging,omitempty"`
+	AdaptiveConcurrency *AdaptiveConcurrency `json:"adaptiveConcurrency,omitempty"`
+	DNSDiscovery        *DNSDiscovery        `json:"dnsDiscovery,omitempty"`
+}
+
+// Transport holds the configuration of the transport to the servers of a backend,
+// it overrides the global forwarding settings.
+type Transport struct {
+	DialTimeout           string                     `json:"dialTimeout,omitempty"`
+	ResponseHeaderTimeout string                     `json:"responseHeaderTimeout,omitempty"`
+	IdleConnTimeout       string                     `json:"idleConnTimeout,omitempty"`
+	MaxIdleConnsPerHost   int                        `json:"maxIdleConnsPerHost,omitempty"`
+	ServerName            string                     `json:"serverName,omitempty"`
+	RootCAs               traefiktls.FilesOrContents `json:"rootCAs,omitempty"`
+	ClientCert            traefiktls.FileOrContent   `json:"clientCert,omitempty"`
+	ClientKey             traefiktls.FileOrContent   `json:"clientKey,omitempty"`
+	// InsecureSkipVerify overrides the global insecureSkipVerify, when set.
+	InsecureSkipVerify *bool `json:"insecureSkipVerify,omitempty"`
+	DisableHTTP2       bool  `json:"disableHTTP2,omitempty"`
+}
+
+// Retry holds the retry policy of a backend, it overrides the global retry configuration.
+type Retry struct {
+	Attempts        int      `json:"attempts,omitempty"`
+	StatusCodes     []string `json:"statusCodes,omitempty"`
+	Methods         []string `json:"methods,omitempty"`
+	InitialInterval string   `json:"initialInterval,omitempty"`
+	MaxInterval     string   `json:"maxInterval,omitempty"`
+	PerTryTimeout   string   `json:"perTryTimeout,omitempty"`
+	BudgetPercent   int      `json:"budgetPercent,omitempty"`
+}
+
+// Hedging holds the hedging configuration of a backend,
+// a second request is sent to another server when the first one hasn't answered within a delay.
+type Hedging struct {
+	Delay      string `json:"delay,omitempty"`
+	Percentile int    `json:"percentile,omitempty"`
+}
+
+// AdaptiveConcurrency holds the adaptive concurrency limit configuration,
+// the number of requests in flight to the backend adapts to its latency.
+type AdaptiveConcurrency struct {
+	Algorithm        string `json:"algorithm,omitempty"`
+	InitialLimit     int    `json:"initialLimit,omitempty"`
+	MinLimit         int    `json:"minLimit,omitempty"`
+	MaxLimit         int    `json:"maxLimit,omitempty"`
+	LatencyThreshold string `json:"latencyThreshold,omitempty"`
+}
+
+// DNSDiscovery holds the DNS discovery configuration of the servers of a backend,
+// a SRV name, or a A/AAAA name when the port is set, resolved periodically.
+type DNSDiscovery struct {
+	Name     string `json:"name,omitempty"`
+	Port     int    `json:"port,omitempty"`
+	Scheme   string `json:"scheme,omitempty"`
+	Interval string `json:"interval,omitempty"`
 }
 
 // ResponseForwarding holds configuration for the forward of the response
@@ -39,8 +98,11 @@ type ResponseForwarding struct {
 
 // MaxConn holds maximum connection configuration
 type MaxConn struct {
-	Amount        int64  `json:"amount,omitempty"`
-	ExtractorFunc string `json:"extractorFunc,omitempty"`
+	Amount         int64  `json:"amount,omitempty"`
+	ExtractorFunc  string `json:"extractorFunc,omitempty"`
+	QueueLength    int    `json:"queueLength,omitempty"`
+	QueueTimeout   string `json:"queueTimeout,omitempty"`
+	PriorityHeader string `json:"priorityHeader,omitempty"`
 }
 
 // LoadBalancer holds load balancing configuration.
@@ -48,6 +110,8 @@ type LoadBalancer struct {
 	Method     string      `json:"method,omitempty"`
 	Sticky     bool        `json:"sticky,omitempty"` // Deprecated: use Stickiness instead
 	Stickiness *Stickiness `json:"stickiness,omitempty"`
+	HashKey    string      `json:"hashKey,omitempty"`
+	SlowStart  string      `json:"slowStart,omitempty"`
 }
 
 // Stickiness holds sticky session configuration.
@@ -80,18 +144,35 @@ type WhiteList struct {
 
 // HealthCheck holds HealthCheck configuration
 type HealthCheck struct {
-	Scheme   string            `json:"scheme,omitempty"`
-	Path     string            `json:"path,omitempty"`
-	Port     int               `json:"port,omitempty"`
-	Interval string            `json:"interval,omitempty"`
-	Hostname string            `json:"hostname,omitempty"`
-	Headers  map[string]string `json:"headers,omitempty"`
+	Type        string            `json:"type,omitempty"`
+	Service     string            `json:"service,omitempty"`
+	Scheme      string            `json:"scheme,omitempty"`
+	Path        string            `json:"path,omitempty"`
+	Port        int               `json:"port,omitempty"`
+	Interval    string            `json:"interval,omitempty"`
+	Hostname    string            `json:"hostname,omitempty"`
+	Headers     map[string]string `json:"headers,omitempty"`
+	Method      string            `json:"method,omitempty"`
+	StatusCodes []string          `json:"statusCodes,omitempty"`
+	BodyRegex   string            `json:"bodyRegex,omitempty"`
+	Timeout     string            `json:"timeout,omitempty"`
+	Rise        int               `json:"rise,omitempty"`
+	Fall        int               `json:"fall,omitempty"`
+}
+
+// PassiveHealthCheck holds the passive health check configuration,
+// the servers are ejected on consecutive errors of the live traffic.
+type PassiveHealthCheck struct {
+	ConsecutiveErrors  int    `json:"consecutiveErrors,omitempty"`
+	EjectionTime       string `json:"ejectionTime,omitempty"`
+	MaxEjectionPercent int    `json:"maxEjectionPercent,omitempty"`
 }
 
 // Server holds server configuration.
 type Server struct {
-	URL    string `json:"url,omitempty"`
-	Weight int    `json:"weight"`
+	URL      string `json:"url,omitempty"`
+	Weight   int    `json:"weight"`
+	Priority int    `json:"priority,omitempty"`
 }
 
 // Route holds route configuration.
@@ -190,6 +271,9 @@ func (h *Headers) HasSecureHeadersDefined() bool {
 type Frontend struct {
 	EntryPoints          []string              `json:"entryPoints,omitempty" hash:"ignore"`
 	Backend              string                `json:"backend,omitempty"`
+	Backends             []WeightedBackend     `json:"backends,omitempty"`
+	BackendsStickiness   *Stickiness           `json:"backendsStickiness,omitempty"`
+	Mirror               *Mirror               `json:"mirror,omitempty"`
 	Routes               map[string]Route      `json:"routes,omitempty" hash:"ignore"`
 	PassHostHeader       bool                  `json:"passHostHeader,omitempty"`
 	PassTLSCert          bool                  `json:"passTLSCert,omitempty"` // Deprecated use PassTLSClientCert instead
@@ -203,6 +287,41 @@ type Frontend struct {
 	RateLimit            *RateLimit            `json:"ratelimit,omitempty"`
 	Redirect             *Redirect             `json:"redirect,omitempty"`
 	Auth                 *Auth                 `json:"auth,omitempty"`
+	// TLSPassthrough makes a TCP frontend forward the TLS connections as is, even when its entry point terminates TLS.
+	TLSPassthrough bool `json:"tlsPassthrough,omitempty"`
+}
+
+// WeightedBackend holds a backend of a frontend splitting its traffic between several backends.
+type WeightedBackend struct {
+	Name   string `json:"name,omitempty"`
+	Weight int    `json:"weight"`
+}
+
+// Mirror holds the mirroring configuration of a frontend.
+type Mirror struct {
+	Backend string `json:"backend,omitempty"`
+	// Percent is the percentage of the mirrored requests, 100 when not set.
+	Percent     *int  `json:"percent,omitempty"`
+	MaxBodySize int64 `json:"maxBodySize,omitempty"`
+}
+
+// GetBackends returns the backends used by the frontend.
+// The Backends list takes precedence over the Backend name.
+func (f *Frontend) GetBackends() []WeightedBackend {
+	if len(f.Backends) > 0 {
+		return f.Backends
+	}
+	return []WeightedBackend{{Name: f.Backend, Weight: 1}}
+}
+
+// HasBackend returns true if the frontend sends traffic to the given backend.
+func (f *Frontend) HasBackend(name string) bool {
+	for _, backend := range f.GetBackends() {
+		if backend.Name == name {
+			return true
+		}
+	}
+	return false
 }
 
 // Hash returns the hash value of a Frontend struct.
@@ -232,11 +351,20 @@ const (
 	Wrr LoadBalancerMethod = iota
 	// Drr = Dynamic Round Robin
 	Drr
+	// LeastConn = Least Connections
+	LeastConn
+	// Hash = Consistent Hash
+	Hash
+	// PeakEwma = Peak Exponentially Weighted Moving Average of the latency
+	PeakEwma
 )
 
 var loadBalancerMethodNames = []string{
 	"Wrr",
 	"Drr",
+	"LeastConn",
+	"Hash",
+	"PeakEwma",
 }
 
 // NewLoadBalancerMethod create a new LoadBalancerMethod from a given LoadBalancer.
@@ -0,0 +1,184 @@
+package udp
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pteich/traefik/log"
+	"github.com/pteich/traefik/safe"
+)
+
+// DefaultSessionTimeout is the default duration after which an idle session is closed.
+const DefaultSessionTimeout = 30 * time.Second
+
+const maxDatagramSize = 65535
+
+// Forwarder forwards the datagrams received on a connection to the servers of a load balancer.
+// Each client address is bound to a server for the duration of a session,
+// which is closed when no datagram has been exchanged during the session timeout.
+type Forwarder struct {
+	conn     net.PacketConn
+	timeout  time.Duration
+	balancer *safe.Safe
+
+	lock     sync.Mutex
+	sessions map[string]*session
+}
+
+// NewForwarder creates a new Forwarder reading the datagrams from conn.
+func NewForwarder(conn net.PacketConn, timeout time.Duration) *Forwarder {
+	if timeout <= 0 {
+		timeout = DefaultSessionTimeout
+	}
+
+	return &Forwarder{
+		conn:     conn,
+		timeout:  timeout,
+		balancer: safe.New(nil),
+		sessions: make(map[string]*session),
+	}
+}
+
+// SetLoadBalancer safely updates the load balancer used to create the new sessions.
+// The ongoing sessions keep their server until they are closed.
+func (f *Forwarder) SetLoadBalancer(lb *LoadBalancer) {
+	f.balancer.Set(lb)
+}
+
+// Serve forwards the datagrams until the connection is closed.
+func (f *Forwarder) Serve() error {
+	buf := make([]byte, maxDatagramSize)
+	for {
+		n, clientAddr, err := f.conn.ReadFrom(buf)
+		if err != nil {
+			if netErr, ok := err.(net.Error); ok && netErr.Temporary() {
+				log.Debugf("Temporary error while reading UDP datagram: %v", err)
+				continue
+			}
+			return err
+		}
+
+		sess, err := f.getSession(clientAddr)
+		if err != nil {
+			log.Debugf("Error while creating UDP session for %s: %v", clientAddr, err)
+			continue
+		}
+
+		if _, err := sess.backend.Write(buf[:n]); err != nil {
+			log.Debugf("Error while forwarding UDP datagram from %s to %s: %v", clientAddr, sess.backend.RemoteAddr(), err)
+			continue
+		}
+		sess.touch()
+	}
+}
+
+// Close closes the connection and all the sessions.
+func (f *Forwarder) Close() error {
+	err := f.conn.Close()
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	for key, sess := range f.sessions {
+		sess.backend.Close()
+		delete(f.sessions, key)
+	}
+
+	return err
+}
+
+func (f *Forwarder) getSession(clientAddr net.Addr) (*session, error) {
+	key := clientAddr.String()
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if sess, ok := f.sessions[key]; ok {
+		return sess, nil
+	}
+
+	lb, _ := f.balancer.Get().(*LoadBalancer)
+	if lb == nil {
+		return nil, errors.New("no UDP backend")
+	}
+
+	address, err := lb.NextAddress()
+	if err != nil {
+		return nil, err
+	}
+
+	backend, err := net.Dial("udp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debugf("Creating UDP session from %s to %s", clientAddr, address)
+
+	sess := &session{clientAddr: clientAddr, backend: backend}
+	sess.touch()
+	f.sessions[key] = sess
+
+	safe.Go(func() {
+		f.reply(key, sess)
+	})
+
+	return sess, nil
+}
+
+// reply sends the datagrams of the server back to the client, until the session expires.
+func (f *Forwarder) reply(key string, sess *session) {
+	defer f.closeSession(key, sess)
+
+	buf := make([]byte, maxDatagramSize)
+	for {
+		if err := sess.backend.SetReadDeadline(sess.lastActivity().Add(f.timeout)); err != nil {
+			log.Debugf("Error while setting UDP read deadline: %v", err)
+			return
+		}
+
+		n, err := sess.backend.Read(buf)
+		if err != nil {
+			if netErr, ok := err.(net.Error); ok && netErr.Timeout() && time.Since(sess.lastActivity()) < f.timeout {
+				// the client sent datagrams in the meantime
+				continue
+			}
+			return
+		}
+
+		if _, err := f.conn.WriteTo(buf[:n], sess.clientAddr); err != nil {
+			log.Debugf("Error while sending UDP datagram to %s: %v", sess.clientAddr, err)
+			return
+		}
+		sess.touch()
+	}
+}
+
+func (f *Forwarder) closeSession(key string, sess *session) {
+	log.Debugf("Closing UDP session from %s to %s", sess.clientAddr, sess.backend.RemoteAddr())
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.sessions[key] == sess {
+		delete(f.sessions, key)
+	}
+	sess.backend.Close()
+}
+
+// session binds a client to a server.
+type session struct {
+	clientAddr net.Addr
+	backend    net.Conn
+	lastActive int64
+}
+
+func (s *session) touch() {
+	atomic.StoreInt64(&s.lastActive, time.Now().UnixNano())
+}
+
+func (s *session) lastActivity() time.Time {
+	return time.Unix(0, atomic.LoadInt64(&s.lastActive))
+}
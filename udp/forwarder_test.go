@@ -0,0 +1,136 @@
+package udp
+
+import (
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+func TestForwarder(t *testing.T) {
+	lb, err := NewLoadBalancer()
+	require.NoError(t, err)
+
+	require.NoError(t, lb.UpsertServer(testServerURL(t, "foo"), roundrobin.Weight(1)))
+	require.NoError(t, lb.UpsertServer(testServerURL(t, "bar"), roundrobin.Weight(1)))
+
+	forwarder := serveForwarder(t, time.Minute)
+	forwarder.SetLoadBalancer(lb)
+
+	addr := forwarder.conn.LocalAddr().String()
+
+	client1 := dial(t, addr)
+	client2 := dial(t, addr)
+
+	// The datagrams of a client are sent to the same server during the session.
+	response1 := exchange(t, client1, "1")
+	assert.Contains(t, []string{"foo-1", "bar-1"}, response1)
+	assert.Equal(t, response1[:3]+"-2", exchange(t, client1, "2"))
+
+	response2 := exchange(t, client2, "3")
+	assert.NotEqual(t, response1[:3], response2[:3])
+	assert.Equal(t, response2[:3]+"-4", exchange(t, client2, "4"))
+}
+
+func TestForwarderSessionTimeout(t *testing.T) {
+	lb, err := NewLoadBalancer()
+	require.NoError(t, err)
+
+	require.NoError(t, lb.UpsertServer(testServerURL(t, "foo"), roundrobin.Weight(1)))
+
+	forwarder := serveForwarder(t, 100*time.Millisecond)
+	forwarder.SetLoadBalancer(lb)
+
+	client := dial(t, forwarder.conn.LocalAddr().String())
+	assert.Equal(t, "foo-1", exchange(t, client, "1"))
+	assert.Equal(t, 1, sessionsCount(forwarder))
+
+	assert.Eventually(t, func() bool {
+		return sessionsCount(forwarder) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, "foo-2", exchange(t, client, "2"))
+}
+
+func TestForwarderNoLoadBalancer(t *testing.T) {
+	forwarder := serveForwarder(t, time.Minute)
+
+	client := dial(t, forwarder.conn.LocalAddr().String())
+	_, err := client.Write([]byte("1"))
+	require.NoError(t, err)
+
+	require.NoError(t, client.SetReadDeadline(time.Now().Add(100*time.Millisecond)))
+	_, err = client.Read(make([]byte, maxDatagramSize))
+	assert.Error(t, err)
+	assert.Equal(t, 0, sessionsCount(forwarder))
+}
+
+func serveForwarder(t *testing.T, timeout time.Duration) *Forwarder {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	forwarder := NewForwarder(conn, timeout)
+	t.Cleanup(func() { forwarder.Close() })
+
+	go forwarder.Serve()
+
+	return forwarder
+}
+
+func sessionsCount(forwarder *Forwarder) int {
+	forwarder.lock.Lock()
+	defer forwarder.lock.Unlock()
+	return len(forwarder.sessions)
+}
+
+// testServerURL starts a UDP server answering every datagram with the prefix followed by the datagram.
+func testServerURL(t *testing.T, prefix string) *url.URL {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, maxDatagramSize)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			conn.WriteTo([]byte(prefix+"-"+string(buf[:n])), addr)
+		}
+	}()
+
+	return &url.URL{Scheme: "udp", Host: conn.LocalAddr().String()}
+}
+
+func dial(t *testing.T, addr string) net.Conn {
+	t.Helper()
+
+	conn, err := net.Dial("udp", addr)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+func exchange(t *testing.T, conn net.Conn, data string) string {
+	t.Helper()
+
+	_, err := conn.Write([]byte(data))
+	require.NoError(t, err)
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	buf := make([]byte, maxDatagramSize)
+	n, err := conn.Read(buf)
+	require.NoError(t, err)
+
+	return string(buf[:n])
+}
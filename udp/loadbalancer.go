@@ -0,0 +1,35 @@
+package udp
+
+import (
+	"net/http"
+
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+// LoadBalancer selects the UDP servers, using the weighted round robin of oxy.
+// The servers are identified by URLs whose host holds the address to send the datagrams to (e.g. udp://10.0.0.1:53),
+// so the load balancer can be managed like the HTTP ones.
+// The addresses should be resolved beforehand, the sessions being created by the single reading loop of the forwarder.
+type LoadBalancer struct {
+	*roundrobin.RoundRobin
+}
+
+// NewLoadBalancer creates a new LoadBalancer.
+func NewLoadBalancer() (*LoadBalancer, error) {
+	rr, err := roundrobin.New(http.NotFoundHandler())
+	if err != nil {
+		return nil, err
+	}
+
+	return &LoadBalancer{RoundRobin: rr}, nil
+}
+
+// NextAddress returns the address of the next server.
+func (b *LoadBalancer) NextAddress() (string, error) {
+	server, err := b.NextServer()
+	if err != nil {
+		return "", err
+	}
+
+	return server.Host, nil
+}